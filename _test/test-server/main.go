@@ -18,6 +18,7 @@ import (
 	field "github.com/quickfixgo/quickfix/gen/field"
 	tag "github.com/quickfixgo/quickfix/gen/tag"
 	filelog "github.com/quickfixgo/quickfix/log/file"
+	"github.com/quickfixgo/quickfix/store/bolt"
 	"github.com/quickfixgo/quickfix/store/file"
 	"github.com/quickfixgo/quickfix/store/mongo"
 )
@@ -166,6 +167,13 @@ func main() {
 		appSettings.GlobalSettings().Set(config.DynamicSessions, "Y")
 
 		acceptor, err = quickfix.NewAcceptor(app, file.NewStoreFactory(appSettings), appSettings, fileLogFactory)
+	case "BOLT":
+		boltStoreRootPath := path.Join(os.TempDir(), fmt.Sprintf("BoltStoreTestSuite-%d", os.Getpid()))
+		boltStorePath := path.Join(boltStoreRootPath, fmt.Sprintf("%d", time.Now().UnixNano()))
+		appSettings.GlobalSettings().Set(config.BoltStorePath, boltStorePath)
+		appSettings.GlobalSettings().Set(config.DynamicSessions, "Y")
+
+		acceptor, err = quickfix.NewAcceptor(app, bolt.NewStoreFactory(appSettings), appSettings, fileLogFactory)
 	case "MEMORY":
 		fallthrough
 	default:
@@ -18,7 +18,10 @@ package quickfix
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	"net"
 	"runtime/debug"
@@ -28,6 +31,7 @@ import (
 	proxyproto "github.com/pires/go-proxyproto"
 
 	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
 )
 
 // Acceptor accepts connections from FIX clients and manages the associated sessions.
@@ -37,8 +41,13 @@ type Acceptor struct {
 	logFactory            LogFactory
 	storeFactory          MessageStoreFactory
 	globalLog             Log
+	sessionsLock          sync.RWMutex
+	sessionSettings       map[SessionID]*SessionSettings
 	sessions              map[SessionID]*session
+	sessionDone           map[SessionID]chan interface{}
 	sessionGroup          sync.WaitGroup
+	running               bool
+	socketAcceptHost      string
 	listenerShutdown      sync.WaitGroup
 	dynamicSessions       bool
 	dynamicQualifier      bool
@@ -49,6 +58,15 @@ type Acceptor struct {
 	listeners             map[string]net.Listener
 	connectionValidator   ConnectionValidator
 	tlsConfig             *tls.Config
+	certificates          *certificateLoader
+	adminServer           *adminServer
+
+	// Socket options from the default settings, applied to every connection.
+	socketOptions socketOptions
+
+	// Limits from the default settings, applied to the first message of a connection.
+	inboundLimits  inboundLimitSettings
+	parserSettings internal.SessionSettings
 	sessionFactory
 }
 
@@ -68,9 +86,19 @@ func (a *Acceptor) Start() (err error) {
 		}
 	}
 
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	if a.sessionSettings == nil {
+		a.sessionSettings = a.settings.SessionSettings()
+	}
+	if a.sessionDone == nil {
+		a.sessionDone = make(map[SessionID]chan interface{})
+	}
+
 	a.sessionHostPort = make(map[SessionID]int)
 	a.listeners = make(map[string]net.Listener)
-	for sessionID, sessionSettings := range a.settings.SessionSettings() {
+	for sessionID, sessionSettings := range a.sessionSettings {
 		if sessionSettings.HasSetting(config.SocketAcceptPort) {
 			if a.sessionHostPort[sessionID], err = sessionSettings.IntSetting(config.SocketAcceptPort); err != nil {
 				return
@@ -81,10 +109,11 @@ func (a *Acceptor) Start() (err error) {
 		address := net.JoinHostPort(socketAcceptHost, strconv.Itoa(a.sessionHostPort[sessionID]))
 		a.listeners[address] = nil
 	}
+	a.socketAcceptHost = socketAcceptHost
 
 	if a.tlsConfig == nil {
 		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(a.settings.GlobalSettings()); err != nil {
+		if tlsConfig, a.certificates, err = loadTLSConfigWithCertificate(a.settings.GlobalSettings()); err != nil {
 			return
 		}
 		a.tlsConfig = tlsConfig
@@ -97,25 +126,32 @@ func (a *Acceptor) Start() (err error) {
 		}
 	}
 
+	if a.socketOptions, err = parseSocketOptions(a.settings.GlobalSettings()); err != nil {
+		return
+	}
+	listenConfig := net.ListenConfig{KeepAlive: a.socketOptions.keepAlivePeriod}
+
+	if a.tlsConfig != nil && len(a.tlsConfig.Certificates) == 0 && a.tlsConfig.GetCertificate == nil && a.tlsConfig.GetConfigForClient == nil {
+		return errors.New("tls: neither Certificates, GetCertificate, nor GetConfigForClient set in Config")
+	}
+
 	for address := range a.listeners {
-		if a.tlsConfig != nil {
-			if a.listeners[address], err = tls.Listen("tcp", address, a.tlsConfig); err != nil {
-				return
-			}
-		} else if a.listeners[address], err = net.Listen("tcp", address); err != nil {
+		if a.listeners[address], err = listenConfig.Listen(context.Background(), "tcp", address); err != nil {
 			return
+		}
+
+		if a.tlsConfig != nil {
+			a.listeners[address] = tls.NewListener(a.listeners[address], a.tlsConfig)
 		} else if useTCPProxy {
 			a.listeners[address] = &proxyproto.Listener{Listener: a.listeners[address]}
 		}
 	}
 
-	for _, s := range a.sessions {
-		a.sessionGroup.Add(1)
-		go func(s *session) {
-			s.run()
-			a.sessionGroup.Done()
-		}(s)
+	for sessID, s := range a.sessions {
+		a.startSession(sessID, s)
 	}
+	a.running = true
+
 	if a.dynamicSessions {
 		a.dynamicSessionChan = make(chan *session)
 		a.sessionGroup.Add(1)
@@ -128,6 +164,13 @@ func (a *Acceptor) Start() (err error) {
 	for _, listener := range a.listeners {
 		go a.listenForConnections(listener)
 	}
+
+	if a.adminServer, err = newAdminServer(a.settings.GlobalSettings(), a.Registry, a.globalLog); err != nil {
+		return
+	}
+	if a.adminServer != nil {
+		err = a.adminServer.start()
+	}
 	return
 }
 
@@ -137,6 +180,10 @@ func (a *Acceptor) Stop() {
 		_ = recover() // suppress sending on closed channel error
 	}()
 
+	if a.adminServer != nil {
+		a.adminServer.stop()
+	}
+
 	for _, listener := range a.listeners {
 		listener.Close()
 	}
@@ -144,6 +191,11 @@ func (a *Acceptor) Stop() {
 	if a.dynamicSessions {
 		close(a.dynamicSessionChan)
 	}
+
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	a.running = false
 	for _, session := range a.sessions {
 		session.stop()
 	}
@@ -157,6 +209,206 @@ func (a *Acceptor) Stop() {
 	}
 }
 
+// startSession runs a static session until it is stopped, sessionsLock must be held.
+func (a *Acceptor) startSession(sessID SessionID, s *session) {
+	done := make(chan interface{})
+	a.sessionDone[sessID] = done
+
+	a.sessionGroup.Add(1)
+	go func() {
+		defer a.sessionGroup.Done()
+		defer close(done)
+		s.run()
+	}()
+}
+
+// AddSession creates a static session from settings, overlaid on the global settings of the Acceptor.
+// If the Acceptor is running the session accepts connections immediately, in which case its
+// SocketAcceptPort must be one the Acceptor is already listening on.
+func (a *Acceptor) AddSession(sessionSettings *SessionSettings) (SessionID, error) {
+	a.sessionsLock.Lock()
+	defer a.sessionsLock.Unlock()
+
+	sessionID := sessionIDFromSessionSettings(a.settings.GlobalSettings(), sessionSettings)
+	settings := a.settings.GlobalSettings().clone()
+	settings.overlay(sessionSettings)
+
+	sessID := sessionID
+	sessID.Qualifier = ""
+
+	if _, dup := a.sessions[sessID]; dup {
+		return sessionID, errDuplicateSessionID
+	}
+
+	var port int
+	if a.running {
+		var err error
+		if port, err = a.listeningPort(settings); err != nil {
+			return sessionID, err
+		}
+	}
+
+	session, err := a.createSession(sessionID, a.storeFactory, settings, a.logFactory, a.app)
+	if err != nil {
+		return sessionID, err
+	}
+	a.sessions[sessID] = session
+	a.sessionSettings[sessionID] = settings
+
+	if a.running {
+		a.sessionHostPort[sessionID] = port
+		a.startSession(sessID, session)
+	}
+
+	return sessionID, nil
+}
+
+// listeningPort returns the SocketAcceptPort of settings, or an error if the Acceptor is not listening on it.
+// a.sessionsLock must be held.
+func (a *Acceptor) listeningPort(settings *SessionSettings) (int, error) {
+	port, err := settings.IntSetting(config.SocketAcceptPort)
+	if err != nil {
+		return 0, err
+	}
+
+	address := net.JoinHostPort(a.socketAcceptHost, strconv.Itoa(port))
+	if _, ok := a.listeners[address]; !ok {
+		return 0, fmt.Errorf("acceptor is not listening on %v", address)
+	}
+	return port, nil
+}
+
+// RemoveSession logs out and disconnects the static session matching the session id, closes its store and log,
+// and unregisters it.
+// Connections of the other sessions of the Acceptor are not affected.
+func (a *Acceptor) RemoveSession(sessionID SessionID) error {
+	sessID := sessionID
+	sessID.Qualifier = ""
+
+	a.sessionsLock.Lock()
+	session, ok := a.sessions[sessID]
+	if !ok {
+		a.sessionsLock.Unlock()
+		return errUnknownSession
+	}
+
+	done := a.sessionDone[sessID]
+	delete(a.sessions, sessID)
+	delete(a.sessionDone, sessID)
+	delete(a.sessionSettings, session.sessionID)
+	delete(a.sessionHostPort, session.sessionID)
+	a.sessionsLock.Unlock()
+
+	if done != nil {
+		session.stop()
+		<-done
+	}
+	a.sessionAddr.Delete(sessID)
+
+	// The session goroutine has exited, release the store so the session can be added again.
+	if err := session.close(); err != nil {
+		a.globalLog.OnEventf("Failed to close session %v: %v", session.sessionID, err)
+	}
+
+	return a.UnregisterSession(session.sessionID)
+}
+
+// Reload brings the running Acceptor in line with settings, typically re-read from the original file with ParseSettings.
+// Static sessions are added and removed to match the session sections of settings. Changed settings of a session are
+// applied without dropping its connection, unless connection settings such as SocketAcceptPort changed, in which case
+// the session is restarted. Settings that require the Acceptor to be recreated are listed in the returned report.
+// A session added or restarted on a port the running Acceptor is not listening on is returned as an error,
+// before any session is changed.
+// Log settings are applied to running sessions when a LogFactory created from settings is given with WithReloadLogFactory.
+func (a *Acceptor) Reload(settings *Settings, opts ...ReloadOption) (*ReloadReport, error) {
+	options := newReloadOptions(opts)
+
+	a.sessionsLock.Lock()
+	if a.sessionSettings == nil {
+		a.sessionSettings = a.settings.SessionSettings()
+	}
+	current := make(map[SessionID]*SessionSettings, len(a.sessionSettings))
+	for sessionID, sessionSettings := range a.sessionSettings {
+		current[sessionID] = sessionSettings
+	}
+
+	// Sessions are only added or restarted on ports the Acceptor is listening on.
+	var check func(*SessionSettings) error
+	if a.running {
+		check = func(sessionSettings *SessionSettings) error {
+			_, err := a.listeningPort(sessionSettings)
+			return err
+		}
+	}
+
+	plan, err := planReload(a.sessionFactory, a.settings.GlobalSettings(), current, settings, options, check)
+	if err != nil {
+		a.sessionsLock.Unlock()
+		return nil, err
+	}
+	a.settings = settings
+	if options.logFactory != nil {
+		a.logFactory = options.logFactory
+	}
+	a.sessionsLock.Unlock()
+
+	return plan.apply(a)
+}
+
+func (a *Acceptor) reconfigureSession(sessionID SessionID, from *session, settings *SessionSettings) error {
+	sessID := sessionID
+	sessID.Qualifier = ""
+
+	a.sessionsLock.Lock()
+	session, ok := a.sessions[sessID]
+	if ok {
+		a.sessionSettings[sessionID] = settings
+	}
+	a.sessionsLock.Unlock()
+
+	if !ok {
+		return errUnknownSession
+	}
+	return session.reconfigure(from)
+}
+
+// dynamicSessionSettings returns the settings and the LogFactory for a new dynamic session.
+func (a *Acceptor) dynamicSessionSettings() (*SessionSettings, LogFactory) {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+	return a.settings.GlobalSettings().clone(), a.logFactory
+}
+
+// lookupSessionSettings returns the settings of the session sessID, or the default settings if it is not configured.
+// Sessions are keyed without their SessionQualifier, their settings by the full SessionID.
+func (a *Acceptor) lookupSessionSettings(sessID SessionID) *SessionSettings {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+
+	if session, ok := a.sessions[sessID]; ok {
+		if settings, ok := a.sessionSettings[session.sessionID]; ok {
+			return settings
+		}
+	}
+	return a.settings.GlobalSettings()
+}
+
+func (a *Acceptor) lookupSession(sessID SessionID) (s *session, ok bool) {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+
+	s, ok = a.sessions[sessID]
+	return
+}
+
+func (a *Acceptor) lookupHostPort(sessID SessionID) (port int, ok bool) {
+	a.sessionsLock.RLock()
+	defer a.sessionsLock.RUnlock()
+
+	port, ok = a.sessionHostPort[sessID]
+	return
+}
+
 // RemoteAddr gets remote IP address for a given session.
 func (a *Acceptor) RemoteAddr(sessionID SessionID) (net.Addr, bool) {
 	addr, ok := a.sessionAddr.Load(sessionID)
@@ -175,6 +427,21 @@ func WithAcceptorRegistry(registry *Registry) AcceptorOption {
 	}
 }
 
+// WithAcceptorMetrics sets the MetricsSink that receives measurements from the acceptor's sessions.
+func WithAcceptorMetrics(sink MetricsSink) AcceptorOption {
+	return func(a *Acceptor) {
+		a.sessionFactory.metricsSink = sink
+	}
+}
+
+// WithAcceptorAuthenticator sets the Authenticator that checks the Logon received by the acceptor's sessions.
+// It takes precedence over the LogonCredentialsFile setting.
+func WithAcceptorAuthenticator(authenticator Authenticator) AcceptorOption {
+	return func(a *Acceptor) {
+		a.sessionFactory.authenticator = authenticator
+	}
+}
+
 // NewAcceptor creates and initializes a new Acceptor.
 func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Settings, logFactory LogFactory, opts ...AcceptorOption) (a *Acceptor, err error) {
 	a = &Acceptor{
@@ -182,7 +449,9 @@ func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Se
 		storeFactory:    storeFactory,
 		settings:        settings,
 		logFactory:      logFactory,
+		sessionSettings: settings.SessionSettings(),
 		sessions:        make(map[SessionID]*session),
+		sessionDone:     make(map[SessionID]chan interface{}),
 		sessionHostPort: make(map[SessionID]int),
 		listeners:       make(map[string]net.Listener),
 	}
@@ -203,11 +472,19 @@ func NewAcceptor(app Application, storeFactory MessageStoreFactory, settings *Se
 		}
 	}
 
+	if a.inboundLimits, err = parseInboundLimitSettings(settings.GlobalSettings()); err != nil {
+		return
+	}
+
+	if err = buildParserSettings(&a.parserSettings, settings.GlobalSettings()); err != nil {
+		return
+	}
+
 	if a.globalLog, err = logFactory.Create(); err != nil {
 		return
 	}
 
-	for sessionID, sessionSettings := range settings.SessionSettings() {
+	for sessionID, sessionSettings := range a.sessionSettings {
 		sessID := sessionID
 		sessID.Qualifier = ""
 
@@ -243,23 +520,34 @@ func (a *Acceptor) invalidMessage(msg *bytes.Buffer, err error) {
 }
 
 func (a *Acceptor) handleConnection(netConn net.Conn) {
+	// Messages are read and written through conn, which reports a failed write to the read loop.
+	conn := newMonitoredConn(netConn)
 	defer func() {
 		if err := recover(); err != nil {
 			a.globalLog.OnEventf("Connection Terminated with Panic: %s", debug.Stack())
 		}
 
-		if err := netConn.Close(); err != nil {
+		if err := conn.Close(); err != nil {
 			a.globalLog.OnEvent(err.Error())
 		}
 	}()
 
-	reader := bufio.NewReader(netConn)
+	if err := a.socketOptions.apply(netConn); err != nil {
+		a.globalLog.OnEventf("Unable to set socket options for connection from %v: %v", netConn.RemoteAddr(), err)
+		return
+	}
+
+	reader := bufio.NewReader(conn)
 	parser := newParser(reader)
+	parser.configure(a.parserSettings, inboundLimitSettings{maxMessageSize: a.inboundLimits.maxMessageSize}, a.globalLog)
 
 	msgBytes, err := parser.ReadMessage()
 	if err != nil {
+		var tooLarge MessageTooLargeError
 		if err == io.EOF {
 			a.globalLog.OnEvent("Connection Terminated")
+		} else if errors.As(err, &tooLarge) {
+			a.globalLog.OnEventf("Message too large from new connection from %v: %v", netConn.RemoteAddr(), err)
 		} else {
 			a.globalLog.OnEvent(err.Error())
 		}
@@ -329,11 +617,22 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 	}
 
 	localConnectionPort := netConn.LocalAddr().(*net.TCPAddr).Port
-	if expectedPort, ok := a.sessionHostPort[sessID]; ok && expectedPort != localConnectionPort {
+	if expectedPort, ok := a.lookupHostPort(sessID); ok && expectedPort != localConnectionPort {
 		a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
 		return
 	}
 
+	// Only the client the certificate was issued to may use its session.
+	certificatePolicy, err := parseCertificateIdentityPolicy(a.lookupSessionSettings(sessID))
+	if err != nil {
+		a.globalLog.OnEventf("Unable to check the client certificate for session %v: %v", sessID, err)
+		return
+	}
+	if err := certificatePolicy.check(peerCertificates(netConn), sessID.TargetCompID); err != nil {
+		a.globalLog.OnEventf("Client certificate rejected for session %v from %v: %v", sessID, netConn.RemoteAddr(), err)
+		return
+	}
+
 	// We have a session ID and a network connection. This seems to be a good place for any custom authentication logic.
 	if a.connectionValidator != nil {
 		if err := a.connectionValidator.Validate(netConn, sessID); err != nil {
@@ -346,13 +645,14 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 		a.dynamicQualifierCount++
 		sessID.Qualifier = strconv.Itoa(a.dynamicQualifierCount)
 	}
-	session, ok := a.sessions[sessID]
+	session, ok := a.lookupSession(sessID)
 	if !ok {
 		if !a.dynamicSessions {
 			a.globalLog.OnEventf("Session %v not found for incoming message: %s", sessID, msgBytes)
 			return
 		}
-		dynamicSession, err := a.sessionFactory.createSession(sessID, a.storeFactory, a.settings.globalSettings.clone(), a.logFactory, a.app)
+		settings, logFactory := a.dynamicSessionSettings()
+		dynamicSession, err := a.sessionFactory.createSession(sessID, a.storeFactory, settings, logFactory, a.app)
 		if err != nil {
 			a.globalLog.OnEventf("Dynamic session %v failed to create: %v", sessID, err)
 			return
@@ -363,20 +663,21 @@ func (a *Acceptor) handleConnection(netConn net.Conn) {
 	}
 
 	a.sessionAddr.Store(sessID, netConn.RemoteAddr())
-	msgIn := make(chan fixIn)
-	msgOut := make(chan []byte)
+	msgIn := make(chan fixIn, session.queueSettings.inboundLimit)
+	msgOut := make(chan []byte, messageOutQueueSize)
+	writer := newWriter(conn)
 
-	if err := session.connect(msgIn, msgOut); err != nil {
+	if err := session.connect(msgIn, msgOut, netConn, "", parser, writer); err != nil {
 		a.globalLog.OnEventf("Unable to accept session %v connection: %v", sessID, err.Error())
 		return
 	}
 
 	go func() {
-		msgIn <- fixIn{msgBytes, parser.lastRead}
+		msgIn <- fixIn{bytes: msgBytes, receiveTime: parser.lastRead, breach: parser.checkLimits()}
 		readLoop(parser, msgIn, a.globalLog)
 	}()
 
-	writeLoop(netConn, msgOut, a.globalLog)
+	writer.writeLoop(msgOut)
 }
 
 func (a *Acceptor) dynamicSessionsLoop() {
@@ -439,6 +740,17 @@ func (a *Acceptor) SetConnectionValidator(validator ConnectionValidator) {
 	a.connectionValidator = validator
 }
 
+// ReloadCertificates reloads the certificate and private key of the acceptor from SocketCertificateFile and
+// SocketPrivateKeyFile, or SocketPKCS12File. New connections use the reloaded certificate, established connections are not affected.
+// The files are also reloaded when they change, calling ReloadCertificates is only needed to apply a change immediately.
+// It does nothing if the acceptor does not read its certificate from files, e.g. after SetTLSConfig.
+func (a *Acceptor) ReloadCertificates() error {
+	if a.certificates == nil {
+		return nil
+	}
+	return a.certificates.reload()
+}
+
 // SetTLSConfig allows the creator of the Acceptor to specify a fully customizable tls.Config of their choice,
 // which will be used in the Start() method.
 //
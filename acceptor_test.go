@@ -126,3 +126,29 @@ func TestAcceptor_SetTLSConfig(t *testing.T) {
 	assert.NotNil(t, conn)
 	defer conn.Close()
 }
+
+func TestAcceptor_LookupSessionSettingsQualifier(t *testing.T) {
+	settings := NewSettings()
+	settings.GlobalSettings().Set(config.SocketAcceptPort, "5001")
+
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "sender")
+	sessionSettings.Set(config.TargetCompID, "target")
+	sessionSettings.Set(config.SessionQualifier, "q1")
+	sessionSettings.Set(config.SocketCertificateIdentity, "client1")
+	_, err := settings.AddSession(sessionSettings)
+	require.NoError(t, err)
+
+	acceptor, err := NewAcceptor(EmptyApplication{}, NewMemoryStoreFactory(), settings, NewNullLogFactory())
+	require.NoError(t, err)
+
+	// Sessions are looked up by the SessionID of the first message, which has no qualifier.
+	found := acceptor.lookupSessionSettings(SessionID{BeginString: BeginStringFIX42, SenderCompID: "sender", TargetCompID: "target"})
+	identity, err := found.Setting(config.SocketCertificateIdentity)
+	require.NoError(t, err)
+	assert.Equal(t, "client1", identity)
+
+	found = acceptor.lookupSessionSettings(SessionID{BeginString: BeginStringFIX42, SenderCompID: "sender", TargetCompID: "other"})
+	assert.False(t, found.HasSetting(config.SocketCertificateIdentity))
+}
@@ -0,0 +1,391 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// adminServer is the embedded HTTP server enabled by the AdminServerAddress setting.
+//
+// Endpoints, where {id} is the url-escaped SessionID string:
+//
+//	GET  /sessions                      status of all sessions
+//	GET  /sessions/{id}                 status of one session
+//	POST /sessions/{id}/logout          send a Logout, optional "reason" form value is used as Text(58)
+//	POST /sessions/{id}/disconnect      drop the connection without a Logout
+//	POST /sessions/{id}/reset           reset sequence numbers, see Registry.ResetSession
+//	POST /sessions/{id}/seqnums         set "sender" and/or "target" next expected sequence numbers
+//	POST /sessions/{id}/resend          send a ResendRequest asking the counterparty to resend its messages
+//	                                    from "begin" to "end" (0 or absent for the last received)
+//	POST /sessions/{id}/replay          resend the stored messages from "begin" to "end" (0 or absent for the last sent)
+//	                                    with PossDupFlag, see Registry.ResendMessages
+//	GET  /sessions/{id}/messages        stored messages from "begin" to "end" (0 or absent for the last sent)
+type adminServer struct {
+	registry *Registry
+	token    string
+	log      Log
+
+	listener net.Listener
+	server   *http.Server
+	done     chan struct{}
+}
+
+// newAdminServer returns nil if the admin server is not enabled in settings.
+func newAdminServer(settings *SessionSettings, registry *Registry, log Log) (*adminServer, error) {
+	if !settings.HasSetting(config.AdminServerAddress) {
+		return nil, nil
+	}
+
+	address, err := settings.Setting(config.AdminServerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &adminServer{registry: registry, log: log}
+	if settings.HasSetting(config.AdminServerToken) {
+		if a.token, err = settings.Setting(config.AdminServerToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.token == "" && !isLoopbackAddress(address) {
+		insecure := false
+		if settings.HasSetting(config.AdminServerInsecure) {
+			if insecure, err = settings.BoolSetting(config.AdminServerInsecure); err != nil {
+				return nil, err
+			}
+		}
+
+		if !insecure {
+			return nil, errors.New("AdminServerToken is required unless AdminServerAddress is a loopback address or AdminServerInsecure is set")
+		}
+	}
+
+	a.server = &http.Server{Addr: address, Handler: a.handler(), ReadHeaderTimeout: 10 * time.Second}
+	return a, nil
+}
+
+// isLoopbackAddress returns true if the host of address is localhost or a loopback IP.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (a *adminServer) start() (err error) {
+	if a.listener, err = net.Listen("tcp", a.server.Addr); err != nil {
+		return
+	}
+
+	a.done = make(chan struct{})
+	go func() {
+		defer close(a.done)
+		if err := a.server.Serve(a.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			a.log.OnEventf("Admin server stopped: %v", err)
+		}
+	}()
+	a.log.OnEventf("Admin server listening on %v", a.listener.Addr())
+	return
+}
+
+func (a *adminServer) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.log.OnEventf("Admin server shutdown: %v", err)
+	}
+	<-a.done
+}
+
+func (a *adminServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sessions", a.listSessions)
+	mux.HandleFunc("GET /sessions/{id}", a.withSession(a.getSession))
+	mux.HandleFunc("POST /sessions/{id}/logout", a.withSession(a.logout))
+	mux.HandleFunc("POST /sessions/{id}/disconnect", a.withSession(a.disconnect))
+	mux.HandleFunc("POST /sessions/{id}/reset", a.withSession(a.reset))
+	mux.HandleFunc("POST /sessions/{id}/seqnums", a.withSession(a.setSeqNums))
+	mux.HandleFunc("POST /sessions/{id}/resend", a.withSession(a.resend))
+	mux.HandleFunc("POST /sessions/{id}/replay", a.withSession(a.replay))
+	mux.HandleFunc("GET /sessions/{id}/messages", a.withSession(a.messages))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+type adminSessionStatus struct {
+	SessionID           string    `json:"sessionID"`
+	State               string    `json:"state"`
+	LoggedOn            bool      `json:"loggedOn"`
+	Connected           bool      `json:"connected"`
+	RemoteAddr          string    `json:"remoteAddr,omitempty"`
+	Endpoint            string    `json:"endpoint,omitempty"`
+	NextSenderMsgSeqNum int       `json:"nextSenderMsgSeqNum"`
+	NextTargetMsgSeqNum int       `json:"nextTargetMsgSeqNum"`
+	HeartBtInt          int       `json:"heartBtInt"`
+	LastSentTime        time.Time `json:"lastSentTime"`
+	LastReceivedTime    time.Time `json:"lastReceivedTime"`
+	QueueDepth          int       `json:"queueDepth"`
+	InboundQueueDepth   int       `json:"inboundQueueDepth"`
+}
+
+func (a *adminServer) status(h *SessionHandle) adminSessionStatus {
+	status := adminSessionStatus{
+		SessionID:           h.SessionID().String(),
+		State:               h.State(),
+		LoggedOn:            h.IsLoggedOn(),
+		Connected:           h.IsConnected(),
+		NextSenderMsgSeqNum: h.s.store.NextSenderMsgSeqNum(),
+		NextTargetMsgSeqNum: h.s.store.NextTargetMsgSeqNum(),
+		HeartBtInt:          int(h.HeartBtInt().Seconds()),
+		LastSentTime:        h.LastSentTime(),
+		LastReceivedTime:    h.LastReceivedTime(),
+		QueueDepth:          h.QueueDepth(),
+		InboundQueueDepth:   h.InboundQueueDepth(),
+		Endpoint:            h.Endpoint(),
+	}
+	if addr := h.RemoteAddr(); addr != nil {
+		status.RemoteAddr = addr.String()
+	}
+	return status
+}
+
+func (a *adminServer) listSessions(w http.ResponseWriter, _ *http.Request) {
+	sessionIDs := a.registry.Sessions()
+	sort.Slice(sessionIDs, func(i, j int) bool { return sessionIDs[i].String() < sessionIDs[j].String() })
+
+	statuses := make([]adminSessionStatus, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		if h, err := a.registry.GetSession(sessionID); err == nil {
+			statuses = append(statuses, a.status(h))
+		}
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// withSession resolves the {id} path value to a registered session.
+func (a *adminServer) withSession(f func(http.ResponseWriter, *http.Request, *SessionHandle)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		for _, sessionID := range a.registry.Sessions() {
+			if sessionID.String() != id {
+				continue
+			}
+
+			if h, err := a.registry.GetSession(sessionID); err == nil {
+				f(w, r, h)
+				return
+			}
+		}
+		http.Error(w, errUnknownSession.Error(), http.StatusNotFound)
+	}
+}
+
+func (a *adminServer) getSession(w http.ResponseWriter, _ *http.Request, h *SessionHandle) {
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) logout(w http.ResponseWriter, r *http.Request, h *SessionHandle) {
+	reason := r.FormValue("reason")
+	h.s.log.OnEventf("Admin server: logout requested, reason %q", reason)
+	if err := a.registry.Logout(h.SessionID(), reason); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) disconnect(w http.ResponseWriter, _ *http.Request, h *SessionHandle) {
+	h.s.log.OnEvent("Admin server: disconnect requested")
+	if err := a.registry.Disconnect(h.SessionID()); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) reset(w http.ResponseWriter, _ *http.Request, h *SessionHandle) {
+	h.s.log.OnEvent("Admin server: session reset requested")
+	if err := a.registry.ResetSession(h.SessionID()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) setSeqNums(w http.ResponseWriter, r *http.Request, h *SessionHandle) {
+	sender, hasSender, err := intFormValue(r, "sender")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	target, hasTarget, err := intFormValue(r, "target")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !hasSender && !hasTarget {
+		http.Error(w, "sender or target is required", http.StatusBadRequest)
+		return
+	}
+
+	if (hasSender && sender < 1) || (hasTarget && target < 1) {
+		http.Error(w, "sequence numbers must be positive", http.StatusBadRequest)
+		return
+	}
+
+	if hasSender {
+		h.s.log.OnEventf("Admin server: setting next sender MsgSeqNum to %v", sender)
+		if err := a.registry.SetNextSenderMsgSeqNum(h.SessionID(), sender); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if hasTarget {
+		h.s.log.OnEventf("Admin server: setting next target MsgSeqNum to %v", target)
+		if err := a.registry.SetNextTargetMsgSeqNum(h.SessionID(), target); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+// seqNumRange reads the required "begin" and optional "end" form values of resend and replay.
+// It writes the error response and returns false if they are invalid.
+func seqNumRange(w http.ResponseWriter, r *http.Request) (begin, end int, ok bool) {
+	begin, hasBegin, err := intFormValue(r, "begin")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !hasBegin || begin < 1 {
+		http.Error(w, "begin must be a positive sequence number", http.StatusBadRequest)
+		return
+	}
+
+	end, _, err = intFormValue(r, "end")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	return begin, end, true
+}
+
+func (a *adminServer) resend(w http.ResponseWriter, r *http.Request, h *SessionHandle) {
+	begin, end, ok := seqNumRange(w, r)
+	if !ok {
+		return
+	}
+
+	h.s.log.OnEventf("Admin server: resend requested from %v to %v", begin, end)
+	if err := a.registry.ResendRequest(h.SessionID(), begin, end); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) replay(w http.ResponseWriter, r *http.Request, h *SessionHandle) {
+	begin, end, ok := seqNumRange(w, r)
+	if !ok {
+		return
+	}
+
+	h.s.log.OnEventf("Admin server: replay requested from %v to %v", begin, end)
+	if err := a.registry.ResendMessages(h.SessionID(), begin, end); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.status(h))
+}
+
+func (a *adminServer) messages(w http.ResponseWriter, r *http.Request, h *SessionHandle) {
+	begin, hasBegin, err := intFormValue(r, "begin")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !hasBegin {
+		begin = 1
+	}
+
+	end, _, err := intFormValue(r, "end")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if end == 0 {
+		end = h.s.store.NextSenderMsgSeqNum() - 1
+	}
+
+	msgs := []string{}
+	err = h.s.store.IterateMessages(begin, end, func(msg []byte) error {
+		msgs = append(msgs, string(msg))
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, msgs)
+}
+
+func intFormValue(r *http.Request, key string) (value int, ok bool, err error) {
+	str := r.FormValue(key)
+	if str == "" {
+		return 0, false, nil
+	}
+
+	if value, err = strconv.Atoi(str); err != nil || value < 0 {
+		return 0, false, errors.New("invalid " + key + ": " + str)
+	}
+	return value, true, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,363 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type AdminServerTestSuite struct {
+	SessionSuiteRig
+	registry *Registry
+	admin    *adminServer
+	path     string
+}
+
+func TestAdminServerTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminServerTestSuite))
+}
+
+func (s *AdminServerTestSuite) SetupTest() {
+	s.Init()
+	s.session.State = latentState{}
+	s.registry = NewRegistry()
+	s.Require().Nil(s.registry.registerSession(s.session))
+
+	settings := NewSessionSettings()
+	settings.Set(config.AdminServerAddress, "127.0.0.1:0")
+
+	var err error
+	s.admin, err = newAdminServer(settings, s.registry, nullLog{})
+	s.Require().Nil(err)
+	s.Require().NotNil(s.admin)
+
+	s.path = "/sessions/" + url.PathEscape(s.sessionID.String())
+}
+
+func (s *AdminServerTestSuite) do(method, target string, form url.Values) *httptest.ResponseRecorder {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+
+	req := httptest.NewRequest(method, target, body)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if s.admin.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.admin.token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.admin.handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *AdminServerTestSuite) TestNotConfigured() {
+	admin, err := newAdminServer(NewSessionSettings(), s.registry, nullLog{})
+	s.Nil(err)
+	s.Nil(admin)
+}
+
+func (s *AdminServerTestSuite) TestListSessions() {
+	rec := s.do("GET", "/sessions", nil)
+	s.Equal(http.StatusOK, rec.Code)
+
+	var statuses []adminSessionStatus
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&statuses))
+	s.Require().Len(statuses, 1)
+	s.Equal(s.sessionID.String(), statuses[0].SessionID)
+	s.Equal(latentState{}.String(), statuses[0].State)
+	s.Equal(1, statuses[0].NextSenderMsgSeqNum)
+	s.Equal(1, statuses[0].NextTargetMsgSeqNum)
+}
+
+func (s *AdminServerTestSuite) TestUnknownSession() {
+	rec := s.do("GET", "/sessions/"+url.PathEscape("FIX.4.2:A->B"), nil)
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *AdminServerTestSuite) TestSetSeqNums() {
+	rec := s.do("POST", s.path+"/seqnums", url.Values{"sender": {"10"}, "target": {"20"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.NextSenderMsgSeqNum(10)
+	s.NextTargetMsgSeqNum(20)
+
+	var status adminSessionStatus
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&status))
+	s.Equal(10, status.NextSenderMsgSeqNum)
+	s.Equal(20, status.NextTargetMsgSeqNum)
+
+	rec = s.do("POST", s.path+"/seqnums", url.Values{"sender": {"x"}})
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	rec = s.do("POST", s.path+"/seqnums", url.Values{"target": {"0"}})
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	rec = s.do("POST", s.path+"/seqnums", url.Values{})
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.NextTargetMsgSeqNum(20)
+}
+
+func (s *AdminServerTestSuite) TestReset() {
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextTargetMsgSeqNum()
+
+	rec := s.do("POST", s.path+"/reset", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.ExpectStoreReset()
+}
+
+func (s *AdminServerTestSuite) TestMessages() {
+	s.Require().Nil(s.store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("msg1")))
+	s.Require().Nil(s.store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("msg2")))
+	s.Require().Nil(s.store.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("msg3")))
+
+	var msgs []string
+	rec := s.do("GET", s.path+"/messages", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&msgs))
+	s.Equal([]string{"msg1", "msg2", "msg3"}, msgs)
+
+	rec = s.do("GET", s.path+"/messages?begin=2&end=2", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.Require().Nil(json.NewDecoder(rec.Body).Decode(&msgs))
+	s.Equal([]string{"msg2"}, msgs)
+}
+
+func (s *AdminServerTestSuite) TestLogoutAndDisconnectNotRunning() {
+	rec := s.do("POST", s.path+"/logout", url.Values{"reason": {"maintenance"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.Contains(rec.Body.String(), errSessionNotRunning.Error())
+
+	rec = s.do("POST", s.path+"/disconnect", nil)
+	s.Equal(http.StatusConflict, rec.Code)
+}
+
+func (s *AdminServerTestSuite) TestLogout() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+
+	s.session.admin = make(chan interface{})
+	go func() { s.session.onAdmin(<-s.session.admin) }()
+
+	rec := s.do("POST", s.path+"/logout", url.Values{"reason": {"maintenance"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.State(logoutState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "maintenance", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *AdminServerTestSuite) TestDisconnect() {
+	s.session.State = inSession{}
+	s.MockApp.On("OnLogout")
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+
+	s.session.admin = make(chan interface{})
+	go func() { s.session.onAdmin(<-s.session.admin) }()
+
+	rec := s.do("POST", s.path+"/disconnect", nil)
+	s.Equal(http.StatusOK, rec.Code)
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Disconnected()
+}
+
+func (s *AdminServerTestSuite) TestResend() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+	for i := 0; i < 5; i++ {
+		s.IncrNextTargetMsgSeqNum()
+	}
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+
+	s.session.admin = make(chan interface{})
+	go func() {
+		for req := range s.session.admin {
+			s.session.onAdmin(req)
+		}
+	}()
+	defer close(s.session.admin)
+
+	rec := s.do("POST", s.path+"/resend", url.Values{"begin": {"2"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.State(inSession{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeResendRequest), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagBeginSeqNo, 2, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagEndSeqNo, 5, s.MockApp.lastToAdmin.Body)
+
+	rec = s.do("POST", s.path+"/resend", url.Values{"begin": {"3"}, "end": {"4"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.LastToAdminMessageSent()
+	s.FieldEquals(tagBeginSeqNo, 3, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagEndSeqNo, 4, s.MockApp.lastToAdmin.Body)
+
+	rec = s.do("POST", s.path+"/resend", url.Values{})
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	// Only messages already received can be resent.
+	rec = s.do("POST", s.path+"/resend", url.Values{"begin": {"2"}, "end": {"6"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.NoMessageSent()
+
+	s.session.State = resendState{}
+	rec = s.do("POST", s.path+"/resend", url.Values{"begin": {"2"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.NoMessageSent()
+}
+
+func (s *AdminServerTestSuite) TestResendNotRunning() {
+	rec := s.do("POST", s.path+"/resend", url.Values{"begin": {"1"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.Contains(rec.Body.String(), errSessionNotRunning.Error())
+}
+
+func (s *AdminServerTestSuite) TestReplay() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.Heartbeat()))
+	s.LastToAdminMessageSent()
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+	s.NextSenderMsgSeqNum(3)
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+
+	s.session.admin = make(chan interface{})
+	go func() {
+		for req := range s.session.admin {
+			s.session.onAdmin(req)
+		}
+	}()
+	defer close(s.session.admin)
+
+	// The stored messages are sent again, the admin message as a gap fill.
+	rec := s.do("POST", s.path+"/replay", url.Values{"begin": {"1"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.State(inSession{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeSequenceReset), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagMsgSeqNum, 1, s.MockApp.lastToAdmin.Header)
+	s.FieldEquals(tagNewSeqNo, 2, s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagGapFillFlag, true, s.MockApp.lastToAdmin.Body)
+
+	s.LastToAppMessageSent()
+	s.MessageType("D", s.MockApp.lastToApp)
+	s.FieldEquals(tagMsgSeqNum, 2, s.MockApp.lastToApp.Header)
+	s.FieldEquals(tagPossDupFlag, true, s.MockApp.lastToApp.Header)
+	s.NoMessageSent()
+	s.NextSenderMsgSeqNum(3)
+
+	rec = s.do("POST", s.path+"/replay", url.Values{"begin": {"2"}, "end": {"2"}})
+	s.Equal(http.StatusOK, rec.Code)
+	s.LastToAppMessageSent()
+	s.FieldEquals(tagMsgSeqNum, 2, s.MockApp.lastToApp.Header)
+	s.NoMessageSent()
+
+	// Only messages already sent can be replayed.
+	rec = s.do("POST", s.path+"/replay", url.Values{"begin": {"1"}, "end": {"3"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.NoMessageSent()
+
+	rec = s.do("POST", s.path+"/replay", url.Values{})
+	s.Equal(http.StatusBadRequest, rec.Code)
+
+	s.session.State = resendState{}
+	rec = s.do("POST", s.path+"/replay", url.Values{"begin": {"1"}})
+	s.Equal(http.StatusConflict, rec.Code)
+	s.NoMessageSent()
+}
+
+func (s *AdminServerTestSuite) TestTokenRequiredUnlessLoopback() {
+	var tests = []struct {
+		address, token, insecure string
+		expectErr                bool
+	}{
+		{"127.0.0.1:8088", "", "", false},
+		{"localhost:8088", "", "", false},
+		{"[::1]:8088", "", "", false},
+		{"0.0.0.0:8088", "", "", true},
+		{":8088", "", "", true},
+		{"10.0.0.1:8088", "", "N", true},
+		{"0.0.0.0:8088", "secret", "", false},
+		{"0.0.0.0:8088", "", "Y", false},
+		{"0.0.0.0:8088", "", "maybe", true},
+	}
+
+	for _, test := range tests {
+		settings := NewSessionSettings()
+		settings.Set(config.AdminServerAddress, test.address)
+		if test.token != "" {
+			settings.Set(config.AdminServerToken, test.token)
+		}
+		if test.insecure != "" {
+			settings.Set(config.AdminServerInsecure, test.insecure)
+		}
+
+		_, err := newAdminServer(settings, s.registry, nullLog{})
+		s.Equal(test.expectErr, err != nil, "%v token %q insecure %q", test.address, test.token, test.insecure)
+	}
+}
+
+func (s *AdminServerTestSuite) TestToken() {
+	s.admin.token = "secret"
+
+	req := httptest.NewRequest("GET", "/sessions", nil)
+	rec := httptest.NewRecorder()
+	s.admin.handler().ServeHTTP(rec, req)
+	s.Equal(http.StatusUnauthorized, rec.Code)
+
+	rec = s.do("GET", "/sessions", nil)
+	s.Equal(http.StatusOK, rec.Code)
+}
+
+func (s *AdminServerTestSuite) TestStartStop() {
+	s.Require().Nil(s.admin.start())
+
+	resp, err := http.Get("http://" + s.admin.listener.Addr().String() + "/sessions")
+	s.Require().Nil(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Nil(resp.Body.Close())
+
+	s.admin.stop()
+}
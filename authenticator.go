@@ -0,0 +1,348 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LogonStatus is a value of SessionStatus(1409), answered to a Logon by an Authenticator.
+type LogonStatus int
+
+// LogonStatus values.
+const (
+	LogonStatusSessionActive           LogonStatus = 0
+	LogonStatusPasswordChanged         LogonStatus = 1
+	LogonStatusPasswordDueToExpire     LogonStatus = 2
+	LogonStatusNewPasswordNotCompliant LogonStatus = 3
+	LogonStatusInvalidCredentials      LogonStatus = 5
+	LogonStatusAccountLocked           LogonStatus = 6
+	LogonStatusLogonsNotAllowed        LogonStatus = 7
+	LogonStatusPasswordExpired         LogonStatus = 8
+)
+
+func (s LogonStatus) String() string {
+	switch s {
+	case LogonStatusSessionActive:
+		return "Session active"
+	case LogonStatusPasswordChanged:
+		return "Session password changed"
+	case LogonStatusPasswordDueToExpire:
+		return "Session password due to expire"
+	case LogonStatusNewPasswordNotCompliant:
+		return "New session password does not comply with policy"
+	case LogonStatusInvalidCredentials:
+		return "Invalid username or password"
+	case LogonStatusAccountLocked:
+		return "Account locked"
+	case LogonStatusLogonsNotAllowed:
+		return "Logons are not allowed at this time"
+	case LogonStatusPasswordExpired:
+		return "Password expired"
+	}
+	return fmt.Sprintf("SessionStatus %d", int(s))
+}
+
+// accepted returns true if s lets the counterparty log on.
+func (s LogonStatus) accepted() bool {
+	switch s {
+	case LogonStatusSessionActive, LogonStatusPasswordChanged, LogonStatusPasswordDueToExpire:
+		return true
+	}
+	return false
+}
+
+// LogonRequest is a Logon received by an acceptor session, with the details of the connection it was received on.
+type LogonRequest struct {
+	SessionID  SessionID
+	Message    *Message
+	RemoteAddr net.Addr
+
+	// Certificates presented by the counterparty, empty unless the connection uses TLS with client certificates.
+	PeerCertificates []*x509.Certificate
+}
+
+// Authenticator checks the Logon received by an acceptor session before it is passed to the application.
+// An Authenticator may be shared by sessions and must be safe for concurrent use.
+type Authenticator interface {
+	// Authenticate returns the SessionStatus(1409) answered on the Logon reply of a FIXT.1.1 session.
+	// A non-nil error, or a status other than LogonStatusSessionActive, LogonStatusPasswordChanged
+	// or LogonStatusPasswordDueToExpire, rejects the Logon. The Logout sent in reply carries the error
+	// or a description of the status as Text(58), and on a FIXT.1.1 session the status.
+	Authenticate(req LogonRequest) (LogonStatus, error)
+}
+
+// PasswordChanger is an Authenticator that changes passwords. Authenticate only checks the NewPassword(925) of a Logon,
+// answering LogonStatusPasswordChanged. ChangePassword then changes it once the session accepts the Logon.
+type PasswordChanger interface {
+	Authenticator
+
+	// ChangePassword changes the password of the user of req to its NewPassword(925). An error rejects the Logon.
+	ChangePassword(req LogonRequest) error
+}
+
+// authenticationFailed rejects a Logon refused by the Authenticator of the session.
+type authenticationFailed struct {
+	RejectLogon
+	status LogonStatus
+}
+
+// logonRequest returns the LogonRequest of msg received by the session.
+func (s *session) logonRequest(msg *Message) LogonRequest {
+	s.status.RLock()
+	remoteAddr := s.status.remoteAddr
+	s.status.RUnlock()
+
+	return LogonRequest{
+		SessionID:        s.sessionID,
+		Message:          msg,
+		RemoteAddr:       remoteAddr,
+		PeerCertificates: s.peerCertificates,
+	}
+}
+
+// authenticate checks msg with the Authenticator of the session, returning authenticationFailed if it is refused.
+// A password change is only checked, see changePassword.
+func (s *session) authenticate(msg *Message) (LogonStatus, error) {
+	status, err := s.authenticator.Authenticate(s.logonRequest(msg))
+
+	switch {
+	case err == nil && status.accepted():
+		return status, nil
+
+	case err == nil:
+		err = errors.New(status.String())
+
+	case status.accepted():
+		status = LogonStatusInvalidCredentials
+	}
+
+	return status, authenticationFailed{RejectLogon: RejectLogon{Text: err.Error()}, status: status}
+}
+
+// changePassword changes the password as requested by msg, once the session accepts the Logon.
+func (s *session) changePassword(msg *Message) error {
+	if changer, ok := s.authenticator.(PasswordChanger); ok {
+		if err := changer.ChangePassword(s.logonRequest(msg)); err != nil {
+			return authenticationFailed{RejectLogon: RejectLogon{Text: err.Error()}, status: LogonStatusLogonsNotAllowed}
+		}
+	}
+
+	s.log.OnEvent("Session password changed")
+	return nil
+}
+
+const ssha256Prefix = "{SSHA256}"
+
+// credentialsFile is an Authenticator checking Username(553) and Password(554) against a file,
+// see config.LogonCredentialsFile. The file is read on each Logon, so changes apply without a restart.
+type credentialsFile struct {
+	path string
+	// Serializes access to the file by all the authenticators using it.
+	mu *sync.Mutex
+}
+
+// Locks of credentials files by path.
+var credentialsFileLocks sync.Map
+
+// NewCredentialsFileAuthenticator returns an Authenticator checking the Username(553) and Password(554) of each Logon
+// against the credentials file at path. It is a PasswordChanger, a Logon with NewPassword(925) accepted by the session
+// changes the password of the user and rewrites the file.
+// See config.LogonCredentialsFile for the format of the file.
+func NewCredentialsFileAuthenticator(path string) (Authenticator, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mu, _ := credentialsFileLocks.LoadOrStore(path, &sync.Mutex{})
+	a := &credentialsFile{path: path, mu: mu.(*sync.Mutex)}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, _, err := a.read(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// read returns the lines of the file and the index of the entry of each username.
+func (a *credentialsFile) read() (lines []string, users map[string]int, err error) {
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users = make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines = append(lines, line)
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		username, _, ok := strings.Cut(trimmed, ":")
+		if !ok || username == "" {
+			return nil, nil, fmt.Errorf("%v:%v: expected username:password", a.path, len(lines))
+		}
+		users[username] = len(lines) - 1
+	}
+
+	return lines, users, scanner.Err()
+}
+
+// Authenticate implements Authenticator.
+func (a *credentialsFile) Authenticate(req LogonRequest) (LogonStatus, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, _, _, status, err := a.check(req)
+	return status, err
+}
+
+// ChangePassword implements PasswordChanger.
+func (a *credentialsFile) ChangePassword(req LogonRequest) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	lines, i, newPassword, status, err := a.check(req)
+	if err != nil {
+		return err
+	}
+	if status != LogonStatusPasswordChanged {
+		return errors.New("Logon has no NewPassword")
+	}
+
+	hashed, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	username, _, _ := strings.Cut(strings.TrimSpace(lines[i]), ":")
+	lines[i] = username + ":" + hashed
+	if err := a.write(lines); err != nil {
+		return fmt.Errorf("Failed to change password: %w", err)
+	}
+	return nil
+}
+
+// check checks the credentials of req against the file, returning its lines, the index of the entry of the user
+// and the new password requested, if any. a.mu must be held.
+func (a *credentialsFile) check(req LogonRequest) (lines []string, i int, newPassword string, status LogonStatus, err error) {
+	invalid := errors.New(LogonStatusInvalidCredentials.String())
+	username, rej := req.Message.Body.GetString(tagUsername)
+	if rej != nil {
+		return nil, 0, "", LogonStatusInvalidCredentials, invalid
+	}
+	password, rej := req.Message.Body.GetString(tagPassword)
+	if rej != nil {
+		return nil, 0, "", LogonStatusInvalidCredentials, invalid
+	}
+
+	lines, users, err := a.read()
+	if err != nil {
+		return nil, 0, "", LogonStatusLogonsNotAllowed, err
+	}
+
+	i, ok := users[username]
+	if !ok {
+		return nil, 0, "", LogonStatusInvalidCredentials, invalid
+	}
+
+	_, stored, _ := strings.Cut(strings.TrimSpace(lines[i]), ":")
+	if !checkPassword(stored, password) {
+		return nil, 0, "", LogonStatusInvalidCredentials, invalid
+	}
+
+	if !req.Message.Body.Has(tagNewPassword) {
+		return lines, i, "", LogonStatusSessionActive, nil
+	}
+
+	newPassword, rej = req.Message.Body.GetString(tagNewPassword)
+	if rej != nil || newPassword == "" || newPassword == password || strings.ContainsAny(newPassword, "\r\n") {
+		return nil, 0, "", LogonStatusNewPasswordNotCompliant, errors.New(LogonStatusNewPasswordNotCompliant.String())
+	}
+	return lines, i, newPassword, LogonStatusPasswordChanged, nil
+}
+
+// write replaces the file with lines, keeping its permissions.
+func (a *credentialsFile) write(lines []string) error {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(a.path), filepath.Base(a.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), a.path)
+}
+
+// hashPassword returns password hashed with a random salt in the {SSHA256} format.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(append([]byte(password), salt...))
+	return ssha256Prefix + base64.StdEncoding.EncodeToString(append(digest[:], salt...)), nil
+}
+
+// checkPassword returns true if password matches stored, a plain or {SSHA256} hashed password.
+func checkPassword(stored, password string) bool {
+	if !strings.HasPrefix(stored, ssha256Prefix) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, ssha256Prefix))
+	if err != nil || len(decoded) <= sha256.Size {
+		return false
+	}
+
+	digest := sha256.Sum256(append([]byte(password), decoded[sha256.Size:]...))
+	return subtle.ConstantTimeCompare(digest[:], decoded[:sha256.Size]) == 1
+}
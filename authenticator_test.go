@@ -0,0 +1,315 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func logonWithCredentials(username, password, newPassword string) *Message {
+	logon := NewMessage()
+	logon.Header.SetField(tagMsgType, FIXString("A"))
+	if username != "" {
+		logon.Body.SetField(tagUsername, FIXString(username))
+	}
+	if password != "" {
+		logon.Body.SetField(tagPassword, FIXString(password))
+	}
+	if newPassword != "" {
+		logon.Body.SetField(tagNewPassword, FIXString(newPassword))
+	}
+	return logon
+}
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "credentials")
+	require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestCredentialsFileAuthenticator(t *testing.T) {
+	hashed, err := hashPassword("secret2")
+	require.Nil(t, err)
+
+	path := writeCredentialsFile(t, "# users\n\nalice:secret1\nbob:"+hashed+"\n")
+	auth, err := NewCredentialsFileAuthenticator(path)
+	require.Nil(t, err)
+
+	var tests = []struct {
+		username, password string
+		expected           LogonStatus
+	}{
+		{"alice", "secret1", LogonStatusSessionActive},
+		{"bob", "secret2", LogonStatusSessionActive},
+		{"alice", "secret2", LogonStatusInvalidCredentials},
+		{"bob", hashed, LogonStatusInvalidCredentials},
+		{"carol", "secret1", LogonStatusInvalidCredentials},
+		{"alice", "", LogonStatusInvalidCredentials},
+		{"", "secret1", LogonStatusInvalidCredentials},
+	}
+
+	for _, test := range tests {
+		status, err := auth.Authenticate(LogonRequest{Message: logonWithCredentials(test.username, test.password, "")})
+		assert.Equal(t, test.expected, status, test.username+":"+test.password)
+		assert.Equal(t, test.expected == LogonStatusSessionActive, err == nil, test.username+":"+test.password)
+	}
+}
+
+func TestCredentialsFileAuthenticatorInvalidFile(t *testing.T) {
+	_, err := NewCredentialsFileAuthenticator(filepath.Join(t.TempDir(), "missing"))
+	assert.NotNil(t, err)
+
+	_, err = NewCredentialsFileAuthenticator(writeCredentialsFile(t, "alice:secret1\nbob\n"))
+	assert.NotNil(t, err)
+}
+
+func TestCredentialsFileAuthenticatorChangePassword(t *testing.T) {
+	path := writeCredentialsFile(t, "# users\nalice:secret1\nbob:secret2\n")
+	auth, err := NewCredentialsFileAuthenticator(path)
+	require.Nil(t, err)
+
+	status, err := auth.Authenticate(LogonRequest{Message: logonWithCredentials("alice", "secret1", "secret1")})
+	assert.Equal(t, LogonStatusNewPasswordNotCompliant, status)
+	assert.NotNil(t, err)
+
+	status, err = auth.Authenticate(LogonRequest{Message: logonWithCredentials("alice", "wrong", "secret3")})
+	assert.Equal(t, LogonStatusInvalidCredentials, status)
+	assert.NotNil(t, err)
+
+	req := LogonRequest{Message: logonWithCredentials("alice", "secret1", "secret3")}
+	status, err = auth.Authenticate(req)
+	assert.Equal(t, LogonStatusPasswordChanged, status)
+	assert.Nil(t, err)
+
+	// The password only changes once the session accepts the Logon.
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, "# users\nalice:secret1\nbob:secret2\n", string(data))
+
+	assert.NotNil(t, auth.(PasswordChanger).ChangePassword(LogonRequest{Message: logonWithCredentials("alice", "secret1", "")}))
+	require.Nil(t, auth.(PasswordChanger).ChangePassword(req))
+
+	data, err = os.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(data), "secret3")
+	assert.True(t, strings.HasPrefix(string(data), "# users\nalice:{SSHA256}"))
+	assert.True(t, strings.HasSuffix(string(data), "\nbob:secret2\n"))
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Authenticators sharing the file see the new password.
+	other, err := NewCredentialsFileAuthenticator(path)
+	require.Nil(t, err)
+
+	for _, a := range []Authenticator{auth, other} {
+		status, err = a.Authenticate(LogonRequest{Message: logonWithCredentials("alice", "secret1", "")})
+		assert.Equal(t, LogonStatusInvalidCredentials, status)
+		assert.NotNil(t, err)
+
+		status, err = a.Authenticate(LogonRequest{Message: logonWithCredentials("alice", "secret3", "")})
+		assert.Equal(t, LogonStatusSessionActive, status)
+		assert.Nil(t, err)
+	}
+}
+
+type mockAuthenticator struct {
+	status LogonStatus
+	err    error
+	req    LogonRequest
+
+	changeErr error
+	changed   []LogonRequest
+}
+
+func (a *mockAuthenticator) Authenticate(req LogonRequest) (LogonStatus, error) {
+	a.req = req
+	return a.status, a.err
+}
+
+func (a *mockAuthenticator) ChangePassword(req LogonRequest) error {
+	a.changed = append(a.changed, req)
+	return a.changeErr
+}
+
+type AuthenticatorTestSuite struct {
+	SessionSuiteRig
+	auth *mockAuthenticator
+}
+
+func TestAuthenticatorTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthenticatorTestSuite))
+}
+
+func (s *AuthenticatorTestSuite) SetupTest() {
+	s.Init()
+	s.session.sessionID.BeginString = BeginStringFIXT11
+	s.session.stateMachine.State = logonState{}
+	s.auth = &mockAuthenticator{}
+	s.session.authenticator = s.auth
+}
+
+// Logon returns a Logon of the FIXT.1.1 session.
+func (s *AuthenticatorTestSuite) Logon() *Message {
+	logon := s.SessionSuiteRig.Logon()
+	logon.Header.SetField(tagBeginString, FIXString(BeginStringFIXT11))
+	logon.Body.SetField(tagDefaultApplVerID, FIXString("9"))
+	return logon
+}
+
+func (s *AuthenticatorTestSuite) TestLogonAccepted() {
+	s.auth.status = LogonStatusPasswordChanged
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToAdmin")
+
+	logon := s.Logon()
+	s.fixMsgIn(s.session, logon)
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.Same(logon, s.auth.req.Message)
+	s.Equal(s.session.sessionID, s.auth.req.SessionID)
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogon), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionStatus, int(LogonStatusPasswordChanged), s.MockApp.lastToAdmin.Body)
+	s.Require().Len(s.auth.changed, 1)
+	s.Same(logon, s.auth.changed[0].Message)
+}
+
+func (s *AuthenticatorTestSuite) TestLogonRejectedByApplicationKeepsPassword() {
+	s.auth.status = LogonStatusPasswordChanged
+	s.MockApp.On("FromAdmin").Return(RejectLogon{Text: "not today"})
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Empty(s.auth.changed)
+}
+
+func (s *AuthenticatorTestSuite) TestLogonTargetTooLowKeepsPassword() {
+	s.auth.status = LogonStatusPasswordChanged
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.IncrNextTargetMsgSeqNum()
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.State(latentState{})
+	s.Empty(s.auth.changed)
+}
+
+func (s *AuthenticatorTestSuite) TestLogonPasswordChangeFailed() {
+	s.auth.status = LogonStatusPasswordChanged
+	s.auth.changeErr = errors.New("disk full")
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.MockApp.AssertNotCalled(s.T(), "OnLogon")
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionStatus, int(LogonStatusLogonsNotAllowed), s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagText, "disk full", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *AuthenticatorTestSuite) TestSessionStatusOnlyFIXT11() {
+	s.session.sessionID.BeginString = BeginStringFIX42
+	s.auth.status = LogonStatusPasswordDueToExpire
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.SessionSuiteRig.Logon())
+
+	s.State(inSession{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogon), s.MockApp.lastToAdmin)
+	s.False(s.MockApp.lastToAdmin.Body.Has(tagSessionStatus))
+
+	s.SetupTest()
+	s.session.sessionID.BeginString = BeginStringFIX42
+	s.auth.status = LogonStatusAccountLocked
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.SessionSuiteRig.Logon())
+
+	s.State(latentState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.False(s.MockApp.lastToAdmin.Body.Has(tagSessionStatus))
+}
+
+func (s *AuthenticatorTestSuite) TestLogonRejected() {
+	s.auth.status = LogonStatusAccountLocked
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.MockApp.AssertNotCalled(s.T(), "FromAdmin")
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionStatus, int(LogonStatusAccountLocked), s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagText, "Account locked", s.MockApp.lastToAdmin.Body)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *AuthenticatorTestSuite) TestLogonRejectedWithError() {
+	s.auth.err = errors.New("token expired")
+	s.MockApp.On("ToAdmin")
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagSessionStatus, int(LogonStatusInvalidCredentials), s.MockApp.lastToAdmin.Body)
+	s.FieldEquals(tagText, "token expired", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *AuthenticatorTestSuite) TestInitiatorNotAuthenticated() {
+	s.session.InitiateLogon = true
+	s.auth.status = LogonStatusAccountLocked
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogon")
+
+	s.fixMsgIn(s.session, s.Logon())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.Nil(s.auth.req.Message)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Command inspect-store reads and repairs the MessageStore of a session while the engine is not running.
+//
+// Commands:
+//
+//	info                    print the seqnums and creation time
+//	list [flags]            print the stored messages, optionally by seqnum range and matching tag values
+//	grep tag=value [flags]  print the stored messages with the tag value, as list -match
+//	set [flags]             set the next sender and target seqnums
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/cmd/store-tools/internal/stores"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+var (
+	backend = flag.String("store", "file", "store to open: "+strings.Join(stores.Backends, ", "))
+	session = flag.String("session", "", "session to open, e.g. FIX.4.4:SENDER->TARGET, required if the settings file has more than one session")
+)
+
+// stringList collects the values of a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `usage: %v [flags] <settings file> <command> [command flags]
+
+commands:
+  info                    print the seqnums and creation time
+  list [flags]            print the stored messages
+  grep tag=value [flags]  print the stored messages with the tag value
+  set [flags]             set the next sender and target seqnums, the engine must not be running
+
+flags:
+`, os.Args[0])
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		usage()
+	}
+
+	if err := run(flag.Arg(0), flag.Arg(1), flag.Args()[2:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(settingsFile, command string, args []string, out io.Writer) error {
+	settings, err := stores.LoadSettings(settingsFile)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	if *session != "" {
+		ids = append(ids, *session)
+	}
+	sessionIDs, err := stores.SessionIDs(settings, ids)
+	if err != nil {
+		return err
+	}
+	if len(sessionIDs) != 1 {
+		return fmt.Errorf("the settings file has %v sessions, use -session to choose one", len(sessionIDs))
+	}
+
+	// The file store is opened read-only, which fails if it does not exist.
+	store, err := openStore(stores.NewReadOnlyFactory, sessionIDs[0], settings)
+	if err != nil {
+		return err
+	}
+	if command == "set" {
+		// The store exists, open it again for writing, without removing segments past retention.
+		if err := store.Close(); err != nil {
+			return err
+		}
+		if store, err = openStore(stores.NewRepairFactory, sessionIDs[0], settings); err != nil {
+			return err
+		}
+	}
+	defer store.Close()
+
+	switch command {
+	case "info":
+		return info(store, out)
+	case "list":
+		return list(store, args, nil, out)
+	case "grep":
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return errors.New("usage: grep tag=value [flags]")
+		}
+		return list(store, args[1:], []string{args[0]}, out)
+	case "set":
+		return set(store, args, out)
+	}
+	return fmt.Errorf("unknown command %q", command)
+}
+
+func openStore(newFactory func(string, *quickfix.Settings) (quickfix.MessageStoreFactory, error), sessionID quickfix.SessionID,
+	settings *quickfix.Settings) (quickfix.MessageStore, error) {
+	factory, err := newFactory(*backend, settings)
+	if err != nil {
+		return nil, err
+	}
+	return factory.Create(sessionID)
+}
+
+func info(store quickfix.MessageStore, out io.Writer) error {
+	fmt.Fprintf(out, "next sender seqnum: %v\n", store.NextSenderMsgSeqNum())
+	fmt.Fprintf(out, "next target seqnum: %v\n", store.NextTargetMsgSeqNum())
+	fmt.Fprintf(out, "creation time:      %v\n", store.CreationTime().UTC().Format("2006-01-02 15:04:05.000 MST"))
+	return nil
+}
+
+func list(store quickfix.MessageStore, args, matches []string, out io.Writer) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	begin := flags.Int("begin", 1, "first seqnum to print")
+	end := flags.Int("end", 0, "last seqnum to print, the last message sent by default")
+	var dicts stringList
+	flags.Var(&dicts, "dict", "data dictionary naming the tags, may be repeated, e.g. for FIXT11.xml and FIX50SP2.xml")
+	flags.Func("match", "print only the messages with a tag value, tag=value, may be repeated", func(value string) error {
+		matches = append(matches, value)
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *end == 0 {
+		*end = store.NextSenderMsgSeqNum() - 1
+	}
+
+	var filter []field
+	for _, match := range matches {
+		f, err := parseMatch(match)
+		if err != nil {
+			return err
+		}
+		filter = append(filter, f)
+	}
+
+	var printer messagePrinter
+	for _, fname := range dicts {
+		dict, err := datadictionary.Parse(fname)
+		if err != nil {
+			return fmt.Errorf("%v: %w", fname, err)
+		}
+		printer.dicts = append(printer.dicts, dict)
+	}
+
+	return store.IterateMessages(*begin, *end, func(msg []byte) error {
+		fields := splitFields(msg)
+		if !matchFields(fields, filter) {
+			return nil
+		}
+		return printer.print(out, fields)
+	})
+}
+
+func set(store quickfix.MessageStore, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("set", flag.ContinueOnError)
+	sender := flags.Int("sender", 0, "next sender seqnum")
+	target := flags.Int("target", 0, "next target seqnum")
+	force := flags.Bool("force", false, "allow lowering a seqnum, the counterparty rejects a MsgSeqNum lower than it expects")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *sender < 0 || *target < 0 || *sender == 0 && *target == 0 {
+		return errors.New("set requires -sender or -target, a positive seqnum")
+	}
+	if !*force && (*sender != 0 && *sender < store.NextSenderMsgSeqNum() || *target != 0 && *target < store.NextTargetMsgSeqNum()) {
+		return fmt.Errorf("seqnums can only be raised, next sender %v, next target %v, use -force to lower them",
+			store.NextSenderMsgSeqNum(), store.NextTargetMsgSeqNum())
+	}
+
+	if *sender != 0 {
+		previous := store.NextSenderMsgSeqNum()
+		if err := store.SetNextSenderMsgSeqNum(*sender); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "next sender seqnum: %v -> %v\n", previous, *sender)
+	}
+	if *target != 0 {
+		previous := store.NextTargetMsgSeqNum()
+		if err := store.SetNextTargetMsgSeqNum(*target); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "next target seqnum: %v -> %v\n", previous, *target)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/cmd/store-tools/internal/stores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSettings writes a settings file with the session SENDER->TARGET stored in dir/store, and the default settings.
+func writeSettings(t *testing.T, dir, name, defaults string) string {
+	settingsFile := filepath.Join(dir, name)
+	require.Nil(t, os.WriteFile(settingsFile, []byte(fmt.Sprintf(`
+[DEFAULT]
+FileStorePath=%s
+%s
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET`, filepath.Join(dir, "store"), defaults)), 0600))
+	return settingsFile
+}
+
+func writeStore(t *testing.T) string {
+	return writeSegmentedStore(t, "")
+}
+
+// writeSegmentedStore writes a store of two messages with the given default settings, returning its settings file.
+func writeSegmentedStore(t *testing.T, defaults string) string {
+	settingsFile := writeSettings(t, t.TempDir(), "settings.cfg", defaults)
+
+	settings, err := stores.LoadSettings(settingsFile)
+	require.Nil(t, err)
+	factory, err := stores.NewFactory("file", settings)
+	require.Nil(t, err)
+	store, err := factory.Create(quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"})
+	require.Nil(t, err)
+	defer store.Close()
+
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("8=FIX.4.4\x019=20\x0135=D\x0134=1\x0155=IBM\x0154=1\x0110=000\x01")))
+	require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(2, []byte("8=FIX.4.4\x019=20\x0135=D\x0134=2\x0155=MSFT\x0154=2\x0110=000\x01")))
+	require.Nil(t, store.SetNextTargetMsgSeqNum(5))
+	return settingsFile
+}
+
+func TestInfo(t *testing.T) {
+	var out bytes.Buffer
+	require.Nil(t, run(writeStore(t), "info", nil, &out))
+	assert.Contains(t, out.String(), "next sender seqnum: 3\n")
+	assert.Contains(t, out.String(), "next target seqnum: 5\n")
+}
+
+func TestList(t *testing.T) {
+	settingsFile := writeStore(t)
+
+	var out bytes.Buffer
+	require.Nil(t, run(settingsFile, "list", nil, &out))
+	assert.Equal(t, "8=FIX.4.4|9=20|35=D|34=1|55=IBM|54=1|10=000\n8=FIX.4.4|9=20|35=D|34=2|55=MSFT|54=2|10=000\n", out.String())
+
+	out.Reset()
+	require.Nil(t, run(settingsFile, "list", []string{"-begin", "2"}, &out))
+	assert.Equal(t, "8=FIX.4.4|9=20|35=D|34=2|55=MSFT|54=2|10=000\n", out.String())
+
+	out.Reset()
+	require.Nil(t, run(settingsFile, "grep", []string{"55=IBM", "-dict", "../../../spec/FIX44.xml"}, &out))
+	assert.Equal(t, `--- MsgSeqNum 1
+  BeginString(8) = FIX.4.4
+  BodyLength(9) = 20
+  MsgType(35) = D (NEWORDERSINGLE)
+  MsgSeqNum(34) = 1
+  Symbol(55) = IBM
+  Side(54) = 1 (BUY)
+  CheckSum(10) = 000
+`, out.String())
+
+	out.Reset()
+	require.Nil(t, run(settingsFile, "list", []string{"-match", "55=IBM", "-match", "54=2"}, &out))
+	assert.Empty(t, out.String())
+
+	assert.NotNil(t, run(settingsFile, "grep", []string{"IBM"}, &out))
+}
+
+func TestSet(t *testing.T) {
+	settingsFile := writeStore(t)
+
+	var out bytes.Buffer
+	require.Nil(t, run(settingsFile, "set", []string{"-sender", "10"}, &out))
+	assert.Equal(t, "next sender seqnum: 3 -> 10\n", out.String())
+
+	// Lowering a seqnum requires -force.
+	assert.NotNil(t, run(settingsFile, "set", []string{"-target", "2"}, &out))
+	assert.NotNil(t, run(settingsFile, "set", nil, &out))
+
+	out.Reset()
+	require.Nil(t, run(settingsFile, "set", []string{"-target", "2", "-force"}, &out))
+	assert.Equal(t, "next target seqnum: 5 -> 2\n", out.String())
+
+	out.Reset()
+	require.Nil(t, run(settingsFile, "info", nil, &out))
+	assert.Contains(t, out.String(), "next sender seqnum: 10\n")
+	assert.Contains(t, out.String(), "next target seqnum: 2\n")
+}
+
+func TestSetKeepsSegments(t *testing.T) {
+	settingsFile := writeSegmentedStore(t, "FileStoreSegmentMessages=1")
+	dir := filepath.Dir(settingsFile)
+	bodyFiles, err := filepath.Glob(filepath.Join(dir, "store", "*.body"))
+	require.Nil(t, err)
+	require.Len(t, bodyFiles, 2)
+
+	// The first segment is past retention, but is not removed by set.
+	retention := writeSettings(t, dir, "retention.cfg", "FileStoreSegmentMessages=1\nFileStoreRetentionSeqNums=1")
+	var out bytes.Buffer
+	require.Nil(t, run(retention, "set", []string{"-sender", "10"}, &out))
+	assert.Equal(t, "next sender seqnum: 3 -> 10\n", out.String())
+
+	out.Reset()
+	require.Nil(t, run(retention, "list", nil, &out))
+	assert.Equal(t, "8=FIX.4.4|9=20|35=D|34=1|55=IBM|54=1|10=000\n8=FIX.4.4|9=20|35=D|34=2|55=MSFT|54=2|10=000\n", out.String())
+}
+
+func TestMissingStore(t *testing.T) {
+	dir := t.TempDir()
+	settingsFile := writeSettings(t, dir, "settings.cfg", "")
+
+	// The store is not created by the commands, set included.
+	var out bytes.Buffer
+	assert.NotNil(t, run(settingsFile, "info", nil, &out))
+	assert.NotNil(t, run(settingsFile, "list", nil, &out))
+	assert.NotNil(t, run(settingsFile, "set", []string{"-sender", "10"}, &out))
+	assert.Empty(t, out.String())
+	_, err := os.Stat(filepath.Join(dir, "store"))
+	assert.True(t, os.IsNotExist(err))
+}
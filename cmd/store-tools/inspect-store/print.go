@@ -0,0 +1,130 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const soh = '\x01'
+
+type field struct {
+	tag   int
+	value string
+}
+
+// splitFields returns the fields of msg in order. Fields that are not tag=value are returned with tag 0.
+func splitFields(msg []byte) []field {
+	var fields []field
+	for _, raw := range bytes.Split(bytes.TrimSuffix(msg, []byte{soh}), []byte{soh}) {
+		tag, value, ok := bytes.Cut(raw, []byte("="))
+		t, err := strconv.Atoi(string(tag))
+		if !ok || err != nil {
+			fields = append(fields, field{value: string(raw)})
+			continue
+		}
+		fields = append(fields, field{tag: t, value: string(value)})
+	}
+	return fields
+}
+
+// parseMatch parses a tag=value filter.
+func parseMatch(match string) (field, error) {
+	tag, value, ok := strings.Cut(match, "=")
+	t, err := strconv.Atoi(tag)
+	if !ok || err != nil || t <= 0 {
+		return field{}, fmt.Errorf("invalid match %q, expected tag=value", match)
+	}
+	return field{tag: t, value: value}, nil
+}
+
+// matchFields returns true if fields has each field of filter.
+func matchFields(fields, filter []field) bool {
+	for _, f := range filter {
+		found := false
+		for _, candidate := range fields {
+			if candidate == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// messagePrinter prints messages on one line, or a field per line named by the data dictionaries.
+type messagePrinter struct {
+	dicts []*datadictionary.DataDictionary
+}
+
+func (p messagePrinter) print(out io.Writer, fields []field) error {
+	if len(p.dicts) == 0 {
+		parts := make([]string, len(fields))
+		for i, f := range fields {
+			parts[i] = f.String()
+		}
+		_, err := fmt.Fprintln(out, strings.Join(parts, "|"))
+		return err
+	}
+
+	for _, f := range fields {
+		if f.tag == 34 {
+			if _, err := fmt.Fprintf(out, "--- MsgSeqNum %v\n", f.value); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	for _, f := range fields {
+		if _, err := fmt.Fprintf(out, "  %v\n", p.describe(f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describe returns f with the name of its tag and the description of its value, if known.
+func (p messagePrinter) describe(f field) string {
+	for _, dict := range p.dicts {
+		fieldType, ok := dict.FieldTypeByTag[f.tag]
+		if !ok {
+			continue
+		}
+
+		s := fmt.Sprintf("%v(%v) = %v", fieldType.Name(), f.tag, f.value)
+		if enum, ok := fieldType.Enums[f.value]; ok {
+			s += " (" + enum.Description + ")"
+		}
+		return s
+	}
+	return f.String()
+}
+
+func (f field) String() string {
+	if f.tag == 0 {
+		return f.value
+	}
+	return strconv.Itoa(f.tag) + "=" + f.value
+}
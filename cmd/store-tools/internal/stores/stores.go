@@ -0,0 +1,152 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Package stores opens the MessageStores of the sessions of a settings file, for the store commands.
+// The store commands are a module of their own, so that the SQL drivers they link are not dependencies of quickfix.
+package stores
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	// SQL drivers of the schemas in _sql.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/bolt"
+	"github.com/quickfixgo/quickfix/store/file"
+	"github.com/quickfixgo/quickfix/store/mongo"
+	"github.com/quickfixgo/quickfix/store/sql"
+)
+
+// Backends are the stores accepted by NewFactory.
+var Backends = []string{"file", "bolt", "sql", "mongo"}
+
+// NewFactory returns the MessageStoreFactory of backend, configured by settings.
+func NewFactory(backend string, settings *quickfix.Settings) (quickfix.MessageStoreFactory, error) {
+	switch strings.ToLower(backend) {
+	case "file":
+		return file.NewStoreFactory(settings), nil
+	case "bolt":
+		return bolt.NewStoreFactory(settings), nil
+	case "sql":
+		return sql.NewStoreFactory(settings), nil
+	case "mongo":
+		return mongo.NewStoreFactory(settings), nil
+	}
+	return nil, fmt.Errorf("unknown store %q, expected one of %v", backend, strings.Join(Backends, ", "))
+}
+
+// NewReadOnlyFactory returns the MessageStoreFactory of backend for reading a store, configured by settings.
+// The file store is opened without creating, writing or removing files, and Create fails if the store does not exist.
+// The other backends are opened as by NewFactory.
+func NewReadOnlyFactory(backend string, settings *quickfix.Settings) (quickfix.MessageStoreFactory, error) {
+	if strings.ToLower(backend) == "file" {
+		return file.NewReadOnlyStoreFactory(settings), nil
+	}
+	return NewFactory(backend, settings)
+}
+
+// NewRepairFactory returns the MessageStoreFactory of backend for changing a store, configured by settings.
+// The file store keeps the segments past retention, the other backends are opened as by NewFactory.
+func NewRepairFactory(backend string, settings *quickfix.Settings) (quickfix.MessageStoreFactory, error) {
+	if strings.ToLower(backend) == "file" {
+		return file.NewRepairStoreFactory(settings), nil
+	}
+	return NewFactory(backend, settings)
+}
+
+// LoadSettings reads the settings file fname.
+func LoadSettings(fname string) (*quickfix.Settings, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return quickfix.ParseSettings(f)
+}
+
+// SessionIDs returns the sessions named by ids, or all the sessions of settings ordered by name if ids is empty.
+// Sessions that are not in settings are parsed with ParseSessionID, their stores use the global settings
+// if DynamicSessions is enabled.
+func SessionIDs(settings *quickfix.Settings, ids []string) ([]quickfix.SessionID, error) {
+	configured := settings.SessionSettings()
+	if len(ids) == 0 {
+		sessionIDs := make([]quickfix.SessionID, 0, len(configured))
+		for sessionID := range configured {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+		sort.Slice(sessionIDs, func(i, j int) bool { return sessionIDs[i].String() < sessionIDs[j].String() })
+		return sessionIDs, nil
+	}
+
+	sessionIDs := make([]quickfix.SessionID, 0, len(ids))
+	for _, id := range ids {
+		sessionID, err := findSessionID(configured, id)
+		if err != nil {
+			return nil, err
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs, nil
+}
+
+func findSessionID(configured map[quickfix.SessionID]*quickfix.SessionSettings, id string) (quickfix.SessionID, error) {
+	for sessionID := range configured {
+		if sessionID.String() == id {
+			return sessionID, nil
+		}
+	}
+	return ParseSessionID(id)
+}
+
+// ParseSessionID parses a SessionID in the format of SessionID.String, e.g. FIX.4.4:SENDER/SUB->TARGET:QUALIFIER.
+// A single sub-component after a CompID is read as the SubID.
+func ParseSessionID(id string) (sessionID quickfix.SessionID, err error) {
+	sender, target, ok := strings.Cut(id, "->")
+	if !ok {
+		return sessionID, fmt.Errorf("invalid session %q, expected BeginString:SenderCompID->TargetCompID", id)
+	}
+
+	var senderCompID, targetCompID string
+	sessionID.BeginString, senderCompID, ok = strings.Cut(sender, ":")
+	if !ok || sessionID.BeginString == "" {
+		return sessionID, fmt.Errorf("invalid session %q, expected BeginString:SenderCompID->TargetCompID", id)
+	}
+	targetCompID, sessionID.Qualifier, _ = strings.Cut(target, ":")
+
+	if sessionID.SenderCompID, sessionID.SenderSubID, sessionID.SenderLocationID, err = parseCompID(senderCompID); err != nil {
+		return sessionID, fmt.Errorf("invalid session %q: %v", id, err)
+	}
+	if sessionID.TargetCompID, sessionID.TargetSubID, sessionID.TargetLocationID, err = parseCompID(targetCompID); err != nil {
+		return sessionID, fmt.Errorf("invalid session %q: %v", id, err)
+	}
+	return sessionID, nil
+}
+
+func parseCompID(s string) (compID, subID, locationID string, err error) {
+	parts := strings.Split(s, "/")
+	if len(parts) > 3 || parts[0] == "" {
+		return "", "", "", fmt.Errorf("invalid CompID %q", s)
+	}
+
+	parts = append(parts, "", "")
+	return parts[0], parts[1], parts[2], nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package stores
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSessionID(t *testing.T) {
+	var tests = []quickfix.SessionID{
+		{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"},
+		{BeginString: "FIXT.1.1", SenderCompID: "SENDER", SenderSubID: "SUB", TargetCompID: "TARGET", Qualifier: "q1"},
+		{BeginString: "FIX.4.2", SenderCompID: "S", SenderSubID: "SS", SenderLocationID: "SL", TargetCompID: "T", TargetSubID: "TS", TargetLocationID: "TL"},
+	}
+
+	for _, expected := range tests {
+		sessionID, err := ParseSessionID(expected.String())
+		require.Nil(t, err, expected.String())
+		assert.Equal(t, expected, sessionID)
+	}
+
+	for _, id := range []string{"", "FIX.4.4:SENDER", "SENDER->TARGET", ":SENDER->TARGET", "FIX.4.4:->TARGET", "FIX.4.4:SENDER->A/B/C/D"} {
+		_, err := ParseSessionID(id)
+		assert.NotNil(t, err, id)
+	}
+}
+
+func TestSessionIDs(t *testing.T) {
+	settings, err := quickfix.ParseSettings(strings.NewReader(`
+[DEFAULT]
+FileStorePath=store
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET2
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET1
+SessionQualifier=q`))
+	require.Nil(t, err)
+
+	target1 := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET1", Qualifier: "q"}
+	target2 := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET2"}
+
+	sessionIDs, err := SessionIDs(settings, nil)
+	require.Nil(t, err)
+	assert.Equal(t, []quickfix.SessionID{target1, target2}, sessionIDs)
+
+	sessionIDs, err = SessionIDs(settings, []string{"FIX.4.4:SENDER->TARGET2", "FIX.4.2:OTHER->TARGET"})
+	require.Nil(t, err)
+	assert.Equal(t, []quickfix.SessionID{target2, {BeginString: "FIX.4.2", SenderCompID: "OTHER", TargetCompID: "TARGET"}}, sessionIDs)
+
+	_, err = SessionIDs(settings, []string{"TARGET2"})
+	assert.NotNil(t, err)
+}
+
+func TestNewFactory(t *testing.T) {
+	settings := quickfix.NewSettings()
+	for _, backend := range Backends {
+		factory, err := NewFactory(strings.ToUpper(backend), settings)
+		assert.Nil(t, err)
+		assert.NotNil(t, factory)
+	}
+
+	_, err := NewFactory("memory", settings)
+	assert.NotNil(t, err)
+}
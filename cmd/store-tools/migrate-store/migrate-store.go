@@ -0,0 +1,149 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+// Command migrate-store copies the state of sessions, their creation time, seqnums and stored messages,
+// from one MessageStore to another, e.g. from files to a SQL database. The engine must not be running.
+//
+// Both stores are configured by the settings file, or the destination by a second settings file given with -to-settings.
+// To copy from a store in memory, call quickfix.CopyMessageStore in the process holding it.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/cmd/store-tools/internal/stores"
+)
+
+var (
+	from       = flag.String("from", "", "store to copy from: "+strings.Join(stores.Backends, ", "))
+	to         = flag.String("to", "", "store to copy to: "+strings.Join(stores.Backends, ", "))
+	toSettings = flag.String("to-settings", "", "settings file of the store to copy to, the settings file by default")
+	force      = flag.Bool("force", false, "overwrite sessions that are not empty in the store to copy to")
+	sessions   sessionList
+)
+
+// sessionList collects the values of a repeated flag.
+type sessionList []string
+
+func (l *sessionList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *sessionList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %v -from <store> -to <store> [flags] <settings file>\n", os.Args[0])
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	flag.Var(&sessions, "session", "session to copy, e.g. FIX.4.4:SENDER->TARGET, may be repeated, all the sessions of the settings file by default")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 1 || *from == "" || *to == "" {
+		usage()
+	}
+
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(settingsFile string) error {
+	srcSettings, err := stores.LoadSettings(settingsFile)
+	if err != nil {
+		return err
+	}
+
+	dstSettings := srcSettings
+	if *toSettings != "" {
+		if dstSettings, err = stores.LoadSettings(*toSettings); err != nil {
+			return err
+		}
+	} else if strings.EqualFold(*from, *to) {
+		return errors.New("the stores to copy from and to are the same, use -to-settings to configure the store to copy to")
+	}
+
+	srcFactory, err := stores.NewFactory(*from, srcSettings)
+	if err != nil {
+		return err
+	}
+	dstFactory, err := stores.NewFactory(*to, dstSettings)
+	if err != nil {
+		return err
+	}
+
+	sessionIDs, err := stores.SessionIDs(srcSettings, sessions)
+	if err != nil {
+		return err
+	}
+	if len(sessionIDs) == 0 {
+		return errors.New("no sessions to copy")
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := migrate(srcFactory, dstFactory, sessionID); err != nil {
+			return fmt.Errorf("%v: %w", sessionID, err)
+		}
+	}
+	return nil
+}
+
+func migrate(srcFactory, dstFactory quickfix.MessageStoreFactory, sessionID quickfix.SessionID) error {
+	src, err := srcFactory.Create(sessionID)
+	if err != nil {
+		return fmt.Errorf("open %v store: %w", *from, err)
+	}
+	defer src.Close()
+
+	dst, err := dstFactory.Create(sessionID)
+	if err != nil {
+		return fmt.Errorf("open %v store: %w", *to, err)
+	}
+	defer dst.Close()
+
+	if !*force && (dst.NextSenderMsgSeqNum() != 1 || dst.NextTargetMsgSeqNum() != 1) {
+		return fmt.Errorf("%v store is not empty, next sender %v, next target %v, use -force to overwrite it",
+			*to, dst.NextSenderMsgSeqNum(), dst.NextTargetMsgSeqNum())
+	}
+
+	if err := quickfix.CopyMessageStore(dst, src); err != nil {
+		return err
+	}
+
+	messages := 0
+	err = dst.IterateMessages(1, dst.NextSenderMsgSeqNum()-1, func([]byte) error {
+		messages++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v: copied %v messages, next sender %v, next target %v, created %v\n", sessionID,
+		messages, dst.NextSenderMsgSeqNum(), dst.NextTargetMsgSeqNum(), dst.CreationTime().UTC().Format("2006-01-02 15:04:05"))
+	return nil
+}
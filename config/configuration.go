@@ -476,6 +476,123 @@ const (
 	// Valid Values:
 	//  - Any positive integer
 	MaxLatency string = "MaxLatency"
+
+	// ThrottleMessages enables outbound throttling of application messages and sets the number of messages
+	// that may be sent per ThrottleInterval. Admin messages, e.g. Heartbeat, are never throttled.
+	//
+	// Required: No
+	//
+	// Default: 0, no throttling
+	//
+	// Valid Values:
+	//  - Any positive integer
+	ThrottleMessages string = "ThrottleMessages"
+
+	// ThrottleInterval is the interval ThrottleMessages applies to.
+	//
+	// Required: No
+	//
+	// Default: 1s
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds or a valid go time.Duration
+	ThrottleInterval string = "ThrottleInterval"
+
+	// ThrottleMsgTypes limits throttling to the listed MsgTypes, each of which is allowed ThrottleMessages per ThrottleInterval.
+	// If not set, all application messages share one limit.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A comma delimited list of MsgType values, e.g. D,F,G
+	ThrottleMsgTypes string = "ThrottleMsgTypes"
+
+	// ThrottlePolicy determines what happens to a message sent with SendToTarget when the throttle limit is reached.
+	//
+	// Required: No
+	//
+	// Default: REJECT
+	//
+	// Valid Values:
+	//  - REJECT SendToTarget returns quickfix.ErrThrottled and the message is not sent
+	//  - BLOCK SendToTarget sleeps on the calling goroutine until the message can be sent. Locks held by the caller stay held,
+	//    and calling it from an Application callback holds up the session
+	//  - QUEUE SendToTarget returns immediately and the message is sequenced and sent once the limit allows. Released messages
+	//    are subject to OutboundQueueLimit, and SendToTarget returns quickfix.ErrThrottled once ThrottleQueueLimit messages wait
+	ThrottlePolicy string = "ThrottlePolicy"
+
+	// ThrottleQueueLimit sets the number of messages the QUEUE ThrottlePolicy holds at most.
+	//
+	// Required: No
+	//
+	// Default: 10000
+	//
+	// Valid Values:
+	//  - Any positive integer
+	ThrottleQueueLimit string = "ThrottleQueueLimit"
+
+	// MaxMessageSize sets the largest message in bytes read from a connection.
+	// The connection is closed when a counterparty sends a larger message, or declares one with BodyLength(9),
+	// so that a malformed or hostile peer cannot make the engine buffer an unbounded amount of data.
+	// For acceptors, the value in the default section applies until the session of a new connection is known.
+	//
+	// Required: No
+	//
+	// Default: 0, no limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	MaxMessageSize string = "MaxMessageSize"
+
+	// ResyncOnCorruptMessage if set to Y, skips a message with an unreadable BodyLength(9), or one cut short by the start
+	// of another message, and continues with the next 8=FIX found in the stream instead of closing the connection.
+	// The skipped message is recovered by the usual resend request for the sequence gap.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ResyncOnCorruptMessage string = "ResyncOnCorruptMessage"
+
+	// SocketFlushPolicy determines when the messages written by a session are flushed to its connection.
+	// Messages are collected in a buffer of SocketWriteBufferSize bytes, which is always flushed when full.
+	//
+	// Required: No
+	//
+	// Default: IDLE
+	//
+	// Valid Values:
+	//  - IDLE flushes once no more messages are queued, so that a burst of messages is sent with few writes
+	//  - IMMEDIATE flushes each message as soon as it is written
+	//  - INTERVAL flushes SocketFlushInterval after the first message written since the last flush, trading latency for fewer writes
+	SocketFlushPolicy string = "SocketFlushPolicy"
+
+	// SocketFlushInterval sets the longest time a message is held in the write buffer when SocketFlushPolicy is INTERVAL.
+	// Values are parsed as a time.Duration, e.g. 500us or 2ms.
+	//
+	// Required: No
+	//
+	// Default: 1ms
+	//
+	// Valid Values:
+	//  - A positive duration
+	SocketFlushInterval string = "SocketFlushInterval"
+
+	// SocketWriteBufferSize sets the size in bytes of the buffer collecting the messages written by a session
+	// before they are flushed to its connection.
+	//
+	// Required: No
+	//
+	// Default: 4096
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SocketWriteBufferSize string = "SocketWriteBufferSize"
 )
 
 const (
@@ -493,6 +610,58 @@ const (
 	//  - Any positive integer
 	ReconnectInterval string = "ReconnectInterval"
 
+	// ReconnectBackoffMultiplier sets the factor the wait between reconnection attempts grows by after each attempt,
+	// starting from ReconnectInterval.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 1 (a fixed ReconnectInterval)
+	//
+	// Valid Values:
+	//  - A number greater than or equal to 1
+	ReconnectBackoffMultiplier string = "ReconnectBackoffMultiplier"
+
+	// ReconnectMaxInterval caps the wait between reconnection attempts when ReconnectBackoffMultiplier is used.
+	// Only used for initiators.
+	//
+	// Example Values:
+	//  - ReconnectMaxInterval=300 # 300 seconds
+	//  - ReconnectMaxInterval=5m # 5 minutes
+	//
+	// Required: No
+	//
+	// Default: 0 (no maximum)
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds or a valid go time.Duration
+	ReconnectMaxInterval string = "ReconnectMaxInterval"
+
+	// ReconnectJitter randomizes each wait between reconnection attempts by up to this fraction of the wait,
+	// up or down, so that sessions do not reconnect in lockstep. The wait never exceeds ReconnectMaxInterval.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 0
+	//
+	// Valid Values:
+	//  - A number from 0 to 1, e.g. 0.2 for +/- 20%
+	ReconnectJitter string = "ReconnectJitter"
+
+	// ReconnectResetOnLogon determines if the wait between reconnection attempts goes back to ReconnectInterval
+	// once the session has logged on.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	ReconnectResetOnLogon string = "ReconnectResetOnLogon"
+
 	// LogoutTimeout defines the number of seconds to wait for a logout response before disconnecting.
 	// Only used for initiators.
 	// Value must be positive integer.
@@ -555,6 +724,35 @@ const (
 	//  - A positive integer
 	SocketConnectPort string = "SocketConnectPort"
 
+	// SocketConnectFailoverThreshold sets the number of consecutive failed connection attempts to a SocketConnectHost
+	// before the initiator fails over to the next host, see SocketConnectHost<n>.
+	// A host that was connected to successfully is used again on the next reconnect.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: 1
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SocketConnectFailoverThreshold string = "SocketConnectFailoverThreshold"
+
+	// SocketConnectFailbackInterval sets how long after failing over from the primary SocketConnectHost the initiator
+	// tries the primary host again on its next reconnect. While connected to a backup host, the primary host is probed
+	// with a TCP connection at this interval. Once the probe succeeds the session logs out and reconnects to the primary host.
+	// Only used for initiators.
+	//
+	// Example Values:
+	//  - SocketConnectFailbackInterval=5m # 5 minutes
+	//
+	// Required: No
+	//
+	// Default: 0 (the primary host is only tried again when the backup hosts fail)
+	//
+	// Valid Values:
+	//  - A valid go time.Duration
+	SocketConnectFailbackInterval string = "SocketConnectFailbackInterval"
+
 	// SocketTimeout sets the duration of timeout for TLS handshake.
 	// Only used for initiators.
 	//
@@ -570,6 +768,30 @@ const (
 	//  - A valid go time.Duration
 	SocketTimeout string = "SocketTimeout"
 
+	// SocketLocalHost sets the local address connections are made from.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Chosen by the operating system
+	//
+	// Valid Values:
+	//  - A valid IPv4 or IPv6 address or a domain name
+	SocketLocalHost string = "SocketLocalHost"
+
+	// SocketLocalPort sets the local port connections are made from.
+	// A connection made right after the previous one closed may fail while the port is in TIME_WAIT,
+	// it is retried after ReconnectInterval.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: Chosen by the operating system
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SocketLocalPort string = "SocketLocalPort"
+
 	// ProxyType sets the type of proxy server to connect to.
 	// Only used for initiators.
 	//
@@ -579,6 +801,8 @@ const (
 	//
 	// Valid Values:
 	//  - socks
+	//  - http a proxy supporting the HTTP CONNECT method
+	//  - https an HTTP CONNECT proxy reached over TLS
 	ProxyType string = "ProxyType"
 
 	// ProxyHost provides the address of the proxy server to connect to.
@@ -624,6 +848,18 @@ const (
 	// Valid Values:
 	//  - Any string
 	ProxyPassword string = "ProxyPassword"
+
+	// ProxyCAFile is the path of a PEM encoded CA bundle used to verify the certificate of an https proxy,
+	// in place of the host's root CA set.
+	// Only used for initiators.
+	//
+	// Required: No
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A filepath to a file with read access.
+	ProxyCAFile string = "ProxyCAFile"
 )
 
 const (
@@ -708,6 +944,168 @@ const (
 	//  - Y
 	//  - N
 	DynamicQualifier string = "DynamicQualifier"
+
+	// InboundMaxMessageSize sets the largest message in bytes accepted from a counterparty.
+	// It is checked against BodyLength(9) before the message is read, as MaxMessageSize,
+	// and a larger message closes the connection.
+	// The value in the default section also applies to the first message of a new connection, before its session is known.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: 0, no limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	InboundMaxMessageSize string = "InboundMaxMessageSize"
+
+	// InboundMaxMessages sets the number of messages accepted per InboundInterval on a connection.
+	// Once the limit is reached the connection is read no faster than the limit allows,
+	// and each message over the limit is handled according to InboundLimitPolicy.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: 0, no limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	InboundMaxMessages string = "InboundMaxMessages"
+
+	// InboundInterval is the interval InboundMaxMessages applies to.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: 1s
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds or a valid go time.Duration
+	InboundInterval string = "InboundInterval"
+
+	// InboundLimitPolicy determines how a message over InboundMaxMessages is handled.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: LOGOUT
+	//
+	// Valid Values:
+	//  - REJECT the message is rejected, admin messages are still processed
+	//  - LOGOUT the session is logged out
+	//  - DISCONNECT the connection is dropped without a Logout
+	InboundLimitPolicy string = "InboundLimitPolicy"
+
+	// OutboundQueueLimit sets the number of messages sent with SendToTarget that may wait in the send queue
+	// of a session, e.g. while a slow counterparty holds up the connection. Once the limit is reached,
+	// further messages are handled according to OutboundQueuePolicy.
+	//
+	// Required: No
+	//
+	// Default: 0, no limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	OutboundQueueLimit string = "OutboundQueueLimit"
+
+	// OutboundQueuePolicy determines how a message sent with SendToTarget is handled when the send queue is at OutboundQueueLimit.
+	// BLOCK must not be used by applications sending from their Application callbacks, as the queue does not drain
+	// while a callback waits.
+	//
+	// Required: No
+	//
+	// Default: REJECT
+	//
+	// Valid Values:
+	//  - REJECT SendToTarget returns a quickfix.QueueFullError and the message is not sent
+	//  - BLOCK SendToTarget waits until the queue is below the limit
+	//  - DISCONNECT SendToTarget returns a quickfix.QueueFullError and the connection is dropped without a Logout
+	OutboundQueuePolicy string = "OutboundQueuePolicy"
+
+	// InboundQueueLimit sets the number of messages read from the connection that may wait to be processed by the session.
+	// Once the limit is reached, reading is handled according to InboundQueuePolicy.
+	//
+	// Required: No
+	//
+	// Default: 0, each message is handed to the session as it is read
+	//
+	// Valid Values:
+	//  - Any positive integer
+	InboundQueueLimit string = "InboundQueueLimit"
+
+	// InboundQueuePolicy determines how reading is handled when the inbound queue is at InboundQueueLimit.
+	//
+	// Required: No
+	//
+	// Default: BLOCK
+	//
+	// Valid Values:
+	//  - BLOCK reading pauses until the session catches up, so that TCP flow control slows the counterparty down
+	//  - DISCONNECT reading stops and the connection is dropped without waiting for the session to process the queued
+	//    messages, which the counterparty resends after the next logon. Requires InboundQueueLimit
+	InboundQueuePolicy string = "InboundQueuePolicy"
+
+	// LogonCredentialsFile is the path of a credentials file checked against the Username(553) and Password(554)
+	// of each Logon received. Each line of the file holds username:password, blank lines and lines starting with # are ignored.
+	// Passwords may be stored hashed as {SSHA256} followed by the base64 of the SHA-256 digest of password and salt, then the salt.
+	// A Logon with NewPassword(925) changes the password of the user once the Logon is accepted,
+	// the file is rewritten with the new password hashed.
+	// An Authenticator set with WithAcceptorAuthenticator takes precedence.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Valid Values:
+	//  - A valid path
+	LogonCredentialsFile string = "LogonCredentialsFile"
+)
+
+const (
+	// Socket settings.
+	// Initiators apply the settings of each session, acceptors apply the settings of the default section
+	// to every connection they accept.
+
+	// SocketNodelay disables Nagle's algorithm (TCP_NODELAY) so that messages are sent without delay.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	SocketNodelay string = "SocketNodelay"
+
+	// SocketSendBufferSize sets the size in bytes of the socket send buffer (SO_SNDBUF).
+	//
+	// Required: No
+	//
+	// Default: Chosen by the operating system
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SocketSendBufferSize string = "SocketSendBufferSize"
+
+	// SocketReceiveBufferSize sets the size in bytes of the socket receive buffer (SO_RCVBUF).
+	//
+	// Required: No
+	//
+	// Default: Chosen by the operating system
+	//
+	// Valid Values:
+	//  - Any positive integer
+	SocketReceiveBufferSize string = "SocketReceiveBufferSize"
+
+	// SocketKeepAlivePeriod sets the interval between TCP keepalive probes. A period of 0 disables keepalive.
+	//
+	// Required: No
+	//
+	// Default: 15s
+	//
+	// Valid Values:
+	//  - A positive integer number of seconds or a valid go time.Duration
+	//  - 0
+	SocketKeepAlivePeriod string = "SocketKeepAlivePeriod"
 )
 
 const (
@@ -728,6 +1126,7 @@ const (
 	// SocketCertificateFile is the filepath for the certificate to use for secure TLS connections.
 	// Must be used with SocketPrivateKeyFile.
 	// Must contain PEM encoded data.
+	// The certificate and private key are reloaded when either file changes, without restarting sessions.
 	//
 	// Required: No
 	//
@@ -806,6 +1205,8 @@ const (
 
 	// SocketServerName sets the expected server name on a returned certificate, unless SocketInsecureSkipVerify is true.
 	// This is for the TLS Server Name Indication extension.
+	// In config files you can also set SocketServerName<n> for the matching SocketConnectHost<n>.
+	// If not set, the host of SocketConnectHost<n> is used.
 	// Only used for initiators.
 	//
 	// Required: No
@@ -827,8 +1228,60 @@ const (
 	//  - TLS10
 	//  - TLS11
 	//  - TLS12
+	//  - TLS13
 	SocketMinimumTLSVersion string = "SocketMinimumTLSVersion"
 
+	// SocketMaximumTLSVersion specifies the maximum TLS version to use when creating a secure connection.
+	//
+	// Required: No
+	//
+	// Default: The highest version supported by Go
+	//
+	// Valid Values:
+	//  - TLS10
+	//  - TLS11
+	//  - TLS12
+	//  - TLS13
+	SocketMaximumTLSVersion string = "SocketMaximumTLSVersion"
+
+	// SocketCipherSuites restricts the cipher suites used for TLS 1.0 to 1.2 connections.
+	// The cipher suites of TLS 1.3 are not configurable.
+	//
+	// Required: No
+	//
+	// Default: The cipher suites Go considers secure
+	//
+	// Valid Values:
+	//  - A comma separated list of cipher suite names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+	SocketCipherSuites string = "SocketCipherSuites"
+
+	// SocketPrivateKeyPassword is the password of an encrypted SocketPrivateKeyFile or SocketPrivateKeyBytes.
+	// Both PKCS#8 encrypted keys and legacy OpenSSL encrypted PEM keys are supported.
+	//
+	// Required: No
+	//
+	// Valid Values:
+	//  - Any string
+	SocketPrivateKeyPassword string = "SocketPrivateKeyPassword"
+
+	// SocketPKCS12File is the path of a PKCS#12 bundle holding the private key, certificate and certificate chain
+	// to use for secure TLS communications, in place of SocketPrivateKeyFile and SocketCertificateFile.
+	// Like those files it is reloaded when it changes.
+	//
+	// Required: No
+	//
+	// Valid Values:
+	//  - A valid path
+	SocketPKCS12File string = "SocketPKCS12File"
+
+	// SocketPKCS12Password is the password of SocketPKCS12File.
+	//
+	// Required: No
+	//
+	// Valid Values:
+	//  - Any string
+	SocketPKCS12Password string = "SocketPKCS12Password"
+
 	// SocketUseSSL if set to Y, an initiator will use TLS even if client certificates are not present.
 	// It is set to N by default, meaning TLS will not be used if SocketPrivateKeyFile or SocketCertificateFile are not supplied.
 	//
@@ -843,6 +1296,35 @@ const (
 	//  - Y
 	//  - N
 	SocketUseSSL string = "SocketUseSSL"
+
+	// SocketCertificateCompIDBinding binds the client certificate presented on a TLS connection to the SenderCompID
+	// of the counterparty, so that one client cannot log on as another. The certificate is checked against the SenderCompID
+	// of the first message of the connection before its session is chosen, the connection is closed if it does not match.
+	// Connections without a client certificate are closed.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: NONE
+	//
+	// Valid Values:
+	//  - NONE the certificate is not checked
+	//  - CN the subject common name of the certificate must match
+	//  - SAN one of the DNS subject alternative names of the certificate must match
+	//  - ANY the common name or one of the DNS subject alternative names must match
+	SocketCertificateCompIDBinding string = "SocketCertificateCompIDBinding"
+
+	// SocketCertificateIdentity lists the certificate names accepted by SocketCertificateCompIDBinding for the session,
+	// in place of the SenderCompID of the counterparty. Use it when certificate names differ from CompIDs.
+	// Used for acceptors only.
+	//
+	// Required: No
+	//
+	// Default: The TargetCompID of the session
+	//
+	// Valid Values:
+	//  - A comma separated list of names
+	SocketCertificateIdentity string = "SocketCertificateIdentity"
 )
 
 const (
@@ -973,6 +1455,33 @@ const (
 	//  - N
 	PersistMessages string = "PersistMessages"
 
+	// MessageStoreTimeout sets the deadline for storing each message sent and its MsgSeqNum, and the MsgSeqNum of each message
+	// received, for MessageStores implementing quickfix.MessageStoreWithContext such as sql.NewStoreFactory(..) and mongo.NewStoreFactory(..).
+	// A message that cannot be stored in time is not sent, and the session disconnects, as it does if the MsgSeqNum of a message
+	// received cannot be stored in time. As the outcome of the timed out write
+	// is unknown, consider RefreshOnLogon to reload the MsgSeqNum from the store on the next logon.
+	// Values are parsed as a time.Duration, e.g. 500ms or 2s.
+	//
+	// Required: No
+	//
+	// Default: 0, no deadline
+	//
+	// Valid Values:
+	//  - A positive duration
+	MessageStoreTimeout string = "MessageStoreTimeout"
+
+	// MessageStoreResendTimeout sets the deadline for reading and resending the messages requested by a ResendRequest,
+	// for MessageStores implementing quickfix.MessageStoreWithContext. The session disconnects if the resend does not complete in time.
+	// Values are parsed as a time.Duration, e.g. 30s.
+	//
+	// Required: No
+	//
+	// Default: 0, no deadline
+	//
+	// Valid Values:
+	//  - A positive duration
+	MessageStoreResendTimeout string = "MessageStoreResendTimeout"
+
 	// FileStorePath sets the directory path in which to write sequence number and message files.
 	// This will create the directory path if it does not already exist.
 	// FileStorePath is only relevant if also using file.NewStoreFactory(..) in code
@@ -1000,6 +1509,86 @@ const (
 	//  - N
 	FileStoreSync string = "FileStoreSync"
 
+	// FileStoreSegmentSize sets the size in bytes at which the FileStore starts a new segment, a new pair of body and header files,
+	// for the messages it stores. Old segments are removed according to FileStoreRetentionDays and FileStoreRetentionSeqNums.
+	// FileStoreSegmentSize is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0, a single segment that grows until the store is reset
+	//
+	// Valid Values:
+	//  - Any positive integer
+	FileStoreSegmentSize string = "FileStoreSegmentSize"
+
+	// FileStoreSegmentMessages sets the number of messages after which the FileStore starts a new segment,
+	// see FileStoreSegmentSize. A segment is started when either limit is reached.
+	// FileStoreSegmentMessages is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0, no limit
+	//
+	// Valid Values:
+	//  - Any positive integer
+	FileStoreSegmentMessages string = "FileStoreSegmentMessages"
+
+	// FileStoreRetentionDays removes the FileStore segments that were last written to more than this many days ago.
+	// Segments are removed when a message is stored and when the store is refreshed, the current segment is never removed.
+	// Messages that are removed can no longer be resent, they are answered with a gap fill.
+	// FileStoreRetentionDays is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0, segments are kept until the store is reset
+	//
+	// Valid Values:
+	//  - Any positive integer
+	FileStoreRetentionDays string = "FileStoreRetentionDays"
+
+	// FileStoreRetentionSeqNums removes the FileStore segments whose messages all have a MsgSeqNum more than this many
+	// below the next sender MsgSeqNum. Segments are removed as with FileStoreRetentionDays.
+	// FileStoreRetentionSeqNums is only relevant if also using file.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: 0, segments are kept until the store is reset
+	//
+	// Valid Values:
+	//  - Any positive integer
+	FileStoreRetentionSeqNums string = "FileStoreRetentionSeqNums"
+
+	// BoltStorePath sets the directory path in which to write the embedded database file of each session,
+	// holding its sequence numbers and messages. This will create the directory path if it does not already exist.
+	// BoltStorePath is only relevant if also using bolt.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: Only if using an embedded database as your MessageStore
+	//
+	// Default: N/A
+	//
+	// Valid Values:
+	//  - A valid path
+	BoltStorePath string = "BoltStorePath"
+
+	// BoltStoreSync controls whether the BoltStore syncs to the hard drive on every commit.
+	// Without syncing the database stays consistent, but the last writes may be lost on a crash of the host.
+	// BoltStoreSync is only relevant if also using bolt.NewStoreFactory(..) in code
+	// when creating your MessageStoreFactory for your initiator or acceptor.
+	//
+	// Required: No
+	//
+	// Default: Y
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	BoltStoreSync string = "BoltStoreSync"
+
 	// SQLStoreDriver sets the name of the database driver to use for message storage (see https://go.dev/wiki/SQLDrivers for the list of available drivers).
 	// SQLStoreDriver is only relevant if also using sql.NewStoreFactory(..) in code
 	// when creating your MessageStoreFactory for your initiator or acceptor.
@@ -1160,3 +1749,45 @@ const (
 	//  - N
 	EnableNextExpectedMsgSeqNum string = "EnableNextExpectedMsgSeqNum"
 )
+
+const (
+	// Admin server settings.
+
+	// AdminServerAddress enables the embedded admin HTTP server and sets the address it listens on.
+	// The admin server lists the sessions of the engine's registry and lets operators inspect and
+	// correct their sequence numbers and state. Only read from the [DEFAULT] section.
+	//
+	// Common examples:
+	//  - 127.0.0.1:8088 (only reachable from the local host)
+	//
+	// Required: No
+	//
+	// Default: N/A (admin server disabled)
+	//
+	// Valid Values:
+	//  - A host:port address to listen on
+	AdminServerAddress string = "AdminServerAddress"
+
+	// AdminServerToken sets a bearer token that requests to the admin server must present
+	// in the Authorization header. Only read from the [DEFAULT] section.
+	//
+	// Required: Unless AdminServerAddress is a loopback address or AdminServerInsecure is Y
+	//
+	// Default: N/A (no authorization)
+	//
+	// Valid Values:
+	//  - Any string
+	AdminServerToken string = "AdminServerToken"
+
+	// AdminServerInsecure allows the admin server to listen on an address other than loopback without AdminServerToken,
+	// so that anyone able to reach it can control the sessions. Only read from the [DEFAULT] section.
+	//
+	// Required: No
+	//
+	// Default: N
+	//
+	// Valid Values:
+	//  - Y
+	//  - N
+	AdminServerInsecure string = "AdminServerInsecure"
+)
@@ -15,21 +15,172 @@
 
 package quickfix
 
-import "io"
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
-func writeLoop(connection io.Writer, messageOut chan []byte, log Log) {
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// peerCertificates returns the certificates presented by the counterparty on a TLS connection,
+// looking through wrapping connections such as those of a PROXY protocol listener.
+func peerCertificates(netConn net.Conn) []*x509.Certificate {
 	for {
-		msg, ok := <-messageOut
-		if !ok {
-			return
+		switch conn := netConn.(type) {
+		case *tls.Conn:
+			return conn.ConnectionState().PeerCertificates
+		case interface{ Raw() net.Conn }:
+			netConn = conn.Raw()
+		default:
+			return nil
 		}
+	}
+}
 
-		if _, err := connection.Write(msg); err != nil {
-			log.OnEvent(err.Error())
+const (
+	defaultWriteBufferSize = 4096
+	defaultFlushInterval   = time.Millisecond
+
+	// Number of messages a session can queue for the writer of its connection, so that a burst
+	// of messages is coalesced into few writes.
+	messageOutQueueSize = 64
+)
+
+// writeFailedError is returned by reads of a connection after a write to it failed.
+type writeFailedError struct {
+	err error
+}
+
+func (e writeFailedError) Error() string { return "write failed: " + e.err.Error() }
+
+func (e writeFailedError) Unwrap() error { return e.err }
+
+// monitoredConn reports a failed write to the reader of a connection. The connection is closed, so that
+// a pending read returns, and reads then fail with a writeFailedError holding the error of the write.
+type monitoredConn struct {
+	net.Conn
+	closeOnce sync.Once
+	closeErr  error
+	// writeFailedError of the first failed write.
+	failed atomic.Value
+}
+
+func newMonitoredConn(netConn net.Conn) *monitoredConn {
+	return &monitoredConn{Conn: netConn}
+}
+
+func (c *monitoredConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if err != nil {
+		c.failed.CompareAndSwap(nil, writeFailedError{err: err})
+		c.Close()
+	}
+	return n, err
+}
+
+func (c *monitoredConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil {
+		if failed, ok := c.failed.Load().(writeFailedError); ok {
+			return n, failed
+		}
+	}
+	return n, err
+}
+
+// Close closes the connection once, later calls return the result of the first.
+func (c *monitoredConn) Close() error {
+	c.closeOnce.Do(func() { c.closeErr = c.Conn.Close() })
+	return c.closeErr
+}
+
+// NetConn returns the wrapped connection.
+func (c *monitoredConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// writer coalesces the messages of a session into buffered writes to its connection, see config.SocketFlushPolicy.
+type writer struct {
+	connection io.Writer
+	bufferSize int
+	immediate  bool
+	interval   time.Duration
+}
+
+func newWriter(connection io.Writer) *writer {
+	return &writer{connection: connection, bufferSize: defaultWriteBufferSize}
+}
+
+// configure applies the writer settings of a session, before the write loop is started.
+func (w *writer) configure(settings internal.SessionSettings) {
+	w.bufferSize = defaultWriteBufferSize
+	if settings.WriteBufferSize > 0 {
+		w.bufferSize = settings.WriteBufferSize
+	}
+	w.immediate = settings.FlushImmediately
+	w.interval = settings.FlushInterval
+}
+
+// writeLoop writes the messages of messageOut until it is closed. A failed write is reported to the reader
+// by the monitoredConn of the connection. The remaining messages are then discarded, so that the session
+// is never blocked sending to a dead connection.
+func (w *writer) writeLoop(messageOut <-chan []byte) {
+	buffered := bufio.NewWriterSize(w.connection, w.bufferSize)
+	var err error
+	flush := func() {
+		if err == nil && buffered.Buffered() > 0 {
+			err = buffered.Flush()
+		}
+	}
+
+	timer := time.NewTimer(w.interval)
+	timer.Stop()
+	defer timer.Stop()
+	var flushTimer <-chan time.Time
+
+	for {
+		select {
+		case msg, ok := <-messageOut:
+			if !ok {
+				flush()
+				return
+			}
+
+			if err != nil {
+				continue
+			}
+			if _, err = buffered.Write(msg); err != nil {
+				continue
+			}
+
+			switch {
+			case w.immediate:
+				flush()
+			case w.interval > 0:
+				if flushTimer == nil {
+					timer.Reset(w.interval)
+					flushTimer = timer.C
+				}
+			case len(messageOut) == 0:
+				flush()
+			}
+
+		case <-flushTimer:
+			flushTimer = nil
+			flush()
 		}
 	}
 }
 
+// readLoop reads messages from parser until it fails. Messages over the inbound rate limit of the parser are marked
+// as breaches, and reading pauses after them.
 func readLoop(parser *parser, msgIn chan fixIn, log Log) {
 	defer close(msgIn)
 
@@ -37,8 +188,31 @@ func readLoop(parser *parser, msgIn chan fixIn, log Log) {
 		msg, err := parser.ReadMessage()
 		if err != nil {
 			log.OnEvent(err.Error())
+
+			var tooLarge MessageTooLargeError
+			var writeFailed writeFailedError
+			if errors.As(err, &tooLarge) || errors.As(err, &writeFailed) {
+				msgIn <- fixIn{err: err}
+			}
 			return
 		}
-		msgIn <- fixIn{msg, parser.lastRead}
+
+		in := fixIn{bytes: msg, receiveTime: parser.lastRead, breach: parser.checkLimits()}
+		if parser.disconnectWhenQueueFull == nil {
+			msgIn <- in
+		} else {
+			select {
+			case msgIn <- in:
+			default:
+				err := QueueFullError{Inbound: true, Limit: cap(msgIn)}
+				log.OnEvent(err.Error())
+				parser.disconnectWhenQueueFull(err)
+				return
+			}
+		}
+
+		if in.breach != nil {
+			time.Sleep(parser.limiter.wait(time.Now()))
+		}
 	}
 }
@@ -17,8 +17,19 @@ package quickfix
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/internal"
 )
 
 func TestWriteLoop(t *testing.T) {
@@ -31,7 +42,7 @@ func TestWriteLoop(t *testing.T) {
 		msgOut <- []byte("test msg 3")
 		close(msgOut)
 	}()
-	writeLoop(writer, msgOut, nullLog{})
+	newWriter(writer).writeLoop(msgOut)
 
 	expected := "test msg 1 test msg 2 test msg 3"
 
@@ -40,6 +51,117 @@ func TestWriteLoop(t *testing.T) {
 	}
 }
 
+// countingWriter records each write, failing once err is set.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+	err    error
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.Buffer.Write(b)
+}
+
+func queueMessages(count int) chan []byte {
+	msgOut := make(chan []byte, count)
+	for i := 0; i < count; i++ {
+		msgOut <- []byte("test msg ")
+	}
+	close(msgOut)
+	return msgOut
+}
+
+func TestWriterFlushPolicy(t *testing.T) {
+	var tests = []struct {
+		name           string
+		settings       internal.SessionSettings
+		expectedWrites int
+	}{
+		{"idle", internal.SessionSettings{}, 1},
+		{"immediate", internal.SessionSettings{FlushImmediately: true}, 10},
+		{"interval", internal.SessionSettings{FlushInterval: time.Hour}, 1},
+		{"buffer full", internal.SessionSettings{WriteBufferSize: 36}, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			connection := new(countingWriter)
+			w := newWriter(connection)
+			w.configure(test.settings)
+			w.writeLoop(queueMessages(10))
+
+			assert.Equal(t, strings.Repeat("test msg ", 10), connection.String())
+			assert.Equal(t, test.expectedWrites, connection.writes)
+		})
+	}
+}
+
+func TestWriterFlushInterval(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	w := newWriter(client)
+	w.configure(internal.SessionSettings{FlushInterval: 10 * time.Millisecond})
+	msgOut := make(chan []byte)
+	go w.writeLoop(msgOut)
+	defer close(msgOut)
+
+	sent := time.Now()
+	msgOut <- []byte("test msg 1 ")
+	msgOut <- []byte("test msg 2")
+
+	buf := make([]byte, 64)
+	n, err := server.Read(buf)
+	require.Nil(t, err)
+	assert.Equal(t, "test msg 1 test msg 2", string(buf[:n]))
+	assert.GreaterOrEqual(t, time.Since(sent), 10*time.Millisecond)
+}
+
+func TestWriterDiscardsAfterFailure(t *testing.T) {
+	connection := &countingWriter{err: errors.New("broken pipe")}
+	w := newWriter(connection)
+	w.configure(internal.SessionSettings{FlushImmediately: true})
+	w.writeLoop(queueMessages(10))
+
+	assert.Equal(t, 1, connection.writes)
+}
+
+// failingWriteConn fails every write with err.
+type failingWriteConn struct {
+	net.Conn
+	err error
+}
+
+func (c failingWriteConn) Write([]byte) (int, error) { return 0, c.err }
+
+func TestReadLoopWriteFailed(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	writeErr := errors.New("connection reset by peer")
+	conn := newMonitoredConn(failingWriteConn{Conn: client, err: writeErr})
+	msgIn := make(chan fixIn)
+	go readLoop(newParser(conn), msgIn, nullLog{})
+
+	msgOut := make(chan []byte, 1)
+	msgOut <- []byte("test msg")
+	close(msgOut)
+	newWriter(conn).writeLoop(msgOut)
+
+	msg := <-msgIn
+	var writeFailed writeFailedError
+	require.True(t, errors.As(msg.err, &writeFailed), "expected writeFailedError, got %v", msg.err)
+	assert.ErrorIs(t, msg.err, writeErr)
+
+	_, ok := <-msgIn
+	assert.False(t, ok)
+	assert.Nil(t, conn.Close())
+}
+
 func TestReadLoop(t *testing.T) {
 	msgIn := make(chan fixIn)
 	stream := "hello8=FIX.4.09=5blah10=103garbage8=FIX.4.09=4foo10=103"
@@ -71,3 +193,107 @@ func TestReadLoop(t *testing.T) {
 		}
 	}
 }
+
+func TestReadLoopMessageTooLarge(t *testing.T) {
+	msgIn := make(chan fixIn)
+	stream := "8=FIX.4.0\x019=5\x01blah\x0110=103\x018=FIX.4.0\x019=100000\x01"
+
+	parser := newParser(strings.NewReader(stream))
+	parser.maxMessageSize = 100
+	go readLoop(parser, msgIn, nullLog{})
+
+	msg := <-msgIn
+	if msg.err != nil || msg.bytes.String() != "8=FIX.4.0\x019=5\x01blah\x0110=103\x01" {
+		t.Errorf("Expected first message, got %v %v", msg.bytes, msg.err)
+	}
+
+	msg = <-msgIn
+	if _, ok := msg.err.(MessageTooLargeError); !ok {
+		t.Errorf("Expected MessageTooLargeError, got %v", msg.err)
+	}
+
+	if _, ok := <-msgIn; ok {
+		t.Error("Expected channel to be closed")
+	}
+}
+
+// writeEachMessage is the write loop without coalescing, one write per message.
+func writeEachMessage(connection io.Writer, messageOut <-chan []byte) {
+	for msg := range messageOut {
+		if _, err := connection.Write(msg); err != nil {
+			return
+		}
+	}
+}
+
+// benchmarkWriteLoop sends b.N messages in bursts of burst messages through loop to a TCP connection.
+func benchmarkWriteLoop(b *testing.B, burst int, loop func(io.Writer, <-chan []byte)) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(b, err)
+	defer listener.Close()
+
+	received := make(chan int64)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		n, _ := io.Copy(io.Discard, conn)
+		received <- n
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(b, err)
+
+	msg := []byte("8=FIX.4.4\x019=76\x0135=D\x0134=2\x0149=TW\x0152=20240101-00:00:00.000\x0156=ISLD\x0111=ID\x0121=1\x0140=1\x0154=1\x0155=INTC\x0110=000\x01")
+	msgOut := make(chan []byte, messageOutQueueSize)
+	done := make(chan struct{})
+	go func() {
+		loop(conn, msgOut)
+		conn.Close()
+		close(done)
+	}()
+
+	b.SetBytes(int64(len(msg)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msgOut <- msg
+		if (i+1)%burst == 0 {
+			// The writer catches up between bursts.
+			runtime.Gosched()
+		}
+	}
+	close(msgOut)
+	<-done
+	<-received
+}
+
+func BenchmarkWriteLoop(b *testing.B) {
+	policies := []struct {
+		name     string
+		settings internal.SessionSettings
+	}{
+		{"Immediate", internal.SessionSettings{FlushImmediately: true}},
+		{"Idle", internal.SessionSettings{}},
+		{"Interval", internal.SessionSettings{FlushInterval: defaultFlushInterval}},
+	}
+
+	for _, burst := range []int{1, 16} {
+		b.Run(fmt.Sprintf("Burst%d/PerMessage", burst), func(b *testing.B) {
+			benchmarkWriteLoop(b, burst, writeEachMessage)
+		})
+
+		for _, policy := range policies {
+			b.Run(fmt.Sprintf("Burst%d/%v", burst, policy.name), func(b *testing.B) {
+				benchmarkWriteLoop(b, burst, func(connection io.Writer, messageOut <-chan []byte) {
+					w := newWriter(connection)
+					w.configure(policy.settings)
+					w.writeLoop(messageOut)
+				})
+			})
+		}
+	}
+}
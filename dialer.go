@@ -16,8 +16,17 @@
 package quickfix
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
 	"time"
 
 	"golang.org/x/net/proxy"
@@ -25,6 +34,12 @@ import (
 	"github.com/quickfixgo/quickfix/config"
 )
 
+// forwardDialer makes the connections of a dialer, directly or to a proxy.
+type forwardDialer interface {
+	proxy.Dialer
+	proxy.ContextDialer
+}
+
 func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, err error) {
 	stdDialer := &net.Dialer{}
 	if settings.HasSetting(config.SocketTimeout) {
@@ -40,7 +55,38 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 			stdDialer.Timeout = timeout
 		}
 	}
-	dialer = stdDialer
+
+	var options socketOptions
+	if options, err = parseSocketOptions(settings); err != nil {
+		return stdDialer, err
+	}
+	stdDialer.KeepAlive = options.keepAlivePeriod
+
+	if settings.HasSetting(config.SocketLocalHost) || settings.HasSetting(config.SocketLocalPort) {
+		var localHost string
+		var localPort int
+		if settings.HasSetting(config.SocketLocalHost) {
+			if localHost, err = settings.Setting(config.SocketLocalHost); err != nil {
+				return stdDialer, err
+			}
+		}
+		if settings.HasSetting(config.SocketLocalPort) {
+			if localPort, err = settings.IntSetting(config.SocketLocalPort); err != nil {
+				return stdDialer, err
+			}
+		}
+
+		if stdDialer.LocalAddr, err = net.ResolveTCPAddr("tcp", net.JoinHostPort(localHost, strconv.Itoa(localPort))); err != nil {
+			return stdDialer, err
+		}
+	}
+
+	// The connection to the counterparty, or to the proxy.
+	var forward forwardDialer = stdDialer
+	if options.tuned() {
+		forward = &tcpDialer{Dialer: stdDialer, options: options}
+	}
+	dialer = forward
 
 	if !settings.HasSetting(config.ProxyType) {
 		return
@@ -75,7 +121,7 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 
 		var proxyDialer proxy.Dialer
 
-		proxyDialer, err = proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyHost, proxyPort), proxyAuth, stdDialer)
+		proxyDialer, err = proxy.SOCKS5("tcp", fmt.Sprintf("%s:%d", proxyHost, proxyPort), proxyAuth, forward)
 		if err != nil {
 			return
 		}
@@ -87,9 +133,161 @@ func loadDialerConfig(settings *SessionSettings) (dialer proxy.ContextDialer, er
 			return
 		}
 
+	case "http", "https":
+		var proxyHost string
+		var proxyPort int
+		if proxyHost, err = settings.Setting(config.ProxyHost); err != nil {
+			return
+		} else if proxyPort, err = settings.IntSetting(config.ProxyPort); err != nil {
+			return
+		}
+
+		httpDialer := &httpProxyDialer{proxyAddress: net.JoinHostPort(proxyHost, strconv.Itoa(proxyPort)), forward: forward, timeout: stdDialer.Timeout}
+		if settings.HasSetting(config.ProxyUser) {
+			if httpDialer.user, err = settings.Setting(config.ProxyUser); err != nil {
+				return
+			}
+		}
+		if settings.HasSetting(config.ProxyPassword) {
+			if httpDialer.password, err = settings.Setting(config.ProxyPassword); err != nil {
+				return
+			}
+		}
+
+		if proxyType == "https" {
+			httpDialer.tlsConfig = &tls.Config{ServerName: proxyHost, MinVersion: tls.VersionTLS12}
+			if settings.HasSetting(config.ProxyCAFile) {
+				var caFile string
+				if caFile, err = settings.Setting(config.ProxyCAFile); err != nil {
+					return
+				}
+
+				var pem []byte
+				if pem, err = os.ReadFile(caFile); err != nil {
+					err = fmt.Errorf("failed to read proxy CA bundle: %w", err)
+					return
+				}
+
+				httpDialer.tlsConfig.RootCAs = x509.NewCertPool()
+				if !httpDialer.tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+					err = fmt.Errorf("failed to parse %v", caFile)
+					return
+				}
+			}
+		}
+		dialer = httpDialer
+
 	default:
 		err = fmt.Errorf("unsupported proxy type %s", proxyType)
 	}
 
 	return
 }
+
+// httpProxyDialer connects through an HTTP proxy with the CONNECT method.
+type httpProxyDialer struct {
+	proxyAddress string
+	// Used for the connection to the proxy, nil to connect to the proxy without TLS.
+	tlsConfig *tls.Config
+	// Sent with basic authentication if user is set.
+	user, password string
+	forward        forwardDialer
+	// Limit for the whole exchange with the proxy, 0 for none.
+	timeout time.Duration
+}
+
+// Dial implements proxy.Dialer.
+func (d *httpProxyDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext implements proxy.ContextDialer.
+func (d *httpProxyDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	conn, err := d.forward.DialContext(ctx, network, d.proxyAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn, err = d.connect(ctx, conn, addr); err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connect asks the proxy on conn to open a tunnel to addr.
+func (d *httpProxyDialer) connect(ctx context.Context, conn net.Conn, addr string) (net.Conn, error) {
+	// Unblock reads and writes when ctx is done.
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return conn, err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	if d.tlsConfig != nil {
+		tlsConn := tls.Client(conn, d.tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return conn, err
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.user != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(d.user + ":" + d.password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return conn, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return conn, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return conn, fmt.Errorf("proxy %v refused to connect to %v: %v", d.proxyAddress, addr, resp.Status)
+	}
+
+	if !stop() {
+		return conn, ctx.Err()
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return conn, err
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn reads the data already buffered from a connection before reading the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
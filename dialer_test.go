@@ -16,7 +16,14 @@
 package quickfix
 
 import (
+	"context"
+	"encoding/pem"
+	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -90,3 +97,173 @@ func (s *DialerTestSuite) TestLoadDialerSocksProxyInvalidPort() {
 	_, err := loadDialerConfig(s.settings.GlobalSettings())
 	s.Require().NotNil(err)
 }
+
+func (s *DialerTestSuite) TestLoadDialerHTTPProxy() {
+	for _, proxyType := range []string{"http", "https"} {
+		s.SetupTest()
+		s.settings.GlobalSettings().Set(config.ProxyType, proxyType)
+		s.settings.GlobalSettings().Set(config.ProxyHost, "localhost")
+		s.settings.GlobalSettings().Set(config.ProxyPort, "3128")
+		s.settings.GlobalSettings().Set(config.ProxyUser, "user")
+		s.settings.GlobalSettings().Set(config.ProxyPassword, "secret")
+		dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+		s.Require().Nil(err)
+
+		httpDialer, ok := dialer.(*httpProxyDialer)
+		s.Require().True(ok)
+		s.Equal("localhost:3128", httpDialer.proxyAddress)
+		s.Equal("user", httpDialer.user)
+		s.Equal("secret", httpDialer.password)
+		s.Equal(proxyType == "https", httpDialer.tlsConfig != nil)
+	}
+
+	s.settings.GlobalSettings().Set(config.ProxyCAFile, "missing")
+	_, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+// newConnectProxy returns a proxy answering CONNECT requests with status, then writing greeting to the tunnel.
+func (s *DialerTestSuite) newConnectProxy(status int, greeting string) (*httptest.Server, chan *http.Request) {
+	requests := make(chan *http.Request, 1)
+	proxy := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+		if r.Method != http.MethodConnect || status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		s.Require().NoError(err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 200 Connection established\r\n\r\n" + greeting)
+		_ = rw.Flush()
+		_, _ = io.Copy(io.Discard, conn)
+	}))
+	return proxy, requests
+}
+
+func (s *DialerTestSuite) setProxy(proxyType string, proxy *httptest.Server) {
+	host, port, err := net.SplitHostPort(proxy.Listener.Addr().String())
+	s.Require().NoError(err)
+	s.settings.GlobalSettings().Set(config.ProxyType, proxyType)
+	s.settings.GlobalSettings().Set(config.ProxyHost, host)
+	s.settings.GlobalSettings().Set(config.ProxyPort, port)
+}
+
+func (s *DialerTestSuite) TestHTTPProxyConnect() {
+	proxy, requests := s.newConnectProxy(http.StatusOK, "hello")
+	proxy.Start()
+	defer proxy.Close()
+
+	s.setProxy("http", proxy)
+	s.settings.GlobalSettings().Set(config.ProxyUser, "user")
+	s.settings.GlobalSettings().Set(config.ProxyPassword, "secret")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "fix.example.com:5001")
+	s.Require().Nil(err)
+	defer conn.Close()
+
+	req := <-requests
+	s.Equal(http.MethodConnect, req.Method)
+	s.Equal("fix.example.com:5001", req.Host)
+	s.Equal("Basic dXNlcjpzZWNyZXQ=", req.Header.Get("Proxy-Authorization"))
+
+	// Data sent by the counterparty with the proxy response is not lost.
+	greeting := make([]byte, 5)
+	_, err = io.ReadFull(conn, greeting)
+	s.Nil(err)
+	s.Equal("hello", string(greeting))
+}
+
+func (s *DialerTestSuite) TestHTTPSProxyConnect() {
+	proxy, requests := s.newConnectProxy(http.StatusOK, "")
+	proxy.StartTLS()
+	defer proxy.Close()
+
+	caFile := filepath.Join(s.T().TempDir(), "proxy-ca.crt")
+	s.Require().NoError(os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: proxy.Certificate().Raw}), 0600))
+
+	s.setProxy("https", proxy)
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	// The proxy certificate is not trusted without ProxyCAFile.
+	_, err = dialer.DialContext(context.Background(), "tcp", "fix.example.com:5001")
+	s.NotNil(err)
+
+	s.settings.GlobalSettings().Set(config.ProxyCAFile, caFile)
+	dialer, err = loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "fix.example.com:5001")
+	s.Require().Nil(err)
+	defer conn.Close()
+
+	req := <-requests
+	s.Equal("fix.example.com:5001", req.Host)
+	s.Empty(req.Header.Get("Proxy-Authorization"))
+}
+
+func (s *DialerTestSuite) TestHTTPProxyConnectRefused() {
+	proxy, _ := s.newConnectProxy(http.StatusProxyAuthRequired, "")
+	proxy.Start()
+	defer proxy.Close()
+
+	s.setProxy("http", proxy)
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	_, err = dialer.DialContext(context.Background(), "tcp", "fix.example.com:5001")
+	s.Require().NotNil(err)
+	s.Contains(err.Error(), "407")
+}
+
+func (s *DialerTestSuite) TestLoadDialerSocketOptions() {
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "127.0.0.1")
+	s.settings.GlobalSettings().Set(config.SocketLocalPort, "0")
+	s.settings.GlobalSettings().Set(config.SocketKeepAlivePeriod, "0")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	stdDialer, ok := dialer.(*net.Dialer)
+	s.Require().True(ok)
+	s.Equal(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, stdDialer.LocalAddr)
+	s.Negative(stdDialer.KeepAlive)
+
+	s.settings.GlobalSettings().Set(config.SocketNodelay, "N")
+	dialer, err = loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	tuned, ok := dialer.(*tcpDialer)
+	s.Require().True(ok)
+	s.False(tuned.options.noDelay)
+	s.NotNil(tuned.LocalAddr)
+
+	s.settings.GlobalSettings().Set(config.SocketLocalPort, "port")
+	_, err = loadDialerConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *DialerTestSuite) TestDialFromSocketLocalHost() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	s.settings.GlobalSettings().Set(config.SocketLocalHost, "127.0.0.1")
+	s.settings.GlobalSettings().Set(config.SocketSendBufferSize, "65536")
+	dialer, err := loadDialerConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", listener.Addr().String())
+	s.Require().Nil(err)
+	defer conn.Close()
+	s.Equal("127.0.0.1", conn.LocalAddr().(*net.TCPAddr).IP.String())
+}
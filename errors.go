@@ -23,6 +23,10 @@ import (
 // ErrDoNotSend is a convenience error to indicate a DoNotSend in ToApp.
 var ErrDoNotSend = errors.New("Do Not Send")
 
+// ErrThrottled is returned by SendToTarget when the outbound throttle of the session rejects the message,
+// or its queue is full, see config.ThrottlePolicy.
+var ErrThrottled = errors.New("message throttled")
+
 // rejectReason enum values.
 const (
 	rejectReasonInvalidTagNumber                          = 0
@@ -40,6 +44,7 @@ const (
 	rejectReasonTagSpecifiedOutOfRequiredOrder            = 14
 	rejectReasonRepeatingGroupFieldsOutOfOrder            = 15
 	rejectReasonIncorrectNumInGroupCountForRepeatingGroup = 16
+	rejectReasonOther                                     = 99
 )
 
 // MessageRejectError is a type of error that can correlate to a message reject.
@@ -0,0 +1,83 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// failover selects the SocketConnectAddress for each connection attempt of an initiator session.
+// The first address is the primary. The current address is used until it fails threshold times in a row,
+// then the next address is used. Once failbackInterval has passed on a backup address, the primary is tried again
+// on the next connection attempt, and the primary is probed while connected to a backup, see Initiator.waitForDisconnect.
+type failover struct {
+	addresses        int
+	threshold        int
+	failbackInterval time.Duration
+
+	current  int
+	failures int
+	// When current moved off the primary.
+	failedOverAt time.Time
+}
+
+func newFailover(addresses, threshold int, failbackInterval time.Duration) *failover {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &failover{addresses: addresses, threshold: threshold, failbackInterval: failbackInterval}
+}
+
+// next returns the index of the address for the next connection attempt.
+func (f *failover) next(now time.Time) int {
+	if f.current != 0 && f.failbackInterval > 0 && now.Sub(f.failedOverAt) >= f.failbackInterval {
+		f.current = 0
+		f.failures = 0
+	}
+	return f.current
+}
+
+// failed records a failed connection attempt to the current address.
+func (f *failover) failed(now time.Time) {
+	f.failures++
+	if f.failures < f.threshold {
+		return
+	}
+
+	f.failures = 0
+	f.current = (f.current + 1) % f.addresses
+	if f.current == 1 {
+		f.failedOverAt = now
+	}
+}
+
+// connected records a successful connection to the current address.
+func (f *failover) connected() {
+	f.failures = 0
+}
+
+// failbackIn returns how long until the primary should be tried again, false if the current address is the primary
+// or failback is disabled.
+func (f *failover) failbackIn(now time.Time) (time.Duration, bool) {
+	if f.current == 0 || f.failbackInterval <= 0 {
+		return 0, false
+	}
+	return max(f.failbackInterval-now.Sub(f.failedOverAt), 0), true
+}
+
+// failback moves the current address back to the primary.
+func (f *failover) failback() {
+	f.current = 0
+	f.failures = 0
+}
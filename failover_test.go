@@ -0,0 +1,165 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverSticksToConnectedAddress(t *testing.T) {
+	f := newFailover(3, 1, 0)
+	now := time.Now()
+
+	assert.Equal(t, 0, f.next(now))
+	f.connected()
+	assert.Equal(t, 0, f.next(now), "a disconnect alone does not fail over")
+
+	f.failed(now)
+	assert.Equal(t, 1, f.next(now))
+	f.failed(now)
+	assert.Equal(t, 2, f.next(now))
+	f.failed(now)
+	assert.Equal(t, 0, f.next(now), "wraps around to the primary")
+}
+
+func TestFailoverThreshold(t *testing.T) {
+	f := newFailover(2, 3, 0)
+	now := time.Now()
+
+	f.failed(now)
+	f.failed(now)
+	assert.Equal(t, 0, f.next(now))
+
+	f.connected()
+	f.failed(now)
+	f.failed(now)
+	assert.Equal(t, 0, f.next(now), "failures are counted consecutively")
+
+	f.failed(now)
+	assert.Equal(t, 1, f.next(now))
+}
+
+func TestFailoverFailback(t *testing.T) {
+	f := newFailover(2, 1, time.Minute)
+	now := time.Now()
+
+	f.failed(now)
+	assert.Equal(t, 1, f.next(now))
+	f.connected()
+	assert.Equal(t, 1, f.next(now.Add(30*time.Second)))
+	assert.Equal(t, 0, f.next(now.Add(time.Minute)), "primary is tried again after the failback interval")
+
+	f.failed(now.Add(time.Minute))
+	assert.Equal(t, 1, f.next(now.Add(90*time.Second)))
+	assert.Equal(t, 0, f.next(now.Add(2*time.Minute)))
+}
+
+func TestFailoverSingleAddress(t *testing.T) {
+	f := newFailover(1, 0, time.Minute)
+	now := time.Now()
+
+	f.failed(now)
+	assert.Equal(t, 0, f.next(now))
+}
+
+func TestFailoverFailbackIn(t *testing.T) {
+	f := newFailover(2, 1, time.Minute)
+	now := time.Now()
+
+	_, ok := f.failbackIn(now)
+	assert.False(t, ok, "on the primary")
+
+	f.failed(now)
+	wait, ok := f.failbackIn(now.Add(20 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 40*time.Second, wait)
+
+	wait, ok = f.failbackIn(now.Add(2 * time.Minute))
+	assert.True(t, ok)
+	assert.Zero(t, wait)
+
+	f.failback()
+	assert.Equal(t, 0, f.next(now))
+	_, ok = newFailover(2, 1, 0).failbackIn(now)
+	assert.False(t, ok, "failback disabled")
+}
+
+// probeDialer fails the first dials, then connects.
+type probeDialer struct {
+	mu       sync.Mutex
+	failures int
+	dials    int
+}
+
+func (d *probeDialer) DialContext(_ context.Context, _, _ string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.dials++
+	if d.dials <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func TestInitiatorFailbackProbe(t *testing.T) {
+	endpoints := newFailover(2, 1, 20*time.Millisecond)
+	endpoints.failed(time.Now())
+	endpoints.connected()
+
+	s := &session{log: nullLog{}, admin: make(chan interface{})}
+	running := make(chan struct{})
+	s.status.setRunning(running)
+	defer close(running)
+
+	i := &Initiator{stopChan: make(chan interface{})}
+	disconnected := make(chan interface{})
+	dialer := &probeDialer{failures: 1}
+	result := make(chan bool)
+	go func() {
+		result <- i.waitForDisconnect(s, disconnected, nil, endpoints, "primary:5001", dialer)
+	}()
+
+	// The connection to the backup is logged out once the primary accepts connections.
+	select {
+	case req := <-s.admin:
+		logout, ok := req.(logoutReq)
+		require.True(t, ok)
+		assert.Equal(t, "Failing back to primary", logout.reason)
+		logout.rep <- nil
+	case <-time.After(time.Second):
+		t.Fatal("session did not fail back")
+	}
+
+	dialer.mu.Lock()
+	assert.Equal(t, 2, dialer.dials)
+	dialer.mu.Unlock()
+
+	close(disconnected)
+	assert.True(t, <-result)
+	assert.Equal(t, 0, endpoints.next(time.Now()))
+}
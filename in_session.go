@@ -56,7 +56,7 @@ func (state inSession) FixMsgIn(session *session, msg *Message) sessionState {
 		}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(); err != nil {
 		return handleStateError(session, err)
 	}
 
@@ -91,8 +91,17 @@ func (state inSession) handleLogout(session *session, msg *Message) (nextState s
 		return state.processReject(session, msg, err)
 	}
 
+	var text FIXString
+	if msg.Body.Has(tagText) {
+		if err := msg.Body.GetField(tagText, &text); err != nil {
+			session.logError(err)
+		}
+	}
+	session.setDisconnectReason("Logout")
+
 	if session.IsLoggedOn() {
 		session.log.OnEvent("Received logout request")
+		session.emit(SessionEvent{Type: SessionLoggedOut, Reason: string(text)})
 		session.log.OnEvent("Sending logout response")
 
 		if err := session.sendLogoutInReplyTo("", msg); err != nil {
@@ -117,7 +126,7 @@ func (state inSession) handleLogout(session *session, msg *Message) (nextState s
 		return latentState{}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(); err != nil {
 		session.logError(err)
 	}
 
@@ -140,7 +149,7 @@ func (state inSession) handleTestRequest(session *session, msg *Message) (nextSt
 		}
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(); err != nil {
 		return handleStateError(session, err)
 	}
 	return state
@@ -162,10 +171,11 @@ func (state inSession) handleSequenceReset(session *session, msg *Message) (next
 	if err := msg.Body.GetField(tagNewSeqNo, &newSeqNo); err == nil {
 		expectedSeqNum := FIXInt(session.store.NextTargetMsgSeqNum())
 		session.log.OnEventf("Received SequenceReset FROM: %v TO: %v", expectedSeqNum, newSeqNo)
+		session.emit(SessionEvent{Type: SessionSequenceReset, BeginSeqNo: int(expectedSeqNum), EndSeqNo: int(newSeqNo)})
 
 		switch {
 		case newSeqNo > expectedSeqNum:
-			if err := session.store.SetNextTargetMsgSeqNum(int(newSeqNo)); err != nil {
+			if err := session.setNextTargetMsgSeqNum(int(newSeqNo)); err != nil {
 				return handleStateError(session, err)
 			}
 		case newSeqNo < expectedSeqNum:
@@ -199,6 +209,8 @@ func (state inSession) handleResendRequest(session *session, msg *Message) (next
 	endSeqNo := int(endSeqNoField)
 
 	session.log.OnEventf("Received ResendRequest FROM: %d TO: %d", beginSeqNo, endSeqNo)
+	session.emit(SessionEvent{Type: SessionResendRequested, BeginSeqNo: int(beginSeqNo), EndSeqNo: endSeqNo})
+	session.metrics.ResendRequestReceived(session.sessionID)
 	expectedSeqNum := session.store.NextSenderMsgSeqNum()
 
 	if (session.sessionID.BeginString >= BeginStringFIX42 && endSeqNo == 0) ||
@@ -219,7 +231,7 @@ func (state inSession) handleResendRequest(session *session, msg *Message) (next
 		return state
 	}
 
-	if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+	if err := session.incrNextTargetMsgSeqNum(); err != nil {
 		return handleStateError(session, err)
 	}
 	return state
@@ -238,7 +250,7 @@ func (state inSession) resendMessages(session *session, beginSeqNo, endSeqNo int
 	seqNum := beginSeqNo
 	nextSeqNum := seqNum
 	msg := NewMessage()
-	err := session.store.IterateMessages(beginSeqNo, endSeqNo, func(msgBytes []byte) error {
+	err := session.iterateSentMessages(beginSeqNo, endSeqNo, func(msgBytes []byte) error {
 		err := ParseMessageWithDataDictionary(msg, bytes.NewBuffer(msgBytes), session.transportDataDictionary, session.appDataDictionary)
 		if err != nil {
 			session.log.OnEventf("Resend Msg Parse Error: %v, %v", err.Error(), bytes.NewBuffer(msgBytes).String())
@@ -333,7 +345,7 @@ func (state inSession) processReject(session *session, msg *Message, rej Message
 			return handleStateError(session, err)
 		}
 
-		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+		if err := session.incrNextTargetMsgSeqNum(); err != nil {
 			return handleStateError(session, err)
 		}
 		return state
@@ -413,6 +425,7 @@ func (state *inSession) generateSequenceReset(session *session, beginSeqNo int,
 
 	session.EnqueueBytesAndSend(msgBytes)
 	session.log.OnEventf("Sent SequenceReset TO: %v", endSeqNo)
+	session.metrics.GapFillSent(session.sessionID)
 
 	return
 }
@@ -237,6 +237,37 @@ func (s *InSessionTestSuite) TestFIXMsgInTargetTooHighResendRequestChunkSize() {
 	}
 }
 
+func (s *InSessionTestSuite) TestFIXMsgInResendRequestMessageStoreTimeout() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+	s.LastToAppMessageSent()
+
+	s.session.store = stalledStore{s.session.store}
+	s.session.StoreResendTimeout = time.Millisecond
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.session, s.ResendRequest(1))
+
+	s.MockApp.AssertExpectations(s.T())
+	s.NoMessageSent()
+	s.State(latentState{})
+	s.Disconnected()
+}
+
+func (s *InSessionTestSuite) TestFIXMsgInMessageStoreTimeout() {
+	s.session.store = stalledStore{s.session.store}
+	s.session.StoreTimeout = time.Millisecond
+	s.MockApp.On("FromApp").Return(nil)
+	s.MockApp.On("OnLogout")
+	s.fixMsgIn(s.session, s.NewOrderSingle())
+
+	s.MockApp.AssertExpectations(s.T())
+	s.NoMessageSent()
+	s.State(latentState{})
+	s.Disconnected()
+	s.NextTargetMsgSeqNum(1)
+}
+
 func (s *InSessionTestSuite) TestFIXMsgInResendRequestAllAdminExpectGapFill() {
 	s.MockApp.On("ToAdmin")
 	s.session.Timeout(s.session, internal.NeedHeartbeat)
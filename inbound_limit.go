@@ -0,0 +1,171 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type inboundLimitPolicy int
+
+const (
+	inboundLogout inboundLimitPolicy = iota
+	inboundReject
+	inboundDisconnect
+)
+
+// inboundLimitSettings are the parsed Inbound* settings, see config.InboundMaxMessages.
+type inboundLimitSettings struct {
+	// Largest message in bytes, 0 for no limit.
+	maxMessageSize int
+
+	// Messages per interval, 0 for no limit.
+	maxMessages int
+	interval    time.Duration
+	policy      inboundLimitPolicy
+}
+
+func parseInboundLimitSettings(settings *SessionSettings) (limits inboundLimitSettings, err error) {
+	limits.interval = time.Second
+	if settings.HasSetting(config.InboundMaxMessageSize) {
+		if limits.maxMessageSize, err = settings.IntSetting(config.InboundMaxMessageSize); err != nil {
+			return
+		}
+
+		if limits.maxMessageSize <= 0 {
+			err = errors.New("InboundMaxMessageSize must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.InboundMaxMessages) {
+		if limits.maxMessages, err = settings.IntSetting(config.InboundMaxMessages); err != nil {
+			return
+		}
+
+		if limits.maxMessages <= 0 {
+			err = errors.New("InboundMaxMessages must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.InboundInterval) {
+		if limits.interval, err = settings.DurationSetting(config.InboundInterval); err != nil {
+			var intervalInt int
+			if intervalInt, err = settings.IntSetting(config.InboundInterval); err != nil {
+				return
+			}
+			limits.interval = time.Duration(intervalInt) * time.Second
+		}
+
+		if limits.interval <= 0 {
+			err = errors.New("InboundInterval must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.InboundLimitPolicy) {
+		var policy string
+		if policy, err = settings.Setting(config.InboundLimitPolicy); err != nil {
+			return
+		}
+
+		switch policy {
+		case "LOGOUT":
+			limits.policy = inboundLogout
+		case "REJECT":
+			limits.policy = inboundReject
+		case "DISCONNECT":
+			limits.policy = inboundDisconnect
+		default:
+			err = IncorrectFormatForSetting{Setting: config.InboundLimitPolicy, Value: []byte(policy)}
+		}
+	}
+
+	return
+}
+
+// inboundLimiter applies the InboundMaxMessages limit of a session to the messages read by the parser of one connection.
+// InboundMaxMessageSize is applied by the parser while a message is read, see parser.configure.
+type inboundLimiter struct {
+	limits inboundLimitSettings
+	bucket *tokenBucket
+}
+
+func newInboundLimiter(limits inboundLimitSettings, now time.Time) *inboundLimiter {
+	l := &inboundLimiter{limits: limits}
+	if limits.maxMessages > 0 {
+		l.bucket = newTokenBucket(limits.maxMessages, limits.interval, now)
+	}
+	return l
+}
+
+// check returns an error if a message read at now is over the rate limit, nil if it is within limits.
+func (l *inboundLimiter) check(now time.Time) error {
+	if l.bucket != nil && !l.bucket.take(now) {
+		return fmt.Errorf("more than InboundMaxMessages %v received in %v", l.limits.maxMessages, l.limits.interval)
+	}
+	return nil
+}
+
+// wait returns how long reading should pause after a message over the rate limit, so that the connection
+// is read no faster than the limit allows.
+func (l *inboundLimiter) wait(now time.Time) time.Duration {
+	if l.bucket == nil {
+		return 0
+	}
+
+	l.bucket.refill(now)
+	if l.bucket.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.bucket.tokens) * float64(l.bucket.interval) / l.bucket.capacity)
+}
+
+// inboundLimitExceeded handles a message over the inbound limits of the session according to InboundLimitPolicy.
+func (s *session) inboundLimitExceeded(msg *Message, breach error) sessionState {
+	s.log.OnEventf("Inbound limit exceeded: %v", breach)
+
+	switch s.inboundLimits.policy {
+	case inboundReject:
+		msgType, err := msg.Header.GetBytes(tagMsgType)
+		state, ok := s.State.(inSession)
+		if err != nil || !ok || isAdminMessageType(msgType) {
+			// Admin messages keep the session alive, the connection is only slowed down.
+			return s.State.FixMsgIn(s, msg)
+		}
+
+		if rej := s.verifySelect(msg, true, true, false); rej != nil {
+			return state.processReject(s, msg, rej)
+		}
+		return state.processReject(s, msg, NewMessageRejectError(breach.Error(), rejectReasonOther, nil))
+
+	case inboundLogout:
+		if s.IsLoggedOn() {
+			if err := s.initiateLogoutInReplyTo(breach.Error(), msg); err != nil {
+				return handleStateError(s, err)
+			}
+			return logoutState{}
+		}
+	}
+
+	s.setDisconnectReason(breach.Error())
+	return latentState{}
+}
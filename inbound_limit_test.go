@@ -0,0 +1,192 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+func TestParseInboundLimitSettings(t *testing.T) {
+	settings := NewSessionSettings()
+	limits, err := parseInboundLimitSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, inboundLimitSettings{interval: time.Second, policy: inboundLogout}, limits)
+
+	settings.Set(config.InboundMaxMessageSize, "4096")
+	settings.Set(config.InboundMaxMessages, "100")
+	settings.Set(config.InboundInterval, "10s")
+	settings.Set(config.InboundLimitPolicy, "REJECT")
+	limits, err = parseInboundLimitSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, inboundLimitSettings{maxMessageSize: 4096, maxMessages: 100, interval: 10 * time.Second, policy: inboundReject}, limits)
+
+	settings.Set(config.InboundInterval, "2")
+	settings.Set(config.InboundLimitPolicy, "DISCONNECT")
+	limits, err = parseInboundLimitSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, 2*time.Second, limits.interval)
+	assert.Equal(t, inboundDisconnect, limits.policy)
+
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.InboundMaxMessageSize, "0"},
+		{config.InboundMaxMessages, "-1"},
+		{config.InboundMaxMessages, "lots"},
+		{config.InboundInterval, "0s"},
+		{config.InboundLimitPolicy, "IGNORE"},
+	}
+
+	for _, test := range tests {
+		s := settings.clone()
+		s.Set(test.setting, test.value)
+		_, err = parseInboundLimitSettings(s)
+		assert.NotNil(t, err, test.setting+"="+test.value)
+	}
+}
+
+func TestInboundLimiter(t *testing.T) {
+	now := time.Now()
+	l := newInboundLimiter(inboundLimitSettings{maxMessageSize: 100, maxMessages: 2, interval: time.Second}, now)
+
+	assert.Nil(t, l.check(now))
+	assert.Nil(t, l.check(now))
+	assert.NotNil(t, l.check(now), "too many")
+	assert.Equal(t, 500*time.Millisecond, l.wait(now))
+	assert.Zero(t, l.wait(now.Add(500*time.Millisecond)))
+	assert.Nil(t, l.check(now.Add(500*time.Millisecond)))
+
+	l = newInboundLimiter(inboundLimitSettings{interval: time.Second}, now)
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, l.check(now))
+	}
+	assert.Zero(t, l.wait(now))
+}
+
+func TestReadLoopInboundLimit(t *testing.T) {
+	stream := "8=FIX.4.2\x019=10\x0135=A\x0134=1\x0110=000\x01" +
+		"8=FIX.4.2\x019=10\x0135=0\x0134=2\x0110=000\x01"
+	msgIn := make(chan fixIn)
+	parser := newParser(bytes.NewReader([]byte(stream)))
+	parser.configure(internal.SessionSettings{}, inboundLimitSettings{maxMessages: 1, interval: 50 * time.Millisecond}, nullLog{})
+
+	go readLoop(parser, msgIn, nullLog{})
+
+	first := <-msgIn
+	assert.Nil(t, first.breach)
+	second := <-msgIn
+	assert.NotNil(t, second.breach)
+
+	_, ok := <-msgIn
+	assert.False(t, ok)
+}
+
+func TestReadLoopInboundMaxMessageSize(t *testing.T) {
+	// The second message is refused from its BodyLength, before its body is read.
+	stream := "8=FIX.4.2\x019=10\x0135=A\x0134=1\x0110=000\x01" +
+		"8=FIX.4.2\x019=100000\x0135=D\x01"
+	msgIn := make(chan fixIn)
+	parser := newParser(bytes.NewReader([]byte(stream)))
+	parser.configure(internal.SessionSettings{MaxMessageSize: 1 << 20}, inboundLimitSettings{maxMessageSize: 64}, nullLog{})
+
+	go readLoop(parser, msgIn, nullLog{})
+
+	first := <-msgIn
+	assert.Nil(t, first.err)
+	assert.Nil(t, first.breach)
+
+	var tooLarge MessageTooLargeError
+	second := <-msgIn
+	require.ErrorAs(t, second.err, &tooLarge)
+	assert.Equal(t, 64, tooLarge.MaxMessageSize)
+	assert.Equal(t, config.InboundMaxMessageSize, tooLarge.Setting)
+	assert.Contains(t, second.err.Error(), "exceeds InboundMaxMessageSize 64")
+
+	_, ok := <-msgIn
+	assert.False(t, ok)
+}
+
+type InboundLimitTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestInboundLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(InboundLimitTestSuite))
+}
+
+func (s *InboundLimitTestSuite) SetupTest() {
+	s.Init()
+	s.session.State = inSession{}
+}
+
+func (s *InboundLimitTestSuite) TestReject() {
+	s.session.inboundLimits.policy = inboundReject
+	s.MockApp.On("ToAdmin")
+
+	s.session.setState(s.session, s.session.inboundLimitExceeded(s.NewOrderSingle(), errors.New("too many")))
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeReject), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "too many", s.MockApp.lastToAdmin.Body)
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InboundLimitTestSuite) TestRejectProcessesAdminMessages() {
+	s.session.inboundLimits.policy = inboundReject
+	s.MockApp.On("FromAdmin").Return(nil)
+
+	s.session.setState(s.session, s.session.inboundLimitExceeded(s.Heartbeat(), errors.New("too many")))
+	s.MockApp.AssertExpectations(s.T())
+	s.State(inSession{})
+	s.NoMessageSent()
+	s.NextTargetMsgSeqNum(2)
+}
+
+func (s *InboundLimitTestSuite) TestLogout() {
+	s.session.inboundLimits.policy = inboundLogout
+	s.MockApp.On("ToAdmin")
+
+	s.session.setState(s.session, s.session.inboundLimitExceeded(s.NewOrderSingle(), errors.New("too large")))
+	s.MockApp.AssertExpectations(s.T())
+	s.State(logoutState{})
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "too large", s.MockApp.lastToAdmin.Body)
+}
+
+func (s *InboundLimitTestSuite) TestDisconnect() {
+	s.session.inboundLimits.policy = inboundDisconnect
+	s.MockApp.On("OnLogout")
+
+	nextState := s.session.inboundLimitExceeded(s.NewOrderSingle(), errors.New("too large"))
+	s.Equal("too large", s.session.disconnectReason)
+
+	s.session.setState(s.session, nextState)
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Disconnected()
+}
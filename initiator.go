@@ -19,6 +19,7 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -36,35 +37,196 @@ type Initiator struct {
 	globalLog       Log
 	stopChan        chan interface{}
 	wg              sync.WaitGroup
+	sessionsLock    sync.Mutex
 	sessions        map[SessionID]*session
+	handlers        map[SessionID]*initiatorHandler
+	adminServer     *adminServer
+	reconnectPolicy ReconnectPolicy
 	sessionFactory
 }
 
+// initiatorHandler tracks the connection goroutine of one session.
+type initiatorHandler struct {
+	// Closed to stop the handler of a removed session.
+	stopChan chan interface{}
+	// Closed when the handler has exited.
+	done chan interface{}
+	// Certificate of the session read from files, nil if it has none.
+	certificates *certificateLoader
+}
+
 // Start Initiator.
 func (i *Initiator) Start() (err error) {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
 	i.stopChan = make(chan interface{})
 
-	for sessionID, settings := range i.sessionSettings {
-		// TODO: move into session factory.
-		var tlsConfig *tls.Config
-		if tlsConfig, err = loadTLSConfig(settings); err != nil {
+	if i.adminServer, err = newAdminServer(i.settings.GlobalSettings(), i.Registry, i.globalLog); err != nil {
+		return
+	}
+	if i.adminServer != nil {
+		if err = i.adminServer.start(); err != nil {
 			return
 		}
+	}
 
-		var dialer proxy.ContextDialer
-		if dialer, err = loadDialerConfig(settings); err != nil {
+	for sessionID, settings := range i.sessionSettings {
+		if err = i.startHandler(sessionID, settings); err != nil {
 			return
 		}
-
-		i.wg.Add(1)
-		go func(sessID SessionID) {
-			i.handleConnection(i.sessions[sessID], tlsConfig, dialer)
-			i.wg.Done()
-		}(sessionID)
 	}
 	return
 }
 
+// startHandler starts the connection goroutine of a session, sessionsLock must be held.
+func (i *Initiator) startHandler(sessionID SessionID, settings *SessionSettings) error {
+	// TODO: move into session factory.
+	tlsConfig, certificates, err := loadTLSConfigWithCertificate(settings)
+	if err != nil {
+		return err
+	}
+
+	dialer, err := loadDialerConfig(settings)
+	if err != nil {
+		return err
+	}
+
+	h := &initiatorHandler{stopChan: make(chan interface{}), done: make(chan interface{}), certificates: certificates}
+	i.handlers[sessionID] = h
+
+	i.wg.Add(1)
+	go func(session *session) {
+		defer i.wg.Done()
+		defer close(h.done)
+		i.handleConnection(session, h.stopChan, tlsConfig, dialer)
+	}(i.sessions[sessionID])
+	return nil
+}
+
+// isRunning returns true if the Initiator has been started and not stopped.
+func (i *Initiator) isRunning() bool {
+	if i.stopChan == nil {
+		return false
+	}
+
+	select {
+	case <-i.stopChan:
+		return false
+	default:
+		return true
+	}
+}
+
+// AddSession creates a session from settings, overlaid on the global settings of the Initiator.
+// If the Initiator is running the session starts connecting immediately, otherwise it starts with the Initiator.
+func (i *Initiator) AddSession(sessionSettings *SessionSettings) (SessionID, error) {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
+	sessionID := sessionIDFromSessionSettings(i.settings.GlobalSettings(), sessionSettings)
+	settings := i.settings.GlobalSettings().clone()
+	settings.overlay(sessionSettings)
+
+	if _, dup := i.sessions[sessionID]; dup {
+		return sessionID, errDuplicateSessionID
+	}
+
+	session, err := i.createSession(sessionID, i.storeFactory, settings, i.logFactory, i.app)
+	if err != nil {
+		return sessionID, err
+	}
+	i.sessions[sessionID] = session
+	i.sessionSettings[sessionID] = settings
+
+	if i.isRunning() {
+		if err := i.startHandler(sessionID, settings); err != nil {
+			i.removeSession(sessionID)
+			_ = session.close()
+			_ = i.UnregisterSession(sessionID)
+			return sessionID, err
+		}
+	}
+
+	return sessionID, nil
+}
+
+// RemoveSession stops the session matching the session id, logging out if logged on, closes its store and log,
+// and unregisters it. The other sessions of the Initiator are not affected.
+func (i *Initiator) RemoveSession(sessionID SessionID) error {
+	i.sessionsLock.Lock()
+	session, ok := i.sessions[sessionID]
+	if !ok {
+		i.sessionsLock.Unlock()
+		return errUnknownSession
+	}
+
+	h := i.handlers[sessionID]
+	i.removeSession(sessionID)
+	i.sessionsLock.Unlock()
+
+	if h != nil {
+		close(h.stopChan)
+		<-h.done
+	}
+
+	// The session goroutine has exited, release the store so the session can be added again.
+	if err := session.close(); err != nil {
+		i.globalLog.OnEventf("Failed to close session %v: %v", sessionID, err)
+	}
+
+	return i.UnregisterSession(sessionID)
+}
+
+// Reload brings the running Initiator in line with settings, typically re-read from the original file with ParseSettings.
+// Sessions are added and removed to match the session sections of settings. Changed settings of a session are applied
+// without dropping its connection, unless connection settings such as SocketConnectHost changed, in which case the
+// session is restarted. Settings that require the Initiator to be recreated are listed in the returned report.
+// Log settings are applied to running sessions when a LogFactory created from settings is given with WithReloadLogFactory.
+func (i *Initiator) Reload(settings *Settings, opts ...ReloadOption) (*ReloadReport, error) {
+	options := newReloadOptions(opts)
+
+	i.sessionsLock.Lock()
+	current := make(map[SessionID]*SessionSettings, len(i.sessionSettings))
+	for sessionID, sessionSettings := range i.sessionSettings {
+		current[sessionID] = sessionSettings
+	}
+
+	plan, err := planReload(i.sessionFactory, i.settings.GlobalSettings(), current, settings, options, nil)
+	if err != nil {
+		i.sessionsLock.Unlock()
+		return nil, err
+	}
+	i.settings = settings
+	if options.logFactory != nil {
+		i.logFactory = options.logFactory
+	}
+	i.sessionsLock.Unlock()
+
+	return plan.apply(i)
+}
+
+func (i *Initiator) reconfigureSession(sessionID SessionID, from *session, settings *SessionSettings) error {
+	i.sessionsLock.Lock()
+	session, ok := i.sessions[sessionID]
+	if ok {
+		i.sessionSettings[sessionID] = settings
+	}
+	i.sessionsLock.Unlock()
+
+	if !ok {
+		return errUnknownSession
+	}
+	return session.reconfigure(from)
+}
+
+// removeSession forgets a session, sessionsLock must be held.
+func (i *Initiator) removeSession(sessionID SessionID) {
+	delete(i.sessions, sessionID)
+	delete(i.sessionSettings, sessionID)
+	delete(i.handlers, sessionID)
+}
+
 // Stop Initiator.
 func (i *Initiator) Stop() {
 	select {
@@ -75,8 +237,15 @@ func (i *Initiator) Stop() {
 	}
 	close(i.stopChan)
 
+	if i.adminServer != nil {
+		i.adminServer.stop()
+	}
+
 	i.wg.Wait()
 
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
 	for sessionID := range i.sessionSettings {
 		err := i.UnregisterSession(sessionID)
 		if err != nil {
@@ -85,6 +254,25 @@ func (i *Initiator) Stop() {
 	}
 }
 
+// ReloadCertificates reloads the certificates of the initiator's sessions from SocketCertificateFile and
+// SocketPrivateKeyFile, or SocketPKCS12File. New connections use the reloaded certificates, established connections are not affected.
+// The files are also reloaded when they change, calling ReloadCertificates is only needed to apply a change immediately.
+func (i *Initiator) ReloadCertificates() error {
+	i.sessionsLock.Lock()
+	defer i.sessionsLock.Unlock()
+
+	for sessionID, h := range i.handlers {
+		if h.certificates == nil {
+			continue
+		}
+
+		if err := h.certificates.reload(); err != nil {
+			return fmt.Errorf("%v: %w", sessionID, err)
+		}
+	}
+	return nil
+}
+
 type InitiatorOption func(*Initiator)
 
 func WithInitiatorRegistry(registry *Registry) InitiatorOption {
@@ -93,6 +281,21 @@ func WithInitiatorRegistry(registry *Registry) InitiatorOption {
 	}
 }
 
+// WithInitiatorMetrics sets the MetricsSink that receives measurements from the initiator's sessions.
+func WithInitiatorMetrics(sink MetricsSink) InitiatorOption {
+	return func(i *Initiator) {
+		i.sessionFactory.metricsSink = sink
+	}
+}
+
+// WithInitiatorReconnectPolicy sets the ReconnectPolicy for all sessions of the initiator,
+// in place of the one configured by the ReconnectInterval and ReconnectBackoffMultiplier settings.
+func WithInitiatorReconnectPolicy(policy ReconnectPolicy) InitiatorOption {
+	return func(i *Initiator) {
+		i.reconnectPolicy = policy
+	}
+}
+
 // NewInitiator creates and initializes a new Initiator.
 func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings *Settings, logFactory LogFactory, opts ...InitiatorOption) (*Initiator, error) {
 	i := &Initiator{
@@ -102,6 +305,7 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 		sessionSettings: appSettings.SessionSettings(),
 		logFactory:      logFactory,
 		sessions:        make(map[SessionID]*session),
+		handlers:        make(map[SessionID]*initiatorHandler),
 		sessionFactory:  sessionFactory{BuildInitiators: true, Registry: defaultRegistry},
 	}
 
@@ -127,8 +331,64 @@ func NewInitiator(app Application, storeFactory MessageStoreFactory, appSettings
 	return i, nil
 }
 
+// waitForDisconnect waits until the connection drops, returning false if the Initiator is stopped or stopChan is closed.
+// Connected to a backup address, the primary is probed each SocketConnectFailbackInterval. Once it accepts
+// a connection the session logs out, so that the next connection attempt fails back to the primary.
+func (i *Initiator) waitForDisconnect(session *session, disconnected <-chan interface{}, stopChan <-chan interface{},
+	endpoints *failover, primary string, dialer proxy.ContextDialer) bool {
+	var timer *time.Timer
+	var probe <-chan time.Time
+	if wait, ok := endpoints.failbackIn(time.Now()); ok {
+		timer = time.NewTimer(wait)
+		defer timer.Stop()
+		probe = timer.C
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	probeResult := make(chan error, 1)
+
+	for {
+		select {
+		case <-disconnected:
+			return true
+		case <-i.stopChan:
+			return false
+		case <-stopChan:
+			return false
+
+		case <-probe:
+			probe = nil
+			go func() {
+				probeCtx, probeCancel := context.WithTimeout(ctx, endpoints.failbackInterval)
+				defer probeCancel()
+
+				conn, err := dialer.DialContext(probeCtx, "tcp", primary)
+				if err == nil {
+					_ = conn.Close()
+				}
+				probeResult <- err
+			}()
+
+		case err := <-probeResult:
+			if err != nil {
+				session.log.OnEventf("Primary %v is not reachable: %v", primary, err)
+				timer.Reset(endpoints.failbackInterval)
+				probe = timer.C
+				continue
+			}
+
+			session.log.OnEventf("Primary %v is reachable, failing back", primary)
+			endpoints.failback()
+			if err := session.logout("Failing back to primary"); err != nil {
+				_ = session.disconnect()
+			}
+		}
+	}
+}
+
 // waitForInSessionTime returns true if the session is in session, false if the handler should stop.
-func (i *Initiator) waitForInSessionTime(session *session) bool {
+func (i *Initiator) waitForInSessionTime(session *session, stopChan <-chan interface{}) bool {
 	inSessionTime := make(chan interface{})
 	go func() {
 		session.waitForInSessionTime()
@@ -139,23 +399,31 @@ func (i *Initiator) waitForInSessionTime(session *session) bool {
 	case <-inSessionTime:
 	case <-i.stopChan:
 		return false
+	case <-stopChan:
+		return false
 	}
 
 	return true
 }
 
 // waitForReconnectInterval returns true if a reconnect should be re-attempted, false if handler should stop.
-func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration) bool {
+func (i *Initiator) waitForReconnectInterval(reconnectInterval time.Duration, stopChan <-chan interface{}) bool {
 	select {
 	case <-time.After(reconnectInterval):
 	case <-i.stopChan:
 		return false
+	case <-stopChan:
+		return false
 	}
 
 	return true
 }
 
-func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, dialer proxy.ContextDialer) {
+// handleConnection connects and reconnects session until the Initiator is stopped or stopChan is closed.
+func (i *Initiator) handleConnection(session *session, stopChan <-chan interface{}, tlsConfig *tls.Config, dialer proxy.ContextDialer) {
+	// Connection settings are not changed by reloaded settings, a change restarts the session.
+	settings := session.currentSettings()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -168,10 +436,16 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 		wg.Wait()
 	}()
 
-	connectionAttempt := 0
+	endpoints := newFailover(len(settings.SocketConnectAddress), settings.FailoverThreshold, settings.FailbackInterval)
+
+	reconnectPolicy := i.reconnectPolicy
+	if reconnectPolicy == nil {
+		reconnectPolicy = newBackoffReconnectPolicy(settings)
+	}
+	reconnectAttempt := 0
 
 	for {
-		if !i.waitForInSessionTime(session) {
+		if !i.waitForInSessionTime(session, stopChan) {
 			return
 		}
 
@@ -183,6 +457,8 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 			select {
 			case <-i.stopChan:
 				cancel()
+			case <-stopChan:
+				cancel()
 			case <-ctx.Done():
 				return
 			}
@@ -191,18 +467,27 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 		var disconnected chan interface{}
 		var msgIn chan fixIn
 		var msgOut chan []byte
+		var parser *parser
+		var conn *monitoredConn
+		var writer *writer
+		var connectedAt time.Time
 
-		address := session.SocketConnectAddress[connectionAttempt%len(session.SocketConnectAddress)]
+		endpoint := endpoints.next(time.Now())
+		address := settings.SocketConnectAddress[endpoint]
 		session.log.OnEventf("Connecting to: %v", address)
 
 		netConn, err := dialer.DialContext(ctx, "tcp", address)
 		if err != nil {
 			session.log.OnEventf("Failed to connect: %v", err)
+			endpoints.failed(time.Now())
 			goto reconnect
 		} else if tlsConfig != nil {
-			// Unless InsecureSkipVerify is true, server name config is required for TLS
-			// to verify the received certificate
-			if !tlsConfig.InsecureSkipVerify && len(tlsConfig.ServerName) == 0 {
+			tlsConfig := tlsConfig.Clone()
+			if endpoint < len(settings.SocketConnectServerName) && settings.SocketConnectServerName[endpoint] != "" {
+				tlsConfig.ServerName = settings.SocketConnectServerName[endpoint]
+			} else if !tlsConfig.InsecureSkipVerify && len(tlsConfig.ServerName) == 0 {
+				// Unless InsecureSkipVerify is true, server name config is required for TLS
+				// to verify the received certificate
 				serverName := address
 				if c := strings.LastIndex(serverName, ":"); c > 0 {
 					serverName = serverName[:c]
@@ -212,23 +497,31 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 			tlsConn := tls.Client(netConn, tlsConfig)
 			if err = tlsConn.Handshake(); err != nil {
 				session.log.OnEventf("Failed handshake: %v", err)
+				endpoints.failed(time.Now())
 				goto reconnect
 			}
 			netConn = tlsConn
 		}
 
-		msgIn = make(chan fixIn)
-		msgOut = make(chan []byte)
-		if err := session.connect(msgIn, msgOut); err != nil {
+		msgIn = make(chan fixIn, session.queueSettings.inboundLimit)
+		msgOut = make(chan []byte, messageOutQueueSize)
+		connectedAt = time.Now()
+		// Messages are read and written through conn, which reports a failed write to the read loop.
+		conn = newMonitoredConn(netConn)
+		parser = newParser(bufio.NewReader(conn))
+		writer = newWriter(conn)
+		if err := session.connect(msgIn, msgOut, netConn, address, parser, writer); err != nil {
 			session.log.OnEventf("Failed to initiate: %v", err)
 			goto reconnect
 		}
+		endpoints.connected()
+		session.log.OnEventf("Connected to: %v", address)
 
-		go readLoop(newParser(bufio.NewReader(netConn)), msgIn, session.log)
+		go readLoop(parser, msgIn, session.log)
 		disconnected = make(chan interface{})
 		go func() {
-			writeLoop(netConn, msgOut, session.log)
-			if err := netConn.Close(); err != nil {
+			writer.writeLoop(msgOut)
+			if err := conn.Close(); err != nil {
 				session.log.OnEvent(err.Error())
 			}
 			close(disconnected)
@@ -238,18 +531,25 @@ func (i *Initiator) handleConnection(session *session, tlsConfig *tls.Config, di
 		// dial cancelation after successful connection.
 		cancel()
 
-		select {
-		case <-disconnected:
-		case <-i.stopChan:
+		if !i.waitForDisconnect(session, disconnected, stopChan, endpoints, settings.SocketConnectAddress[0], dialer) {
 			return
 		}
 
 	reconnect:
 		cancel()
 
-		connectionAttempt++
-		session.log.OnEventf("Reconnecting in %v", session.ReconnectInterval)
-		if !i.waitForReconnectInterval(session.ReconnectInterval) {
+		if next := endpoints.next(time.Now()); next != endpoint {
+			session.log.OnEventf("Failing over from %v to %v", address, settings.SocketConnectAddress[next])
+		}
+
+		if settings.ReconnectResetOnLogon && !connectedAt.IsZero() && session.status.lastLogonTime().After(connectedAt) {
+			reconnectAttempt = 0
+		}
+		reconnectAttempt++
+
+		reconnectInterval := reconnectPolicy.ReconnectInterval(session.sessionID, reconnectAttempt)
+		session.log.OnEventf("Reconnecting in %v", reconnectInterval)
+		if !i.waitForReconnectInterval(reconnectInterval, stopChan) {
 			return
 		}
 	}
@@ -21,12 +21,38 @@ type SessionSettings struct {
 	ResetSeqTime                 TimeOfDay
 	EnableResetSeqTime           bool
 
+	// Deadlines of MessageStoreWithContext operations, zero for none.
+	StoreTimeout       time.Duration
+	StoreResendTimeout time.Duration
+
+	// Applied by the parser of each connection.
+	MaxMessageSize         int
+	ResyncOnCorruptMessage bool
+
+	// Applied by the writer of each connection, see config.SocketFlushPolicy.
+	WriteBufferSize  int
+	FlushImmediately bool
+	// Zero to flush once no more messages are queued.
+	FlushInterval time.Duration
+
 	// Required on logon for FIX.T.1 messages.
 	DefaultApplVerID string
 
 	// Specific to initiators.
-	ReconnectInterval    time.Duration
-	LogoutTimeout        time.Duration
-	LogonTimeout         time.Duration
-	SocketConnectAddress []string
+	ReconnectInterval time.Duration
+	// Backoff between reconnects, see config.ReconnectBackoffMultiplier.
+	ReconnectBackoffMultiplier float64
+	ReconnectMaxInterval       time.Duration
+	ReconnectJitter            float64
+	ReconnectResetOnLogon      bool
+	LogoutTimeout              time.Duration
+	LogonTimeout               time.Duration
+	SocketConnectAddress       []string
+
+	// TLS server name for each SocketConnectAddress, empty if not set for the address.
+	SocketConnectServerName []string
+
+	// Failover between SocketConnectAddress entries.
+	FailoverThreshold int
+	FailbackInterval  time.Duration
 }
@@ -232,3 +232,36 @@ func (s *StoreTestSuite) TestMessageStoreCreationTime() {
 	s.Require().True(s.MsgStore.CreationTime().After(t0))
 	s.Require().True(s.MsgStore.CreationTime().Before(t1))
 }
+
+func (s *StoreTestSuite) TestMessageStoreCopy() {
+	// Given a store with the following state
+	src, err := quickfix.NewMemoryStoreFactory().Create(quickfix.SessionID{})
+	s.Require().Nil(err)
+	creationTime := time.Date(2024, time.March, 1, 8, 30, 0, 0, time.UTC)
+	src.SetCreationTime(creationTime)
+	s.Require().Nil(src.SaveMessageAndIncrNextSenderMsgSeqNum(1, []byte("8=FIX.4.4\x019=10\x0135=0\x0134=1\x0110=000\x01")))
+	s.Require().Nil(src.IncrNextSenderMsgSeqNum())
+	s.Require().Nil(src.SaveMessageAndIncrNextSenderMsgSeqNum(3, []byte("8=FIX.4.4\x019=10\x0135=0\x0134=3\x0110=000\x01")))
+	s.Require().Nil(src.SetNextTargetMsgSeqNum(12))
+
+	// And stale state in the MessageStore
+	s.Require().Nil(s.MsgStore.SaveMessage(2, []byte("stale")))
+	s.Require().Nil(s.MsgStore.SetNextSenderMsgSeqNum(99))
+
+	// When the state is copied to the MessageStore
+	s.Require().Nil(quickfix.CopyMessageStore(s.MsgStore, src))
+
+	// Then the MessageStore should have the state of the source, also after it is refreshed from its backing store
+	for i := 0; i < 2; i++ {
+		s.Equal(4, s.MsgStore.NextSenderMsgSeqNum())
+		s.Equal(12, s.MsgStore.NextTargetMsgSeqNum())
+		s.True(creationTime.Equal(s.MsgStore.CreationTime()), s.MsgStore.CreationTime())
+
+		msgs := s.fetchMessages(1, 3)
+		s.Require().Len(msgs, 2)
+		s.Equal("8=FIX.4.4\x019=10\x0135=0\x0134=1\x0110=000\x01", string(msgs[0]))
+		s.Equal("8=FIX.4.4\x019=10\x0135=0\x0134=3\x0110=000\x01", string(msgs[1]))
+
+		s.Require().Nil(s.MsgStore.Refresh())
+	}
+}
@@ -15,6 +15,11 @@
 
 package quickfix
 
+import (
+	"io"
+	"sync"
+)
+
 // Log is a generic interface for logging FIX messages and events.
 type Log interface {
 	// OnIncoming log incoming fix message.
@@ -38,3 +43,58 @@ type LogFactory interface {
 	// CreateSessionLog session specific log.
 	CreateSessionLog(sessionID SessionID) (Log, error)
 }
+
+// swappableLog is the Log of a session, the Log it writes to is replaced when reloaded settings change the logging
+// of a running session.
+type swappableLog struct {
+	mu  sync.RWMutex
+	log Log
+}
+
+func (l *swappableLog) OnIncoming(msg []byte) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.log.OnIncoming(msg)
+}
+
+func (l *swappableLog) OnOutgoing(msg []byte) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.log.OnOutgoing(msg)
+}
+
+func (l *swappableLog) OnEvent(msg string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.log.OnEvent(msg)
+}
+
+func (l *swappableLog) OnEventf(format string, v ...interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.log.OnEventf(format, v...)
+}
+
+// swap replaces the Log written to, and closes the previous Log if it is an io.Closer.
+func (l *swappableLog) swap(log Log) error {
+	l.mu.Lock()
+	previous := l.log
+	l.log = log
+	l.mu.Unlock()
+
+	if closer, ok := previous.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Close closes the Log written to if it is an io.Closer.
+func (l *swappableLog) Close() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if closer, ok := l.log.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -16,6 +16,8 @@
 package composite
 
 import (
+	"io"
+
 	"github.com/quickfixgo/quickfix"
 )
 
@@ -47,6 +49,18 @@ func (l compositeLog) OnEventf(format string, a ...interface{}) {
 	}
 }
 
+// Close closes each log that is an io.Closer, returning the first error.
+func (l compositeLog) Close() (err error) {
+	for _, log := range l.logs {
+		if closer, ok := log.(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return
+}
+
 type compositeLogFactory struct {
 	logFactories []quickfix.LogFactory
 }
@@ -26,6 +26,8 @@ import (
 )
 
 type fileLog struct {
+	eventFile     *os.File
+	messageFile   *os.File
 	eventLogger   *log.Logger
 	messageLogger *log.Logger
 }
@@ -46,6 +48,15 @@ func (l fileLog) OnEventf(format string, v ...interface{}) {
 	l.eventLogger.Printf(format, v...)
 }
 
+// Close closes the log files.
+func (l fileLog) Close() error {
+	eventErr := l.eventFile.Close()
+	if err := l.messageFile.Close(); err != nil {
+		return err
+	}
+	return eventErr
+}
+
 type fileLogFactory struct {
 	globalLogPath   string
 	sessionLogPaths map[quickfix.SessionID]string
@@ -92,9 +103,12 @@ func newFileLog(prefix string, logPath string) (fileLog, error) {
 
 	messageFile, err := os.OpenFile(messageLogName, fileFlags, os.ModePerm)
 	if err != nil {
+		eventFile.Close()
 		return l, err
 	}
 
+	l.eventFile = eventFile
+	l.messageFile = messageFile
 	logFlag := log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC
 	l.eventLogger = log.New(eventFile, "", logFlag)
 	l.messageLogger = log.New(messageFile, "", logFlag)
@@ -145,6 +145,13 @@ func (l mongoLog) OnEventf(format string, v ...interface{}) {
 	l.insert(l.eventLogCollection, []byte(fmt.Sprintf(format, v...)))
 }
 
+// Close disconnects the log from the database.
+func (l mongoLog) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return l.db.Disconnect(ctx)
+}
+
 func generateEntry(s *quickfix.SessionID) (entry *entryData) {
 	entry = &entryData{
 		BeginString:      s.BeginString,
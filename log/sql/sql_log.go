@@ -159,6 +159,11 @@ func (l sqlLog) OnEventf(format string, v ...interface{}) {
 	l.insert("event_log", fmt.Sprintf(format, v...))
 }
 
+// Close closes the database connection of the log.
+func (l sqlLog) Close() error {
+	return l.db.Close()
+}
+
 func (l sqlLog) insert(table string, value string) {
 	s := l.sessionID
 
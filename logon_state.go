@@ -33,11 +33,19 @@ func (s logonState) FixMsgIn(session *session, msg *Message) (nextState sessionS
 
 	if !bytes.Equal(msgType, msgTypeLogon) {
 		session.log.OnEventf("Invalid Session State: Received Msg %s while waiting for Logon", msg)
+		session.setDisconnectReason("Received Msg while waiting for Logon")
 		return latentState{}
 	}
 
 	if err := session.handleLogon(msg); err != nil {
 		switch err := err.(type) {
+		case authenticationFailed:
+			logout := session.buildLogout(err.Error())
+			if session.sessionID.BeginString == BeginStringFIXT11 {
+				logout.Body.SetField(tagSessionStatus, FIXInt(err.status))
+			}
+			return shutdownWithLogout(session, msg, true, err.Error(), logout)
+
 		case RejectLogon:
 			return shutdownWithReason(session, msg, true, err.Error())
 
@@ -63,6 +71,7 @@ func (s logonState) Timeout(session *session, e internal.Event) (nextState sessi
 	switch e {
 	case internal.LogonTimeout:
 		session.log.OnEvent("Timed out waiting for logon response")
+		session.setDisconnectReason("Timed out waiting for logon response")
 		return latentState{}
 	}
 	return s
@@ -73,15 +82,19 @@ func (s logonState) Stop(_ *session) (nextState sessionState) {
 }
 
 func shutdownWithReason(session *session, msg *Message, incrNextTargetMsgSeqNum bool, reason string) (nextState sessionState) {
-	session.log.OnEvent(reason)
-	logout := session.buildLogout(reason)
+	return shutdownWithLogout(session, msg, incrNextTargetMsgSeqNum, reason, session.buildLogout(reason))
+}
 
+func shutdownWithLogout(session *session, msg *Message, incrNextTargetMsgSeqNum bool, reason string, logout *Message) (nextState sessionState) {
+	session.log.OnEvent(reason)
 	if err := session.dropAndSendInReplyTo(logout, msg); err != nil {
 		session.logError(err)
 	}
+	session.emit(SessionEvent{Type: SessionLoggedOut, Reason: reason})
+	session.setDisconnectReason(reason)
 
 	if incrNextTargetMsgSeqNum {
-		if err := session.store.IncrNextTargetMsgSeqNum(); err != nil {
+		if err := session.incrNextTargetMsgSeqNum(); err != nil {
 			session.logError(err)
 		}
 	}
@@ -34,6 +34,7 @@ func (state logoutState) Timeout(session *session, event internal.Event) (nextSt
 	switch event {
 	case internal.LogoutTimeout:
 		session.log.OnEvent("Timed out waiting for logout response")
+		session.setDisconnectReason("Timed out waiting for logout response")
 		return latentState{}
 	}
 
@@ -0,0 +1,53 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// MetricsSink receives session level measurements. Implementations must be safe for concurrent use
+// and should return quickly, as they are called from the session goroutine.
+type MetricsSink interface {
+	// MessageIn counts a message received from the counterparty.
+	MessageIn(sessionID SessionID, msgType string)
+
+	// MessageOut counts a message written to the counterparty.
+	MessageOut(sessionID SessionID, msgType string)
+
+	// Reject counts a Reject(3) or BusinessMessageReject(j) sent to the counterparty, by reject reason.
+	Reject(sessionID SessionID, msgType string, reason int)
+
+	// ResendRequestSent counts a ResendRequest sent to the counterparty.
+	ResendRequestSent(sessionID SessionID)
+
+	// ResendRequestReceived counts a ResendRequest received from the counterparty.
+	ResendRequestReceived(sessionID SessionID)
+
+	// GapFillSent counts a SequenceReset-GapFill sent to the counterparty.
+	GapFillSent(sessionID SessionID)
+
+	// HeartbeatLatency records the delay between the SendingTime(52) of a received Heartbeat and its receipt.
+	HeartbeatLatency(sessionID SessionID, latency time.Duration)
+}
+
+type nullMetrics struct{}
+
+func (nullMetrics) MessageIn(SessionID, string)               {}
+func (nullMetrics) MessageOut(SessionID, string)              {}
+func (nullMetrics) Reject(SessionID, string, int)             {}
+func (nullMetrics) ResendRequestSent(SessionID)               {}
+func (nullMetrics) ResendRequestReceived(SessionID)           {}
+func (nullMetrics) GapFillSent(SessionID)                     {}
+func (nullMetrics) HeartbeatLatency(SessionID, time.Duration) {}
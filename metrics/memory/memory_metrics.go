@@ -0,0 +1,291 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package memory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+type msgTypeKey struct {
+	session string
+	msgType string
+}
+
+type rejectKey struct {
+	session string
+	msgType string
+	reason  int
+}
+
+type latency struct {
+	count uint64
+	sum   time.Duration
+	max   time.Duration
+}
+
+// Sink is a quickfix.MetricsSink that keeps counters in memory and exposes them
+// in the Prometheus text exposition format.
+type Sink struct {
+	mu                     sync.Mutex
+	messagesIn             map[msgTypeKey]uint64
+	messagesOut            map[msgTypeKey]uint64
+	rejects                map[rejectKey]uint64
+	resendRequestsSent     map[string]uint64
+	resendRequestsReceived map[string]uint64
+	gapFillsSent           map[string]uint64
+	heartbeatLatency       map[string]*latency
+}
+
+// NewSink creates an empty in-memory metrics sink.
+func NewSink() *Sink {
+	return &Sink{
+		messagesIn:             make(map[msgTypeKey]uint64),
+		messagesOut:            make(map[msgTypeKey]uint64),
+		rejects:                make(map[rejectKey]uint64),
+		resendRequestsSent:     make(map[string]uint64),
+		resendRequestsReceived: make(map[string]uint64),
+		gapFillsSent:           make(map[string]uint64),
+		heartbeatLatency:       make(map[string]*latency),
+	}
+}
+
+// MessageIn implements quickfix.MetricsSink.
+func (s *Sink) MessageIn(sessionID quickfix.SessionID, msgType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messagesIn[msgTypeKey{sessionID.String(), msgType}]++
+}
+
+// MessageOut implements quickfix.MetricsSink.
+func (s *Sink) MessageOut(sessionID quickfix.SessionID, msgType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messagesOut[msgTypeKey{sessionID.String(), msgType}]++
+}
+
+// Reject implements quickfix.MetricsSink.
+func (s *Sink) Reject(sessionID quickfix.SessionID, msgType string, reason int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejects[rejectKey{sessionID.String(), msgType, reason}]++
+}
+
+// ResendRequestSent implements quickfix.MetricsSink.
+func (s *Sink) ResendRequestSent(sessionID quickfix.SessionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resendRequestsSent[sessionID.String()]++
+}
+
+// ResendRequestReceived implements quickfix.MetricsSink.
+func (s *Sink) ResendRequestReceived(sessionID quickfix.SessionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resendRequestsReceived[sessionID.String()]++
+}
+
+// GapFillSent implements quickfix.MetricsSink.
+func (s *Sink) GapFillSent(sessionID quickfix.SessionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gapFillsSent[sessionID.String()]++
+}
+
+// HeartbeatLatency implements quickfix.MetricsSink.
+func (s *Sink) HeartbeatLatency(sessionID quickfix.SessionID, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.heartbeatLatency[sessionID.String()]
+	if !ok {
+		l = &latency{}
+		s.heartbeatLatency[sessionID.String()] = l
+	}
+	l.count++
+	l.sum += d
+	if d > l.max {
+		l.max = d
+	}
+}
+
+// MessagesIn returns the number of messages of msgType received by the session.
+func (s *Sink) MessagesIn(sessionID quickfix.SessionID, msgType string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messagesIn[msgTypeKey{sessionID.String(), msgType}]
+}
+
+// MessagesOut returns the number of messages of msgType sent by the session.
+func (s *Sink) MessagesOut(sessionID quickfix.SessionID, msgType string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messagesOut[msgTypeKey{sessionID.String(), msgType}]
+}
+
+// Rejects returns the number of rejects of msgType sent by the session with the given reason.
+func (s *Sink) Rejects(sessionID quickfix.SessionID, msgType string, reason int) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rejects[rejectKey{sessionID.String(), msgType, reason}]
+}
+
+// ResendRequestsSent returns the number of ResendRequests sent by the session.
+func (s *Sink) ResendRequestsSent(sessionID quickfix.SessionID) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resendRequestsSent[sessionID.String()]
+}
+
+// ResendRequestsReceived returns the number of ResendRequests received by the session.
+func (s *Sink) ResendRequestsReceived(sessionID quickfix.SessionID) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resendRequestsReceived[sessionID.String()]
+}
+
+// GapFillsSent returns the number of SequenceReset-GapFills sent by the session.
+func (s *Sink) GapFillsSent(sessionID quickfix.SessionID) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gapFillsSent[sessionID.String()]
+}
+
+// HeartbeatLatencyStats returns the number of Heartbeats measured for the session, their mean and maximum latency.
+func (s *Sink) HeartbeatLatencyStats(sessionID quickfix.SessionID) (count uint64, mean, max time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.heartbeatLatency[sessionID.String()]
+	if !ok || l.count == 0 {
+		return 0, 0, 0
+	}
+	return l.count, l.sum / time.Duration(l.count), l.max
+}
+
+// WriteText writes all metrics to w in the Prometheus text exposition format.
+func (s *Sink) WriteText(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, "quickfix_messages_in_total", "counter", "Messages received from the counterparty.")
+	for _, k := range sortedMsgTypeKeys(s.messagesIn) {
+		fmt.Fprintf(bw, "quickfix_messages_in_total{session=%s,msg_type=%s} %d\n", quote(k.session), quote(k.msgType), s.messagesIn[k])
+	}
+
+	writeHeader(bw, "quickfix_messages_out_total", "counter", "Messages sent to the counterparty.")
+	for _, k := range sortedMsgTypeKeys(s.messagesOut) {
+		fmt.Fprintf(bw, "quickfix_messages_out_total{session=%s,msg_type=%s} %d\n", quote(k.session), quote(k.msgType), s.messagesOut[k])
+	}
+
+	writeHeader(bw, "quickfix_rejects_total", "counter", "Rejects sent to the counterparty by reject reason.")
+	rejectKeys := make([]rejectKey, 0, len(s.rejects))
+	for k := range s.rejects {
+		rejectKeys = append(rejectKeys, k)
+	}
+	sort.Slice(rejectKeys, func(i, j int) bool {
+		a, b := rejectKeys[i], rejectKeys[j]
+		if a.session != b.session {
+			return a.session < b.session
+		}
+		if a.msgType != b.msgType {
+			return a.msgType < b.msgType
+		}
+		return a.reason < b.reason
+	})
+	for _, k := range rejectKeys {
+		fmt.Fprintf(bw, "quickfix_rejects_total{session=%s,msg_type=%s,reason=\"%d\"} %d\n", quote(k.session), quote(k.msgType), k.reason, s.rejects[k])
+	}
+
+	writeSessionCounter(bw, "quickfix_resend_requests_sent_total", "ResendRequests sent to the counterparty.", s.resendRequestsSent)
+	writeSessionCounter(bw, "quickfix_resend_requests_received_total", "ResendRequests received from the counterparty.", s.resendRequestsReceived)
+	writeSessionCounter(bw, "quickfix_gap_fills_sent_total", "SequenceReset-GapFills sent to the counterparty.", s.gapFillsSent)
+
+	writeHeader(bw, "quickfix_heartbeat_latency_seconds", "summary", "Delay between SendingTime and receipt of counterparty Heartbeats.")
+	sessions := make([]string, 0, len(s.heartbeatLatency))
+	for session := range s.heartbeatLatency {
+		sessions = append(sessions, session)
+	}
+	sort.Strings(sessions)
+	for _, session := range sessions {
+		l := s.heartbeatLatency[session]
+		fmt.Fprintf(bw, "quickfix_heartbeat_latency_seconds_sum{session=%s} %g\n", quote(session), l.sum.Seconds())
+		fmt.Fprintf(bw, "quickfix_heartbeat_latency_seconds_count{session=%s} %d\n", quote(session), l.count)
+	}
+
+	writeHeader(bw, "quickfix_heartbeat_latency_max_seconds", "gauge", "Maximum observed Heartbeat latency.")
+	for _, session := range sessions {
+		fmt.Fprintf(bw, "quickfix_heartbeat_latency_max_seconds{session=%s} %g\n", quote(session), s.heartbeatLatency[session].max.Seconds())
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format, for use as a scrape endpoint.
+func (s *Sink) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := s.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func writeSessionCounter(w io.Writer, name, help string, counts map[string]uint64) {
+	writeHeader(w, name, "counter", help)
+
+	sessions := make([]string, 0, len(counts))
+	for session := range counts {
+		sessions = append(sessions, session)
+	}
+	sort.Strings(sessions)
+	for _, session := range sessions {
+		fmt.Fprintf(w, "%s{session=%s} %d\n", name, quote(session), counts[session])
+	}
+}
+
+func sortedMsgTypeKeys(counts map[msgTypeKey]uint64) []msgTypeKey {
+	keys := make([]msgTypeKey, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].session != keys[j].session {
+			return keys[i].session < keys[j].session
+		}
+		return keys[i].msgType < keys[j].msgType
+	})
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote formats v as a label value, escaping backslashes, quotes and newlines.
+func quote(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
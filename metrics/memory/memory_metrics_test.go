@@ -0,0 +1,97 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package memory
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+var sessionID = quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+func TestSinkCounters(t *testing.T) {
+	s := NewSink()
+	var _ quickfix.MetricsSink = s
+
+	s.MessageIn(sessionID, "D")
+	s.MessageIn(sessionID, "D")
+	s.MessageIn(sessionID, "0")
+	s.MessageOut(sessionID, "8")
+	s.Reject(sessionID, "3", 5)
+	s.ResendRequestSent(sessionID)
+	s.ResendRequestReceived(sessionID)
+	s.ResendRequestReceived(sessionID)
+	s.GapFillSent(sessionID)
+	s.HeartbeatLatency(sessionID, 10*time.Millisecond)
+	s.HeartbeatLatency(sessionID, 30*time.Millisecond)
+
+	assert.Equal(t, uint64(2), s.MessagesIn(sessionID, "D"))
+	assert.Equal(t, uint64(1), s.MessagesIn(sessionID, "0"))
+	assert.Equal(t, uint64(0), s.MessagesIn(sessionID, "8"))
+	assert.Equal(t, uint64(1), s.MessagesOut(sessionID, "8"))
+	assert.Equal(t, uint64(1), s.Rejects(sessionID, "3", 5))
+	assert.Equal(t, uint64(1), s.ResendRequestsSent(sessionID))
+	assert.Equal(t, uint64(2), s.ResendRequestsReceived(sessionID))
+	assert.Equal(t, uint64(1), s.GapFillsSent(sessionID))
+
+	count, mean, max := s.HeartbeatLatencyStats(sessionID)
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, 20*time.Millisecond, mean)
+	assert.Equal(t, 30*time.Millisecond, max)
+}
+
+func TestSinkWriteText(t *testing.T) {
+	s := NewSink()
+	s.MessageIn(sessionID, "D")
+	s.MessageOut(sessionID, "8")
+	s.Reject(sessionID, "j", 3)
+	s.HeartbeatLatency(sessionID, 500*time.Millisecond)
+
+	var buf bytes.Buffer
+	require.Nil(t, s.WriteText(&buf))
+	text := buf.String()
+
+	assert.Contains(t, text, "# TYPE quickfix_messages_in_total counter\n")
+	assert.Contains(t, text, `quickfix_messages_in_total{session="FIX.4.2:SENDER->TARGET",msg_type="D"} 1`)
+	assert.Contains(t, text, `quickfix_messages_out_total{session="FIX.4.2:SENDER->TARGET",msg_type="8"} 1`)
+	assert.Contains(t, text, `quickfix_rejects_total{session="FIX.4.2:SENDER->TARGET",msg_type="j",reason="3"} 1`)
+	assert.Contains(t, text, `quickfix_heartbeat_latency_seconds_sum{session="FIX.4.2:SENDER->TARGET"} 0.5`)
+	assert.Contains(t, text, `quickfix_heartbeat_latency_seconds_count{session="FIX.4.2:SENDER->TARGET"} 1`)
+}
+
+func TestSinkServeHTTP(t *testing.T) {
+	s := NewSink()
+	s.GapFillSent(sessionID)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+	assert.Contains(t, rec.Body.String(), `quickfix_gap_fills_sent_total{session="FIX.4.2:SENDER->TARGET"} 1`)
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, quote("a\\b\"c\nd"))
+}
@@ -0,0 +1,112 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingMetrics struct {
+	in, out                 []string
+	rejects                 []int
+	resendsSent, resendsRcv int
+	gapFills                int
+	latencies               []time.Duration
+}
+
+func (m *recordingMetrics) MessageIn(_ SessionID, msgType string)  { m.in = append(m.in, msgType) }
+func (m *recordingMetrics) MessageOut(_ SessionID, msgType string) { m.out = append(m.out, msgType) }
+func (m *recordingMetrics) Reject(_ SessionID, _ string, reason int) {
+	m.rejects = append(m.rejects, reason)
+}
+func (m *recordingMetrics) ResendRequestSent(SessionID)     { m.resendsSent++ }
+func (m *recordingMetrics) ResendRequestReceived(SessionID) { m.resendsRcv++ }
+func (m *recordingMetrics) GapFillSent(SessionID)           { m.gapFills++ }
+func (m *recordingMetrics) HeartbeatLatency(_ SessionID, d time.Duration) {
+	m.latencies = append(m.latencies, d)
+}
+
+type MetricsTestSuite struct {
+	SessionSuiteRig
+	metrics *recordingMetrics
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.Init()
+	s.metrics = &recordingMetrics{}
+	s.session.metrics = s.metrics
+	s.session.State = inSession{}
+}
+
+func (s *MetricsTestSuite) TestIncomingHeartbeat() {
+	s.MockApp.On("FromAdmin").Return(nil)
+	heartbeat := s.Heartbeat()
+	heartbeat.Header.SetField(tagSendingTime, FIXUTCTimestamp{Time: time.Now().Add(-time.Second), Precision: Millis})
+
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(heartbeat.build()), receiveTime: time.Now()})
+	s.MockApp.AssertExpectations(s.T())
+
+	s.Equal([]string{"0"}, s.metrics.in)
+	s.Require().Len(s.metrics.latencies, 1)
+	s.InDelta(time.Second, s.metrics.latencies[0], float64(100*time.Millisecond))
+}
+
+func (s *MetricsTestSuite) TestMessageOut() {
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.send(s.NewOrderSingle()))
+
+	s.Equal([]string{"D"}, s.metrics.out)
+}
+
+func (s *MetricsTestSuite) TestReject() {
+	s.MockApp.On("ToAdmin")
+	s.Require().Nil(s.session.doReject(s.NewOrderSingle(), RequiredTagMissing(Tag(11))))
+
+	s.Equal([]int{rejectReasonRequiredTagMissing}, s.metrics.rejects)
+	s.Equal([]string{"3"}, s.metrics.out)
+}
+
+func (s *MetricsTestSuite) TestResendRequest() {
+	s.MockApp.On("ToAdmin")
+	_, err := s.session.sendResendRequest(1, 5)
+	s.Require().Nil(err)
+	s.Equal(1, s.metrics.resendsSent)
+
+	s.IncrNextSenderMsgSeqNum()
+	s.IncrNextSenderMsgSeqNum()
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.session.fixMsgIn(s.session, s.ResendRequest(1))
+	s.Equal(1, s.metrics.resendsRcv)
+	s.Equal(1, s.metrics.gapFills)
+}
+
+func TestMsgTypeFromBytes(t *testing.T) {
+	s := new(QuickFIXSuite)
+	s.SetT(t)
+
+	s.Equal("D", string(msgTypeFromBytes([]byte("8=FIX.4.2\x019=5\x0135=D\x0134=1\x0110=000\x01"))))
+	s.Equal("AE", string(msgTypeFromBytes([]byte("8=FIX.4.4\x019=5\x0135=AE\x01"))))
+	s.Nil(msgTypeFromBytes([]byte("8=FIX.4.2\x019=5\x01")))
+	s.Nil(msgTypeFromBytes([]byte("8=FIX.4.2\x019=5\x0135=D")))
+}
@@ -25,6 +25,8 @@ var msgTypeReject = []byte("3")
 var msgTypeSequenceReset = []byte("4")
 var msgTypeLogout = []byte("5")
 
+var msgTypeFieldPrefix = []byte("\00135=")
+
 // isAdminMessageType returns true if the message type is a session level message.
 func isAdminMessageType(m []byte) bool {
 	switch {
@@ -40,3 +42,18 @@ func isAdminMessageType(m []byte) bool {
 
 	return false
 }
+
+// msgTypeFromBytes returns the MsgType of a raw message, or nil if the message does not contain one.
+func msgTypeFromBytes(msg []byte) []byte {
+	i := bytes.Index(msg, msgTypeFieldPrefix)
+	if i == -1 {
+		return nil
+	}
+
+	msgType := msg[i+len(msgTypeFieldPrefix):]
+	if j := bytes.IndexByte(msgType, '\001'); j != -1 {
+		return msgType[:j]
+	}
+
+	return nil
+}
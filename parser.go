@@ -18,26 +18,103 @@ package quickfix
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+	"github.com/quickfixgo/quickfix/internal"
 )
 
 const (
 	defaultBufSize = 4096
+
+	// Length of the CheckSum(10) field ending a message, which BodyLength(9) does not count.
+	checksumLength = len("10=000\x01")
 )
 
+var beginStringPrefix = []byte("8=FIX")
+
+// MessageTooLargeError is returned when a counterparty sends a message larger than the MaxMessageSize
+// or InboundMaxMessageSize setting. The connection is closed.
+type MessageTooLargeError struct {
+	// Size of the message, or the bytes read of it so far.
+	Size           int
+	MaxMessageSize int
+
+	// Setting the limit is from, MaxMessageSize if empty.
+	Setting string
+}
+
+func (e MessageTooLargeError) Error() string {
+	setting := e.Setting
+	if setting == "" {
+		setting = config.MaxMessageSize
+	}
+	return fmt.Sprintf("message of at least %v bytes exceeds %v %v", e.Size, setting, e.MaxMessageSize)
+}
+
+// corruptMessageError is returned for a message that cannot be framed.
+type corruptMessageError struct {
+	error
+}
+
 type parser struct {
 	// Buffer is a slice of bigBuffer.
 	bigBuffer, buffer []byte
 	reader            io.Reader
 	lastRead          time.Time
+
+	// Largest message read, 0 for no limit, and the setting it is from.
+	maxMessageSize        int
+	maxMessageSizeSetting string
+	// Skip corrupt messages, see config.ResyncOnCorruptMessage.
+	resync bool
+	log    Log
+
+	// Inbound limits of an acceptor session, optional.
+	limiter *inboundLimiter
+
+	// Disconnects the session when the inbound queue is full, nil to wait for room, see config.InboundQueuePolicy.
+	// It does not go through the queue, so that the backlog is not processed first.
+	disconnectWhenQueueFull func(err error)
 }
 
 func newParser(reader io.Reader) *parser {
 	return &parser{reader: reader}
 }
 
+// configure applies the MaxMessageSize and ResyncOnCorruptMessage settings and inbound limits, log receives skipped messages.
+// The lower of MaxMessageSize and InboundMaxMessageSize limits the messages read.
+func (p *parser) configure(settings internal.SessionSettings, limits inboundLimitSettings, log Log) {
+	p.maxMessageSize, p.maxMessageSizeSetting = settings.MaxMessageSize, ""
+	if limits.maxMessageSize > 0 && (p.maxMessageSize == 0 || limits.maxMessageSize < p.maxMessageSize) {
+		p.maxMessageSize, p.maxMessageSizeSetting = limits.maxMessageSize, config.InboundMaxMessageSize
+	}
+	p.resync = settings.ResyncOnCorruptMessage
+	p.limiter = newInboundLimiter(limits, time.Now())
+	p.log = log
+}
+
+// tooLarge returns the error for a message of at least size bytes over the size limit.
+func (p *parser) tooLarge(size int) MessageTooLargeError {
+	return MessageTooLargeError{Size: size, MaxMessageSize: p.maxMessageSize, Setting: p.maxMessageSizeSetting}
+}
+
+// checkLimits returns the inbound rate limit the message just read is over, if any.
+func (p *parser) checkLimits() error {
+	if p.limiter == nil {
+		return nil
+	}
+	return p.limiter.check(p.lastRead)
+}
+
 func (p *parser) readMore() (int, error) {
+	// The buffer holds the part of a message read so far.
+	if p.maxMessageSize > 0 && len(p.buffer) > p.maxMessageSize {
+		return 0, p.tooLarge(len(p.buffer))
+	}
+
 	if len(p.buffer) == cap(p.buffer) {
 		var newBuffer []byte
 		switch {
@@ -93,7 +170,24 @@ func (p *parser) findIndexAfterOffset(offset int, delim []byte) (int, error) {
 }
 
 func (p *parser) findStart() (int, error) {
-	return p.findIndex([]byte("8="))
+	if !p.resync {
+		return p.findIndex([]byte("8="))
+	}
+
+	for {
+		if index := bytes.Index(p.buffer, beginStringPrefix); index != -1 {
+			return index, nil
+		}
+
+		// Drop the garbage read so far, keeping what may be the start of a partial prefix.
+		if keep := len(beginStringPrefix) - 1; len(p.buffer) > keep {
+			p.buffer = p.buffer[len(p.buffer)-keep:]
+		}
+
+		if n, err := p.readMore(); n == 0 && err != nil {
+			return -1, err
+		}
+	}
 }
 
 func (p *parser) findEndAfterOffset(offset int) (int, error) {
@@ -124,22 +218,46 @@ func (p *parser) jumpLength() (int, error) {
 	}
 
 	if offset == lengthIndex {
-		return 0, errors.New("No length given")
+		return 0, corruptMessageError{errors.New("No length given")}
 	}
 
 	length, err := atoi(p.buffer[lengthIndex:offset])
 	if err != nil {
-		return length, err
+		return length, corruptMessageError{err}
 	}
 
 	if length <= 0 {
-		return length, errors.New("Invalid length")
+		return length, corruptMessageError{errors.New("Invalid length")}
+	}
+
+	// BodyLength counts from the field after it to the delimiter before CheckSum.
+	if size := offset + 1 + length + checksumLength; p.maxMessageSize > 0 && size > p.maxMessageSize {
+		return 0, p.tooLarge(size)
 	}
 
 	return offset + length, nil
 }
 
+// ReadMessage returns the next message read. If resync is set, corrupt messages are skipped.
 func (p *parser) ReadMessage() (msgBytes *bytes.Buffer, err error) {
+	for {
+		msgBytes, err = p.readMessage()
+
+		var corrupt corruptMessageError
+		if err == nil || !p.resync || !errors.As(err, &corrupt) {
+			return
+		}
+
+		if p.log != nil {
+			p.log.OnEventf("Skipping corrupt message: %v", err)
+		}
+
+		// Skip past the start of the corrupt message, findStart then moves to the next one.
+		p.buffer = p.buffer[1:]
+	}
+}
+
+func (p *parser) readMessage() (msgBytes *bytes.Buffer, err error) {
 	start, err := p.findStart()
 	if err != nil {
 		return
@@ -156,6 +274,12 @@ func (p *parser) ReadMessage() (msgBytes *bytes.Buffer, err error) {
 		return
 	}
 
+	// A wrong BodyLength(9) can run the message into the next one.
+	if p.resync && bytes.Contains(p.buffer[1:index], append([]byte("\001"), beginStringPrefix...)) {
+		err = corruptMessageError{errors.New("message runs into the next message")}
+		return
+	}
+
 	msgBytes = new(bytes.Buffer)
 	msgBytes.Reset()
 	msgBytes.Write(p.buffer[:index])
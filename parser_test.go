@@ -186,3 +186,84 @@ func (s *ParserSuite) TestReadMessageGrowBuffer() {
 		s.Equal(tc.expectedBufferLen, len(s.parser.buffer))
 	}
 }
+
+func (s *ParserSuite) TestMaxMessageSizeBodyLength() {
+	s.maxMessageSize = 30
+	s.reader = strings.NewReader("8=FIX.4.0\x019=5\x01blah\x0110=103\x018=FIX.4.0\x019=100000\x01")
+
+	msg, err := s.ReadMessage()
+	s.Nil(err)
+	s.Equal("8=FIX.4.0\x019=5\x01blah\x0110=103\x01", msg.String())
+
+	_, err = s.ReadMessage()
+	var tooLarge MessageTooLargeError
+	s.Require().ErrorAs(err, &tooLarge)
+	s.Equal(30, tooLarge.MaxMessageSize)
+	s.Equal(100026, tooLarge.Size)
+}
+
+func (s *ParserSuite) TestMaxMessageSizeIncludesCheckSum() {
+	stream := "8=FIX.4.0\x019=5\x01blah\x0110=103\x01"
+
+	s.maxMessageSize = len(stream)
+	s.reader = strings.NewReader(stream)
+	msg, err := s.ReadMessage()
+	s.Nil(err)
+	s.Equal(stream, msg.String())
+
+	for _, over := range []int{1, checksumLength} {
+		s.SetupTest()
+		s.maxMessageSize = len(stream) - over
+		s.reader = strings.NewReader(stream)
+
+		_, err = s.ReadMessage()
+		var tooLarge MessageTooLargeError
+		s.Require().ErrorAs(err, &tooLarge, "over by %v", over)
+		s.Equal(len(stream), tooLarge.Size)
+	}
+}
+
+func (s *ParserSuite) TestMaxMessageSizeBuffered() {
+	s.maxMessageSize = 64
+	s.reader = strings.NewReader("8=FIX.4.0\x019=" + strings.Repeat("1", 10000))
+
+	_, err := s.ReadMessage()
+	var tooLarge MessageTooLargeError
+	s.Require().ErrorAs(err, &tooLarge)
+	s.LessOrEqual(len(s.bigBuffer), defaultBufSize, "does not grow the buffer past the limit")
+
+	s.SetupTest()
+	s.maxMessageSize = 64
+	s.reader = strings.NewReader(strings.Repeat("garbage", 10000))
+	_, err = s.ReadMessage()
+	s.Require().ErrorAs(err, &tooLarge)
+}
+
+func (s *ParserSuite) TestResyncSkipsGarbage() {
+	s.resync = true
+	s.maxMessageSize = 64
+	s.reader = strings.NewReader(strings.Repeat("garbage8=", 1000) + "8=FIX.4.0\x019=5\x01blah\x0110=103\x01")
+
+	msg, err := s.ReadMessage()
+	s.Nil(err)
+	s.Equal("8=FIX.4.0\x019=5\x01blah\x0110=103\x01", msg.String())
+}
+
+func (s *ParserSuite) TestResyncSkipsCorruptMessages() {
+	var testCases = []struct {
+		stream string
+	}{
+		{"8=FIX.4.0\x019=x\x0110=103\x018=FIX.4.0\x019=4\x01foo\x0110=103\x01"},
+		{"8=FIX.4.0\x019=\x0110=103\x018=FIX.4.0\x019=4\x01foo\x0110=103\x01"},
+		{"8=FIX.4.0\x019=99\x01blah\x01" + strings.Repeat("8=FIX.4.0\x019=4\x01foo\x0110=103\x01", 5)},
+	}
+
+	for _, tc := range testCases {
+		s.SetupTest()
+		s.resync = true
+		s.reader = strings.NewReader(tc.stream)
+		msg, err := s.ReadMessage()
+		s.Nil(err)
+		s.Equal("8=FIX.4.0\x019=4\x01foo\x0110=103\x01", msg.String())
+	}
+}
@@ -25,6 +25,7 @@ func (s pendingTimeout) Timeout(session *session, event internal.Event) (nextSta
 	switch event {
 	case internal.PeerTimeout:
 		session.log.OnEvent("Session Timeout")
+		session.setDisconnectReason("Session Timeout")
 		return latentState{}
 	}
 
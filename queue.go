@@ -0,0 +1,173 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+type queuePolicy int
+
+const (
+	queueReject queuePolicy = iota
+	queueBlock
+	queueDisconnect
+)
+
+// queueSettings are the parsed *Queue* settings of a session, see config.OutboundQueueLimit.
+type queueSettings struct {
+	// Messages sent with SendToTarget waiting in the send queue, 0 for no limit.
+	outboundLimit  int
+	outboundPolicy queuePolicy
+
+	// Messages read and waiting for the session, 0 to hand each message over as it is read.
+	inboundLimit  int
+	inboundPolicy queuePolicy
+}
+
+func parseQueueSettings(settings *SessionSettings) (queues queueSettings, err error) {
+	if settings.HasSetting(config.OutboundQueueLimit) {
+		if queues.outboundLimit, err = settings.IntSetting(config.OutboundQueueLimit); err != nil {
+			return
+		}
+
+		if queues.outboundLimit <= 0 {
+			err = errors.New("OutboundQueueLimit must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.OutboundQueuePolicy) {
+		var policy string
+		if policy, err = settings.Setting(config.OutboundQueuePolicy); err != nil {
+			return
+		}
+
+		switch policy {
+		case "REJECT":
+			queues.outboundPolicy = queueReject
+		case "BLOCK":
+			queues.outboundPolicy = queueBlock
+		case "DISCONNECT":
+			queues.outboundPolicy = queueDisconnect
+		default:
+			err = IncorrectFormatForSetting{Setting: config.OutboundQueuePolicy, Value: []byte(policy)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.InboundQueueLimit) {
+		if queues.inboundLimit, err = settings.IntSetting(config.InboundQueueLimit); err != nil {
+			return
+		}
+
+		if queues.inboundLimit <= 0 {
+			err = errors.New("InboundQueueLimit must be a positive integer")
+			return
+		}
+	}
+
+	queues.inboundPolicy = queueBlock
+	if settings.HasSetting(config.InboundQueuePolicy) {
+		var policy string
+		if policy, err = settings.Setting(config.InboundQueuePolicy); err != nil {
+			return
+		}
+
+		switch policy {
+		case "BLOCK":
+			queues.inboundPolicy = queueBlock
+		case "DISCONNECT":
+			if queues.inboundLimit == 0 {
+				err = errors.New("InboundQueuePolicy DISCONNECT requires InboundQueueLimit")
+				return
+			}
+			queues.inboundPolicy = queueDisconnect
+		default:
+			err = IncorrectFormatForSetting{Setting: config.InboundQueuePolicy, Value: []byte(policy)}
+			return
+		}
+	}
+
+	return
+}
+
+// QueueFullError is returned by SendToTarget when the send queue of the session is at its limit, see config.OutboundQueuePolicy.
+// It is also the reason a connection is dropped when its inbound queue is full, see config.InboundQueuePolicy.
+type QueueFullError struct {
+	// Inbound is true for the queue of messages received, false for the send queue.
+	Inbound bool
+	Limit   int
+}
+
+func (e QueueFullError) Error() string {
+	queue := "outbound"
+	if e.Inbound {
+		queue = "inbound"
+	}
+	return fmt.Sprintf("%v queue full, limit %v messages", queue, e.Limit)
+}
+
+// queueForSendBounded queues msg as queueForSendNow, applying OutboundQueueLimit.
+func (s *session) queueForSendBounded(msg *Message) error {
+	if s.queueSettings.outboundLimit == 0 {
+		return s.queueForSendNow(msg)
+	}
+
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	if err := s.waitForQueueSpace(); err != nil {
+		return err
+	}
+	return s.queueLocked(msg)
+}
+
+// waitForQueueSpace returns once the send queue is below OutboundQueueLimit, or a QueueFullError if the policy
+// does not wait or the session is not running to drain the queue. sendMutex must be held.
+func (s *session) waitForQueueSpace() error {
+	for len(s.toSend) >= s.queueSettings.outboundLimit {
+		err := QueueFullError{Limit: s.queueSettings.outboundLimit}
+		switch {
+		case s.queueSettings.outboundPolicy == queueDisconnect:
+			// The session goroutine drops the connection, see stateMachine.SendAppMessages.
+			s.disconnectErr = err
+			s.notifyMessageOut()
+			return err
+
+		case s.queueSettings.outboundPolicy == queueBlock && s.status.runningChan() != nil:
+			if s.queueSpace == nil {
+				s.queueSpace = sync.NewCond(&s.sendMutex)
+			}
+			s.queueSpace.Wait()
+
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// queueDrained wakes senders waiting for space in the send queue. sendMutex must be held.
+func (s *session) queueDrained() {
+	if s.queueSpace != nil {
+		s.queueSpace.Broadcast()
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestParseQueueSettings(t *testing.T) {
+	settings := NewSessionSettings()
+	queues, err := parseQueueSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, queueSettings{outboundPolicy: queueReject, inboundPolicy: queueBlock}, queues)
+
+	settings.Set(config.OutboundQueueLimit, "1000")
+	settings.Set(config.OutboundQueuePolicy, "BLOCK")
+	settings.Set(config.InboundQueueLimit, "100")
+	settings.Set(config.InboundQueuePolicy, "DISCONNECT")
+	queues, err = parseQueueSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, queueSettings{outboundLimit: 1000, outboundPolicy: queueBlock, inboundLimit: 100, inboundPolicy: queueDisconnect}, queues)
+
+	settings.Set(config.OutboundQueuePolicy, "DISCONNECT")
+	queues, err = parseQueueSettings(settings)
+	require.Nil(t, err)
+	assert.Equal(t, queueDisconnect, queues.outboundPolicy)
+
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.OutboundQueueLimit, "0"},
+		{config.OutboundQueueLimit, "lots"},
+		{config.OutboundQueuePolicy, "QUEUE"},
+		{config.InboundQueueLimit, "-1"},
+		{config.InboundQueuePolicy, "REJECT"},
+	}
+
+	for _, test := range tests {
+		s := settings.clone()
+		s.Set(test.setting, test.value)
+		_, err = parseQueueSettings(s)
+		assert.NotNil(t, err, test.setting+"="+test.value)
+	}
+
+	s := NewSessionSettings()
+	s.Set(config.InboundQueuePolicy, "DISCONNECT")
+	_, err = parseQueueSettings(s)
+	assert.NotNil(t, err, "DISCONNECT without InboundQueueLimit")
+}
+
+func TestReadLoopInboundQueueFull(t *testing.T) {
+	msgIn := make(chan fixIn, 2)
+	stream := strings.Repeat("8=FIX.4.0\x019=5\x01blah\x0110=103\x01", 4)
+
+	parser := newParser(strings.NewReader(stream))
+	disconnected := make(chan error, 1)
+	parser.disconnectWhenQueueFull = func(err error) { disconnected <- err }
+	done := make(chan struct{})
+	go func() {
+		readLoop(parser, msgIn, nullLog{})
+		close(done)
+	}()
+
+	handle := &SessionHandle{s: &session{}}
+	handle.s.status.setMessageIn(msgIn)
+	assert.Eventually(t, func() bool { return handle.InboundQueueDepth() == 2 }, time.Second, time.Millisecond)
+
+	// The session is disconnected without waiting for the queued messages to be processed.
+	var queueFull QueueFullError
+	err := <-disconnected
+	require.True(t, errors.As(err, &queueFull), "expected QueueFullError, got %v", err)
+	assert.Equal(t, QueueFullError{Inbound: true, Limit: 2}, queueFull)
+	<-done
+
+	for i := 0; i < 2; i++ {
+		msg := <-msgIn
+		assert.Nil(t, msg.err)
+	}
+	_, ok := <-msgIn
+	assert.False(t, ok)
+}
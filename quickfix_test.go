@@ -218,6 +218,7 @@ func (s *SessionSuiteRig) Init() {
 		store:        &s.MockStore,
 		application:  &s.MockApp,
 		log:          nullLog{},
+		metrics:      nullMetrics{},
 		messageOut:   s.Receiver.sendChannel,
 		sessionEvent: make(chan internal.Event),
 	}
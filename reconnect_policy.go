@@ -0,0 +1,78 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+// ReconnectPolicy decides how long an initiator session waits before each reconnection attempt.
+// A ReconnectPolicy is shared by all sessions of an Initiator and must be safe for concurrent use.
+type ReconnectPolicy interface {
+	// ReconnectInterval returns the wait before reconnection attempt number attempt of the session, starting at 1.
+	// Attempts are counted from the start of the session and, unless ReconnectResetOnLogon is N, from its last logon.
+	ReconnectInterval(sessionID SessionID, attempt int) time.Duration
+}
+
+// backoffReconnectPolicy is the ReconnectPolicy used when none is given to the Initiator.
+// It is configured by ReconnectInterval, ReconnectBackoffMultiplier, ReconnectMaxInterval and ReconnectJitter.
+type backoffReconnectPolicy struct {
+	interval    time.Duration
+	multiplier  float64
+	maxInterval time.Duration
+	jitter      float64
+
+	// Returns a random number in [0.0,1.0).
+	random func() float64
+}
+
+func newBackoffReconnectPolicy(settings internal.SessionSettings) backoffReconnectPolicy {
+	return backoffReconnectPolicy{
+		interval:    settings.ReconnectInterval,
+		multiplier:  settings.ReconnectBackoffMultiplier,
+		maxInterval: settings.ReconnectMaxInterval,
+		jitter:      settings.ReconnectJitter,
+		random:      rand.Float64,
+	}
+}
+
+func (p backoffReconnectPolicy) ReconnectInterval(_ SessionID, attempt int) time.Duration {
+	interval := float64(p.interval)
+	if p.multiplier > 1 && attempt > 1 {
+		interval *= math.Pow(p.multiplier, float64(attempt-1))
+	}
+
+	if p.maxInterval > 0 && interval > float64(p.maxInterval) {
+		interval = float64(p.maxInterval)
+	} else if interval > math.MaxInt64/2 {
+		interval = math.MaxInt64 / 2
+	}
+
+	if p.jitter > 0 {
+		interval += interval * p.jitter * (2*p.random() - 1)
+
+		// The jitter does not take the interval past the maximum.
+		if p.maxInterval > 0 && interval > float64(p.maxInterval) {
+			interval = float64(p.maxInterval)
+		}
+	}
+
+	return time.Duration(interval)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestBackoffReconnectPolicyFixed(t *testing.T) {
+	p := backoffReconnectPolicy{interval: 30 * time.Second, multiplier: 1}
+
+	assert.Equal(t, 30*time.Second, p.ReconnectInterval(SessionID{}, 1))
+	assert.Equal(t, 30*time.Second, p.ReconnectInterval(SessionID{}, 10))
+}
+
+func TestBackoffReconnectPolicyMultiplier(t *testing.T) {
+	p := backoffReconnectPolicy{interval: time.Second, multiplier: 2, maxInterval: 10 * time.Second}
+
+	assert.Equal(t, time.Second, p.ReconnectInterval(SessionID{}, 1))
+	assert.Equal(t, 2*time.Second, p.ReconnectInterval(SessionID{}, 2))
+	assert.Equal(t, 8*time.Second, p.ReconnectInterval(SessionID{}, 4))
+	assert.Equal(t, 10*time.Second, p.ReconnectInterval(SessionID{}, 5))
+	assert.Equal(t, 10*time.Second, p.ReconnectInterval(SessionID{}, 5000))
+
+	p.maxInterval = 0
+	assert.Positive(t, p.ReconnectInterval(SessionID{}, 5000), "no overflow without a maximum")
+}
+
+func TestBackoffReconnectPolicyJitter(t *testing.T) {
+	p := backoffReconnectPolicy{interval: 10 * time.Second, multiplier: 1, jitter: 0.2}
+
+	p.random = func() float64 { return 0 }
+	assert.Equal(t, 8*time.Second, p.ReconnectInterval(SessionID{}, 1))
+
+	p.random = func() float64 { return 0.5 }
+	assert.Equal(t, 10*time.Second, p.ReconnectInterval(SessionID{}, 1))
+
+	p.random = func() float64 { return 0.75 }
+	assert.Equal(t, 11*time.Second, p.ReconnectInterval(SessionID{}, 1))
+}
+
+func TestBackoffReconnectPolicyJitterMaxInterval(t *testing.T) {
+	p := backoffReconnectPolicy{interval: time.Second, multiplier: 2, maxInterval: 10 * time.Second, jitter: 0.2}
+
+	p.random = func() float64 { return 1 }
+	assert.Equal(t, 10*time.Second, p.ReconnectInterval(SessionID{}, 5))
+	assert.Equal(t, 9600*time.Millisecond, p.ReconnectInterval(SessionID{}, 4))
+
+	p.random = func() float64 { return 0 }
+	assert.Equal(t, 8*time.Second, p.ReconnectInterval(SessionID{}, 5))
+}
+
+type recordingReconnectPolicy struct {
+	sync.Mutex
+	attempts []int
+}
+
+func (p *recordingReconnectPolicy) ReconnectInterval(_ SessionID, attempt int) time.Duration {
+	p.Lock()
+	defer p.Unlock()
+	p.attempts = append(p.attempts, attempt)
+	return 10 * time.Millisecond
+}
+
+func (p *recordingReconnectPolicy) recorded() []int {
+	p.Lock()
+	defer p.Unlock()
+	return append([]int(nil), p.attempts...)
+}
+
+func TestInitiatorReconnectPolicy(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.Nil(t, err)
+
+	settings := NewSettings()
+	sessionSettings := NewSessionSettings()
+	sessionSettings.Set(config.BeginString, BeginStringFIX42)
+	sessionSettings.Set(config.SenderCompID, "SENDER")
+	sessionSettings.Set(config.TargetCompID, "TARGET")
+	sessionSettings.Set(config.HeartBtInt, "30")
+	sessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	sessionSettings.Set(config.SocketConnectPort, strconv.Itoa(port))
+	_, err = settings.AddSession(sessionSettings)
+	require.Nil(t, err)
+
+	policy := &recordingReconnectPolicy{}
+	i, err := NewInitiator(EmptyApplication{}, NewMemoryStoreFactory(), settings, nullLogFactory{},
+		WithInitiatorRegistry(NewRegistry()), WithInitiatorReconnectPolicy(policy))
+	require.Nil(t, err)
+	require.Nil(t, i.Start())
+	defer i.Stop()
+
+	require.Eventually(t, func() bool { return len(policy.recorded()) >= 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{1, 2, 3}, policy.recorded()[:3])
+}
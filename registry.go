@@ -33,6 +33,9 @@ func NewRegistry() *Registry {
 type Registry struct {
 	sessionsLock sync.RWMutex
 	sessions     map[SessionID]*session
+
+	subscribersLock sync.RWMutex
+	subscribers     []chan SessionEvent
 }
 
 var errDuplicateSessionID = errors.New("Duplicate SessionID")
@@ -93,6 +96,49 @@ func (r *Registry) ResetSession(sessionID SessionID) error {
 	return nil
 }
 
+// Logout sends a Logout with reason as Text(58) to the session matching the session id.
+// The session remains registered and, for an initiator, will reconnect as configured.
+func (r *Registry) Logout(sessionID SessionID, reason string) error {
+	session, ok := r.lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+	return session.logout(reason)
+}
+
+// Disconnect drops the connection of the session matching the session id without sending a Logout.
+// The session remains registered and, for an initiator, will reconnect as configured.
+func (r *Registry) Disconnect(sessionID SessionID) error {
+	session, ok := r.lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+	return session.disconnect()
+}
+
+// ResendRequest sends a ResendRequest asking the counterparty to resend the messages from beginSeqNo to endSeqNo
+// already received by the session matching the session id, 0 for endSeqNo is the last message received.
+// The session must be logged on and not recovering a gap. To resend messages sent by the session, see ResendMessages.
+func (r *Registry) ResendRequest(sessionID SessionID, beginSeqNo, endSeqNo int) error {
+	session, ok := r.lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+	return session.requestResend(beginSeqNo, endSeqNo)
+}
+
+// ResendMessages resends the stored messages from beginSeqNo to endSeqNo already sent by the session matching the
+// session id, as if the counterparty had sent a ResendRequest for them: application messages are resent with
+// PossDupFlag, admin messages and messages no longer stored are gap filled. 0 for endSeqNo is the last message sent.
+// The session must be logged on and not recovering a gap.
+func (r *Registry) ResendMessages(sessionID SessionID, beginSeqNo, endSeqNo int) error {
+	session, ok := r.lookupSession(sessionID)
+	if !ok {
+		return errUnknownSession
+	}
+	return session.replay(beginSeqNo, endSeqNo)
+}
+
 // UnregisterSession removes a session from the set of known sessions.
 func (r *Registry) UnregisterSession(sessionID SessionID) error {
 	r.sessionsLock.Lock()
@@ -160,6 +206,27 @@ func (r *Registry) GetLog(sessionID SessionID) (Log, error) {
 	return session.log, nil
 }
 
+// GetSession returns a SessionHandle for the session matching the session id.
+func (r *Registry) GetSession(sessionID SessionID) (*SessionHandle, error) {
+	session, ok := r.lookupSession(sessionID)
+	if !ok {
+		return nil, errUnknownSession
+	}
+	return &SessionHandle{s: session}, nil
+}
+
+// Sessions returns the ids of all sessions known to the registry.
+func (r *Registry) Sessions() []SessionID {
+	r.sessionsLock.RLock()
+	defer r.sessionsLock.RUnlock()
+
+	sessionIDs := make([]SessionID, 0, len(r.sessions))
+	for sessionID := range r.sessions {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs
+}
+
 func (r *Registry) registerSession(s *session) error {
 	r.sessionsLock.Lock()
 	defer r.sessionsLock.Unlock()
@@ -169,6 +236,7 @@ func (r *Registry) registerSession(s *session) error {
 	}
 
 	r.sessions[s.sessionID] = s
+	s.registry = r
 	return nil
 }
 
@@ -180,6 +248,44 @@ func (r *Registry) lookupSession(sessionID SessionID) (s *session, ok bool) {
 	return
 }
 
+// Subscribe returns a channel on which lifecycle events for all sessions of the registry are delivered.
+// Events are dropped rather than blocking a session when the channel buffer is full, so bufferSize should
+// be sized for the expected burst of events.
+func (r *Registry) Subscribe(bufferSize int) <-chan SessionEvent {
+	r.subscribersLock.Lock()
+	defer r.subscribersLock.Unlock()
+
+	ch := make(chan SessionEvent, bufferSize)
+	r.subscribers = append(r.subscribers, ch)
+	return ch
+}
+
+// Unsubscribe stops delivery of events to a channel returned by Subscribe and closes it.
+func (r *Registry) Unsubscribe(events <-chan SessionEvent) {
+	r.subscribersLock.Lock()
+	defer r.subscribersLock.Unlock()
+
+	for i, ch := range r.subscribers {
+		if ch == events {
+			r.subscribers = append(r.subscribers[:i], r.subscribers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
+
+func (r *Registry) publish(evt SessionEvent) {
+	r.subscribersLock.RLock()
+	defer r.subscribersLock.RUnlock()
+
+	for _, ch := range r.subscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
 // Send determines the session to send Messagable using header fields BeginString, TargetCompID, SenderCompID.
 func Send(m Messagable) (err error) {
 	return defaultRegistry.Send(m)
@@ -195,6 +301,26 @@ func ResetSession(sessionID SessionID) error {
 	return defaultRegistry.ResetSession(sessionID)
 }
 
+// Logout sends a Logout with reason as Text(58) to the session matching the session id.
+func Logout(sessionID SessionID, reason string) error {
+	return defaultRegistry.Logout(sessionID, reason)
+}
+
+// Disconnect drops the connection of the session matching the session id without sending a Logout.
+func Disconnect(sessionID SessionID) error {
+	return defaultRegistry.Disconnect(sessionID)
+}
+
+// ResendRequest sends a ResendRequest for already received messages, see Registry.ResendRequest.
+func ResendRequest(sessionID SessionID, beginSeqNo, endSeqNo int) error {
+	return defaultRegistry.ResendRequest(sessionID, beginSeqNo, endSeqNo)
+}
+
+// ResendMessages resends already sent messages from the store, see Registry.ResendMessages.
+func ResendMessages(sessionID SessionID, beginSeqNo, endSeqNo int) error {
+	return defaultRegistry.ResendMessages(sessionID, beginSeqNo, endSeqNo)
+}
+
 // UnregisterSession removes a session from the set of known sessions.
 func UnregisterSession(sessionID SessionID) error {
 	return defaultRegistry.UnregisterSession(sessionID)
@@ -229,3 +355,23 @@ func GetMessageStore(sessionID SessionID) (MessageStore, error) {
 func GetLog(sessionID SessionID) (Log, error) {
 	return defaultRegistry.GetLog(sessionID)
 }
+
+// GetSession returns a SessionHandle for the session matching the session id.
+func GetSession(sessionID SessionID) (*SessionHandle, error) {
+	return defaultRegistry.GetSession(sessionID)
+}
+
+// Sessions returns the ids of all sessions known to the default registry.
+func Sessions() []SessionID {
+	return defaultRegistry.Sessions()
+}
+
+// Subscribe returns a channel on which lifecycle events for all sessions of the default registry are delivered.
+func Subscribe(bufferSize int) <-chan SessionEvent {
+	return defaultRegistry.Subscribe(bufferSize)
+}
+
+// Unsubscribe stops delivery of events to a channel returned by Subscribe and closes it.
+func Unsubscribe(events <-chan SessionEvent) {
+	defaultRegistry.Unsubscribe(events)
+}
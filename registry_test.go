@@ -0,0 +1,121 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	SessionSuiteRig
+	registry *Registry
+}
+
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func (s *RegistryTestSuite) SetupTest() {
+	s.Init()
+	s.registry = NewRegistry()
+	s.Require().Nil(s.registry.registerSession(s.session))
+}
+
+// serveAdmin stands in for the session goroutine for a single admin request.
+func (s *RegistryTestSuite) serveAdmin() {
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	s.T().Cleanup(func() { close(running) })
+
+	s.session.admin = make(chan interface{})
+	go func() { s.session.onAdmin(<-s.session.admin) }()
+}
+
+func (s *RegistryTestSuite) TestUnknownSession() {
+	unknown := SessionID{BeginString: "FIX.4.2", TargetCompID: "X", SenderCompID: "Y"}
+	s.Equal(errUnknownSession, s.registry.Logout(unknown, "reason"))
+	s.Equal(errUnknownSession, s.registry.Disconnect(unknown))
+}
+
+func (s *RegistryTestSuite) TestNotRunning() {
+	s.session.State = inSession{}
+	s.Equal(errSessionNotRunning, s.registry.Logout(s.sessionID, "reason"))
+	s.Equal(errSessionNotRunning, s.registry.Disconnect(s.sessionID))
+	s.NoMessageSent()
+}
+
+func (s *RegistryTestSuite) TestLogout() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+	events := s.registry.Subscribe(10)
+	s.serveAdmin()
+
+	s.Require().Nil(s.registry.Logout(s.sessionID, "end of day"))
+	s.MockApp.AssertExpectations(s.T())
+	s.State(logoutState{})
+
+	s.LastToAdminMessageSent()
+	s.MessageType(string(msgTypeLogout), s.MockApp.lastToAdmin)
+	s.FieldEquals(tagText, "end of day", s.MockApp.lastToAdmin.Body)
+
+	var loggedOut bool
+	for len(events) > 0 {
+		if evt := <-events; evt.Type == SessionLoggedOut {
+			loggedOut = true
+			s.Equal("end of day", evt.Reason)
+		}
+	}
+	s.True(loggedOut)
+}
+
+func (s *RegistryTestSuite) TestLogoutNotLoggedOn() {
+	s.session.State = latentState{}
+	s.serveAdmin()
+
+	s.EqualError(s.registry.Logout(s.sessionID, "reason"), "Not logged on")
+	s.NoMessageSent()
+}
+
+func (s *RegistryTestSuite) TestDisconnect() {
+	s.session.State = inSession{}
+	s.MockApp.On("OnLogout")
+	events := s.registry.Subscribe(10)
+	s.serveAdmin()
+
+	s.Require().Nil(s.registry.Disconnect(s.sessionID))
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Disconnected()
+	s.NoMessageSent()
+
+	var disconnected bool
+	for len(events) > 0 {
+		if evt := <-events; evt.Type == SessionDisconnected {
+			disconnected = true
+			s.Equal("Disconnect requested", evt.Reason)
+		}
+	}
+	s.True(disconnected)
+}
+
+func (s *RegistryTestSuite) TestDisconnectNotConnected() {
+	s.session.State = latentState{}
+	s.serveAdmin()
+
+	s.EqualError(s.registry.Disconnect(s.sessionID), "Not connected")
+}
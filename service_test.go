@@ -12,8 +12,10 @@ import (
 	report "github.com/quickfixgo/fix44/tradecapturereport"
 	ack "github.com/quickfixgo/fix44/tradecapturereportack"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/bolt"
 	"github.com/quickfixgo/tag"
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +32,7 @@ type Client struct {
 func (c *Client) OnLogon(_ quickfix.SessionID) {
 	c.isLoggedIn.Add(1)
 }
-func (c *Client) OnLogoff(_ quickfix.SessionID) {
+func (c *Client) OnLogout(_ quickfix.SessionID) {
 	c.isLoggedIn.Add(-1)
 }
 
@@ -55,7 +57,7 @@ type Server struct {
 func (s *Server) OnLogon(_ quickfix.SessionID) {
 	s.isLoggedIn.Add(1)
 }
-func (s *Server) OnLogoff(_ quickfix.SessionID) {
+func (s *Server) OnLogout(_ quickfix.SessionID) {
 	s.isLoggedIn.Add(-1)
 }
 func (s *Server) FromApp(msg *quickfix.Message, id quickfix.SessionID) quickfix.MessageRejectError {
@@ -172,3 +174,193 @@ func (c *Client) OnReport(_ report.TradeCaptureReport, id quickfix.SessionID) qu
 	c.reports.Add(1)
 	return nil
 }
+
+func TestInitiatorAddRemoveSession(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	s := NewServer(t, port).Start()
+	t.Cleanup(s.Stop)
+
+	c := &Client{t: t}
+	logger := quickfix.NewSlogLogger()
+	logger.Name = "client"
+	registry := quickfix.NewRegistry()
+	c.Initiator, err = quickfix.NewInitiator(c, quickfix.NewMemoryStoreFactory(), quickfix.NewSettings(), logger, quickfix.WithInitiatorRegistry(registry))
+	require.NoError(t, err)
+	c.Start()
+	t.Cleanup(c.Stop)
+
+	session := quickfix.NewSessionSettings()
+	session.Set("BeginString", "FIX.4.4")
+	session.Set("SocketConnectPort", strconv.Itoa(port))
+	session.Set("SocketConnectHost", "localhost")
+	session.Set("TargetCompID", "target")
+	session.Set("SenderCompID", "sender")
+	session.Set("HeartBtInt", "1")
+	c.sessionID, err = c.AddSession(session)
+	require.NoError(t, err)
+	require.Equal(t, []quickfix.SessionID{c.sessionID}, registry.Sessions())
+
+	_, err = c.AddSession(session)
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool { return c.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+	require.Eventually(t, func() bool { return s.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, c.RemoveSession(c.sessionID))
+	require.Equal(t, int32(0), c.isLoggedIn.Load())
+	require.Empty(t, registry.Sessions())
+	require.Eventually(t, func() bool { return s.isLoggedIn.Load() == 0 }, 10*time.Second, 100*time.Millisecond)
+
+	require.Error(t, c.RemoveSession(c.sessionID))
+}
+
+func TestAcceptorAddRemoveSession(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	s := NewServer(t, port).Start()
+	t.Cleanup(s.Stop)
+	c := NewClient(t, port).Start()
+	t.Cleanup(c.Stop)
+	require.Eventually(t, func() bool { return c.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+
+	added := quickfix.NewSessionSettings()
+	added.Set("BeginString", "FIX.4.4")
+	added.Set("SocketAcceptPort", strconv.Itoa(port))
+	added.Set("TargetCompID", "sender2")
+	added.Set("SenderCompID", "target")
+	sessionID, err := s.AddSession(added)
+	require.NoError(t, err)
+
+	_, err = s.AddSession(added)
+	require.Error(t, err)
+
+	otherPort := quickfix.NewSessionSettings()
+	otherPort.Set("BeginString", "FIX.4.4")
+	otherPort.Set("SocketAcceptPort", strconv.Itoa(port+1))
+	otherPort.Set("TargetCompID", "sender3")
+	otherPort.Set("SenderCompID", "target")
+	_, err = s.AddSession(otherPort)
+	require.Error(t, err)
+
+	c2 := &Client{t: t}
+	settings := quickfix.NewSettings()
+	session := quickfix.NewSessionSettings()
+	session.Set("BeginString", "FIX.4.4")
+	session.Set("SocketConnectPort", strconv.Itoa(port))
+	session.Set("SocketConnectHost", "localhost")
+	session.Set("TargetCompID", "target")
+	session.Set("SenderCompID", "sender2")
+	session.Set("HeartBtInt", "1")
+	session.Set("ReconnectInterval", "1")
+	_, err = settings.AddSession(session)
+	require.NoError(t, err)
+	c2.Initiator, err = quickfix.NewInitiator(c2, quickfix.NewMemoryStoreFactory(), settings, quickfix.NewSlogLogger(), quickfix.WithInitiatorRegistry(quickfix.NewRegistry()))
+	require.NoError(t, err)
+	c2.Start()
+	t.Cleanup(c2.Stop)
+
+	require.Eventually(t, func() bool { return c2.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+	require.Equal(t, int32(2), s.isLoggedIn.Load())
+
+	require.NoError(t, s.RemoveSession(sessionID))
+	require.Error(t, s.RemoveSession(sessionID))
+	require.Equal(t, int32(1), s.isLoggedIn.Load())
+	require.Eventually(t, func() bool { return c2.isLoggedIn.Load() == 0 }, 10*time.Second, 100*time.Millisecond)
+	require.Equal(t, int32(1), c.isLoggedIn.Load())
+}
+
+func TestInitiatorRemoveAddSessionBoltStore(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	s := NewServer(t, port).Start()
+	t.Cleanup(s.Stop)
+
+	session := quickfix.NewSessionSettings()
+	session.Set("BeginString", "FIX.4.4")
+	session.Set("SocketConnectPort", strconv.Itoa(port))
+	session.Set("SocketConnectHost", "localhost")
+	session.Set("TargetCompID", "target")
+	session.Set("SenderCompID", "sender")
+	session.Set("HeartBtInt", "1")
+
+	// The store factory knows the session, the initiator starts without it.
+	storeSettings := quickfix.NewSettings()
+	storeSettings.GlobalSettings().Set("BoltStorePath", t.TempDir())
+	_, err = storeSettings.AddSession(session)
+	require.NoError(t, err)
+
+	c := &Client{t: t}
+	c.Initiator, err = quickfix.NewInitiator(c, bolt.NewStoreFactory(storeSettings), quickfix.NewSettings(), quickfix.NewSlogLogger(), quickfix.WithInitiatorRegistry(quickfix.NewRegistry()))
+	require.NoError(t, err)
+	c.Start()
+	t.Cleanup(c.Stop)
+
+	for i := 0; i < 2; i++ {
+		// Adding the session again fails on the lock of the database file unless removing it closed the store.
+		c.sessionID, err = c.AddSession(session)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return c.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+
+		require.NoError(t, c.RemoveSession(c.sessionID))
+		require.Eventually(t, func() bool { return s.isLoggedIn.Load() == 0 }, 10*time.Second, 100*time.Millisecond)
+	}
+}
+
+func TestInitiatorReloadRunning(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	s := NewServer(t, port).Start()
+	t.Cleanup(s.Stop)
+
+	clientSettings := func(extra map[string]string) *quickfix.Settings {
+		settings := quickfix.NewSettings()
+		settings.GlobalSettings().Set("BoltStorePath", t.TempDir())
+		session := quickfix.NewSessionSettings()
+		session.Set("BeginString", "FIX.4.4")
+		session.Set("SocketConnectPort", strconv.Itoa(port))
+		session.Set("SocketConnectHost", "localhost")
+		session.Set("TargetCompID", "target")
+		session.Set("SenderCompID", "sender")
+		session.Set("HeartBtInt", "1")
+		session.Set("ReconnectInterval", "1")
+		for setting, value := range extra {
+			session.Set(setting, value)
+		}
+		_, err := settings.AddSession(session)
+		require.NoError(t, err)
+		return settings
+	}
+
+	settings := clientSettings(nil)
+	registry := quickfix.NewRegistry()
+	c := &Client{t: t}
+	c.Initiator, err = quickfix.NewInitiator(c, bolt.NewStoreFactory(settings), settings, quickfix.NewSlogLogger(), quickfix.WithInitiatorRegistry(registry))
+	require.NoError(t, err)
+	c.Start()
+	t.Cleanup(c.Stop)
+	require.Eventually(t, func() bool { return c.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+
+	// Settings applied to the running session while it sends.
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "sender", TargetCompID: "target"}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, registry.SendToTarget(ack.New(field.NewTradeReportID(strconv.Itoa(i)), field.NewExecType(enum.ExecType_NEW)), sessionID))
+		}
+	}()
+
+	for _, precision := range []string{"MICROS", "NANOS", "MILLIS"} {
+		report, err := c.Reload(clientSettings(map[string]string{"TimeStampPrecision": precision, "ValidateFieldsOutOfOrder": "N"}))
+		require.NoError(t, err)
+		require.Len(t, report.Applied, 1)
+	}
+	<-done
+
+	// A changed connection setting restarts the session, which opens its store again.
+	report, err := c.Reload(clientSettings(map[string]string{"ReconnectInterval": "2"}))
+	require.NoError(t, err)
+	require.Len(t, report.Restarted, 1)
+	require.Eventually(t, func() bool { return s.isLoggedIn.Load() == 1 && c.isLoggedIn.Load() == 1 }, 10*time.Second, 100*time.Millisecond)
+}
@@ -17,9 +17,14 @@ package quickfix
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quickfixgo/quickfix/datadictionary"
@@ -31,6 +36,7 @@ type session struct {
 	store MessageStore
 
 	log       Log
+	metrics   MetricsSink
 	sessionID SessionID
 
 	messageOut chan<- []byte
@@ -38,9 +44,16 @@ type session struct {
 
 	// Application messages are queued up for send here.
 	toSend [][]byte
+	// Length of toSend, readable without holding sendMutex.
+	toSendDepth atomic.Int64
 
 	// Mutex for access to toSend.
 	sendMutex sync.Mutex
+	// Signalled on sendMutex when toSend drains, created by the first sender waiting for space.
+	queueSpace *sync.Cond
+	// Set when a message was refused by the DISCONNECT outbound queue policy or could not be stored in time,
+	// the session goroutine then drops the connection. Guarded by sendMutex.
+	disconnectErr error
 	// Mutex to prevent messages being sent when resendRequest is active
 	// Must be locked before sendMutex to prevent a potential deadlock
 	resendMutex sync.RWMutex
@@ -63,6 +76,31 @@ type session struct {
 	appDataDictionary       *datadictionary.DataDictionary
 
 	timestampPrecision TimestampPrecision
+
+	// Limits applied to messages received on each connection of an acceptor session.
+	inboundLimits inboundLimitSettings
+
+	// Checks the Logon received by an acceptor session, optional.
+	authenticator Authenticator
+	// Certificates presented by the counterparty on the current TLS connection.
+	peerCertificates []*x509.Certificate
+
+	// Outbound throttle for application messages sent with SendToTarget.
+	throttleSettings throttleSettings
+	throttle         *throttle
+
+	// Limits of the send queue and of the inbound queue of each connection.
+	queueSettings queueSettings
+
+	// HeartBtInt from reloaded settings, applied when the current connection ends.
+	pendingHeartBtInt time.Duration
+
+	// Runtime details published for SessionHandle.
+	status sessionStatus
+
+	// Registry the session is registered with, receives lifecycle events.
+	registry         *Registry
+	disconnectReason string
 }
 
 func (s *session) logError(err error) {
@@ -78,15 +116,28 @@ func (s *session) TargetDefaultApplicationVersionID() string {
 type connect struct {
 	messageOut chan<- []byte
 	messageIn  <-chan fixIn
-	err        chan<- error
+	remoteAddr net.Addr
+	// Certificates presented by the counterparty on a TLS connection.
+	peerCertificates []*x509.Certificate
+	// SocketConnectAddress in use by an initiator.
+	endpoint string
+	// Configured with the settings of the session before err is closed, optional.
+	parser *parser
+	writer *writer
+	err    chan<- error
 }
 
-func (s *session) connect(msgIn <-chan fixIn, msgOut chan<- []byte) error {
+func (s *session) connect(msgIn <-chan fixIn, msgOut chan<- []byte, netConn net.Conn, endpoint string, parser *parser, writer *writer) error {
 	rep := make(chan error)
 	s.admin <- connect{
-		messageOut: msgOut,
-		messageIn:  msgIn,
-		err:        rep,
+		messageOut:       msgOut,
+		messageIn:        msgIn,
+		remoteAddr:       netConn.RemoteAddr(),
+		peerCertificates: peerCertificates(netConn),
+		endpoint:         endpoint,
+		parser:           parser,
+		writer:           writer,
+		err:              rep,
 	}
 
 	return <-rep
@@ -101,6 +152,178 @@ func (s *session) stop() {
 	})
 }
 
+// close releases the store of a session that is no longer running, and its log if the log is an io.Closer.
+func (s *session) close() error {
+	err := s.store.Close()
+	if closer, ok := s.log.(io.Closer); ok {
+		if logErr := closer.Close(); err == nil {
+			err = logErr
+		}
+	}
+	return err
+}
+
+var errSessionNotRunning = errors.New("Session not running")
+
+// sendAdmin hands req to the session goroutine, failing if the session is not running.
+func (s *session) sendAdmin(req interface{}) error {
+	done := s.status.runningChan()
+	if done == nil {
+		return errSessionNotRunning
+	}
+
+	select {
+	case s.admin <- req:
+		return nil
+	case <-done:
+		return errSessionNotRunning
+	}
+}
+
+type logoutReq struct {
+	reason string
+	rep    chan<- error
+}
+
+// logout initiates a Logout carrying reason as Text(58), the connection is dropped once the counterparty responds.
+func (s *session) logout(reason string) error {
+	rep := make(chan error, 1)
+	if err := s.sendAdmin(logoutReq{reason: reason, rep: rep}); err != nil {
+		return err
+	}
+	return <-rep
+}
+
+type reconfigureReq struct {
+	from *session
+	rep  chan<- error
+}
+
+// reconfigure applies the settings of from, built by sessionFactory.configureSession, to the session, and replaces
+// the log of the session with from.log if it is set. A running session applies them on the session goroutine.
+func (s *session) reconfigure(from *session) error {
+	for {
+		s.sendMutex.Lock()
+		if s.status.runningChan() == nil {
+			// The session goroutine cannot start before sendMutex is released, see run.
+			s.applySettings(from)
+			s.sendMutex.Unlock()
+			s.applyThrottleSettings()
+			return nil
+		}
+		s.sendMutex.Unlock()
+
+		rep := make(chan error, 1)
+		err := s.sendAdmin(reconfigureReq{from: from, rep: rep})
+		if err == errSessionNotRunning {
+			// Stopped meanwhile.
+			continue
+		} else if err != nil {
+			return err
+		}
+		return <-rep
+	}
+}
+
+// applySettings replaces the settings of the session with those of from. sendMutex must be held,
+// as the settings used to send are read under it by the goroutines sending application messages.
+func (s *session) applySettings(from *session) {
+	heartBtInt := s.HeartBtInt
+	s.SessionSettings = from.SessionSettings
+	s.Validator = from.Validator
+	s.transportDataDictionary = from.transportDataDictionary
+	s.appDataDictionary = from.appDataDictionary
+	s.timestampPrecision = from.timestampPrecision
+	s.inboundLimits = from.inboundLimits
+	s.authenticator = from.authenticator
+	s.throttleSettings = from.throttleSettings
+
+	// HeartBtInt was agreed at logon, the new value is used from the next logon.
+	if s.State != nil && s.IsConnected() {
+		s.HeartBtInt = heartBtInt
+		s.pendingHeartBtInt = from.HeartBtInt
+	}
+
+	if from.log != nil {
+		s.setLog(from.log)
+	}
+}
+
+// currentSettings returns a copy of the settings of the session, for use outside of the session goroutine.
+func (s *session) currentSettings() internal.SessionSettings {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+	return s.SessionSettings
+}
+
+// applyThrottleSettings configures the throttle with the settings of the session. sendMutex must not be held,
+// the throttle may be releasing queued messages, which waits for sendMutex.
+func (s *session) applyThrottleSettings() {
+	if s.throttle != nil {
+		s.throttle.configure(s.throttleSettings)
+	}
+}
+
+// setLog replaces the log of the session, the previous log is closed if it is an io.Closer.
+func (s *session) setLog(log Log) {
+	current, ok := s.log.(*swappableLog)
+	if !ok {
+		s.log = log
+		return
+	}
+
+	if err := current.swap(log); err != nil {
+		s.log.OnEventf("Failed to close the previous log: %v", err)
+	}
+}
+
+type disconnectReq struct {
+	rep chan<- error
+}
+
+// disconnect drops the session connection without a Logout exchange.
+func (s *session) disconnect() error {
+	rep := make(chan error, 1)
+	if err := s.sendAdmin(disconnectReq{rep: rep}); err != nil {
+		return err
+	}
+	return <-rep
+}
+
+type resendReq struct {
+	beginSeq, endSeq int
+	rep              chan<- error
+}
+
+// requestResend sends a ResendRequest for messages from beginSeq to endSeq already received, see Registry.ResendRequest.
+func (s *session) requestResend(beginSeq, endSeq int) error {
+	rep := make(chan error, 1)
+	if err := s.sendAdmin(resendReq{beginSeq: beginSeq, endSeq: endSeq, rep: rep}); err != nil {
+		return err
+	}
+	return <-rep
+}
+
+type replayReq struct {
+	beginSeq, endSeq int
+	rep              chan<- error
+}
+
+// replay resends the stored messages from beginSeq to endSeq, see Registry.ResendMessages.
+func (s *session) replay(beginSeq, endSeq int) error {
+	rep := make(chan error, 1)
+	if err := s.sendAdmin(replayReq{beginSeq: beginSeq, endSeq: endSeq, rep: rep}); err != nil {
+		return err
+	}
+	return <-rep
+}
+
+// connectionErrorReq closes the connection receiving messageIn for err, ahead of the messages queued in messageIn.
+type connectionErrorReq struct {
+	messageIn <-chan fixIn
+	err       error
+}
+
 type waitChan <-chan interface{}
 
 type waitForInSessionReq struct{ rep chan<- waitChan }
@@ -164,10 +387,11 @@ func (s *session) shouldSendReset() bool {
 }
 
 func (s *session) sendLogon() error {
-	return s.sendLogonInReplyTo(s.shouldSendReset(), nil)
+	return s.sendLogonInReplyTo(s.shouldSendReset(), nil, nil)
 }
 
-func (s *session) sendLogonInReplyTo(setResetSeqNum bool, inReplyTo *Message) error {
+// sendLogonInReplyTo sends a Logon, answering sessionStatus as SessionStatus(1409) on a FIXT.1.1 session if it is not nil.
+func (s *session) sendLogonInReplyTo(setResetSeqNum bool, inReplyTo *Message, sessionStatus *LogonStatus) error {
 	logon := NewMessage()
 	logon.Header.SetField(tagMsgType, FIXString("A"))
 	logon.Header.SetField(tagBeginString, FIXString(s.sessionID.BeginString))
@@ -184,6 +408,10 @@ func (s *session) sendLogonInReplyTo(setResetSeqNum bool, inReplyTo *Message) er
 		logon.Body.SetField(tagDefaultApplVerID, FIXString(s.DefaultApplVerID))
 	}
 
+	if sessionStatus != nil && s.sessionID.BeginString == BeginStringFIXT11 {
+		logon.Body.SetField(tagSessionStatus, FIXInt(*sessionStatus))
+	}
+
 	// Evaluate tag 789.
 	if s.EnableNextExpectedMsgSeqNum {
 		if inReplyTo != nil {
@@ -233,6 +461,7 @@ func (s *session) generateSequenceReset(beginSeqNo int, endSeqNo int, inReplyTo
 
 	s.EnqueueBytesAndSend(msgBytes)
 	s.log.OnEventf("Sent SequenceReset TO: %v", endSeqNo)
+	s.metrics.GapFillSent(s.sessionID)
 
 	return
 }
@@ -272,23 +501,46 @@ func (s *session) resend(msg *Message) bool {
 	return s.application.ToApp(msg, s.sessionID) == nil
 }
 
-// queueForSend will validate, persist, and queue the message for send.
+// queueForSend will throttle, validate, persist, and queue the message for send.
 func (s *session) queueForSend(msg *Message) error {
+	if s.throttle != nil {
+		if queued, err := s.throttle.admit(msg); err != nil || queued {
+			return err
+		}
+	}
+
+	return s.queueForSendBounded(msg)
+}
+
+// queueForSendNow will validate, persist, and queue the message for send.
+func (s *session) queueForSendNow(msg *Message) error {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
+	return s.queueLocked(msg)
+}
+
+// queueLocked will validate, persist, and queue the message for send. sendMutex must be held.
+func (s *session) queueLocked(msg *Message) error {
 	msgBytes, err := s.prepMessageForSend(msg, nil)
 	if err != nil {
 		return err
 	}
 
-	s.toSend = append(s.toSend, msgBytes)
+	s.enqueue(msgBytes)
 
 	s.notifyMessageOut()
 
 	return nil
 }
 
+// releaseThrottled sends a message held by the outbound throttle, applying OutboundQueueLimit.
+func (s *session) releaseThrottled(msg *Message) {
+	if err := s.queueForSendBounded(msg); err != nil {
+		s.log.OnEventf("Failed to send throttled message: %v", err)
+	}
+}
+
 func (s *session) notifyMessageOut() {
 	select {
 	case s.messageEvent <- true:
@@ -302,7 +554,7 @@ func (s *session) send(msg *Message) error {
 }
 func (s *session) sendInReplyTo(msg *Message, inReplyTo *Message) error {
 	if !s.IsLoggedOn() {
-		return s.queueForSend(msg)
+		return s.queueForSendNow(msg)
 	}
 
 	// resendMutex must always be locked before sendMutex to prevent a potential deadlock
@@ -317,7 +569,7 @@ func (s *session) sendInReplyTo(msg *Message, inReplyTo *Message) error {
 		return err
 	}
 
-	s.toSend = append(s.toSend, msgBytes)
+	s.enqueue(msgBytes)
 	s.sendQueued(true)
 
 	return nil
@@ -346,7 +598,7 @@ func (s *session) dropAndSendInReplyTo(msg *Message, inReplyTo *Message) error {
 	}
 
 	s.dropQueued()
-	s.toSend = append(s.toSend, msgBytes)
+	s.enqueue(msgBytes)
 	s.sendQueued(true)
 
 	return nil
@@ -395,18 +647,92 @@ func (s *session) prepMessageForSend(msg *Message, inReplyTo *Message) (msgBytes
 	return
 }
 
+// persist stores the message and increments the next sender seqnum. sendMutex must be held.
 func (s *session) persist(seqNum int, msgBytes []byte) error {
+	store, ok := s.store.(MessageStoreWithContext)
+	if !ok || s.StoreTimeout == 0 {
+		if !s.DisableMessagePersist {
+			return s.store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msgBytes)
+		}
+
+		return s.store.IncrNextSenderMsgSeqNum()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.StoreTimeout)
+	defer cancel()
+
+	var err error
 	if !s.DisableMessagePersist {
-		return s.store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, msgBytes)
+		err = store.SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx, seqNum, msgBytes)
+	} else {
+		err = store.IncrNextSenderMsgSeqNumContext(ctx)
+	}
+
+	if err = s.storeTimeoutError(ctx, err); err != nil && ctx.Err() != nil {
+		// The store may be stalled, the session goroutine drops the connection, see stateMachine.SendAppMessages.
+		s.disconnectErr = err
+		s.notifyMessageOut()
+	}
+	return err
+}
+
+// incrNextTargetMsgSeqNum increments the next target seqnum for a message received, within StoreTimeout if the store
+// implements MessageStoreWithContext. On the session goroutine, an error drops the connection.
+func (s *session) incrNextTargetMsgSeqNum() error {
+	store, ok := s.store.(MessageStoreWithContext)
+	if !ok || s.StoreTimeout == 0 {
+		return s.store.IncrNextTargetMsgSeqNum()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.StoreTimeout)
+	defer cancel()
+	return s.storeTimeoutError(ctx, store.IncrNextTargetMsgSeqNumContext(ctx))
+}
+
+// setNextTargetMsgSeqNum sets the next target seqnum for a SequenceReset received, as incrNextTargetMsgSeqNum.
+func (s *session) setNextTargetMsgSeqNum(next int) error {
+	store, ok := s.store.(MessageStoreWithContext)
+	if !ok || s.StoreTimeout == 0 {
+		return s.store.SetNextTargetMsgSeqNum(next)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.StoreTimeout)
+	defer cancel()
+	return s.storeTimeoutError(ctx, store.SetNextTargetMsgSeqNumContext(ctx, next))
+}
+
+// storeTimeoutError returns err of a store operation, noting StoreTimeout if it failed as ctx timed out.
+func (s *session) storeTimeoutError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("message store timed out after %v: %w", s.StoreTimeout, err)
+	}
+	return err
+}
+
+// iterateSentMessages calls cb with the stored messages from beginSeqNum to endSeqNum, within
+// StoreResendTimeout if the store implements MessageStoreWithContext.
+func (s *session) iterateSentMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	store, ok := s.store.(MessageStoreWithContext)
+	if !ok || s.StoreResendTimeout == 0 {
+		return s.store.IterateMessages(beginSeqNum, endSeqNum, cb)
 	}
 
-	return s.store.IncrNextSenderMsgSeqNum()
+	ctx, cancel := context.WithTimeout(context.Background(), s.StoreResendTimeout)
+	defer cancel()
+
+	err := store.IterateMessagesContext(ctx, beginSeqNum, endSeqNum, cb)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("message store timed out after %v: %w", s.StoreResendTimeout, err)
+	}
+	return err
 }
 
 func (s *session) sendQueued(blockUntilSent bool) {
 	for i, msgBytes := range s.toSend {
 		if !s.sendBytes(msgBytes, blockUntilSent) {
 			s.toSend = s.toSend[i:]
+			s.toSendDepth.Store(int64(len(s.toSend)))
+			s.queueDrained()
 			s.notifyMessageOut()
 			return
 		}
@@ -415,15 +741,23 @@ func (s *session) sendQueued(blockUntilSent bool) {
 	s.dropQueued()
 }
 
+// enqueue appends msgBytes to the send queue. sendMutex must be held.
+func (s *session) enqueue(msgBytes []byte) {
+	s.toSend = append(s.toSend, msgBytes)
+	s.toSendDepth.Store(int64(len(s.toSend)))
+}
+
 func (s *session) dropQueued() {
 	s.toSend = s.toSend[:0]
+	s.toSendDepth.Store(0)
+	s.queueDrained()
 }
 
 func (s *session) EnqueueBytesAndSend(msg []byte) {
 	s.sendMutex.Lock()
 	defer s.sendMutex.Unlock()
 
-	s.toSend = append(s.toSend, msg)
+	s.enqueue(msg)
 	s.sendQueued(true)
 }
 
@@ -436,6 +770,8 @@ func (s *session) sendBytes(msg []byte, blockUntilSent bool) bool {
 	if blockUntilSent {
 		s.messageOut <- msg
 		s.log.OnOutgoing(msg)
+		s.metrics.MessageOut(s.sessionID, string(msgTypeFromBytes(msg)))
+		s.status.setLastSent(time.Now())
 		s.stateTimer.Reset(s.HeartBtInt)
 		return true
 	}
@@ -443,6 +779,8 @@ func (s *session) sendBytes(msg []byte, blockUntilSent bool) bool {
 	select {
 	case s.messageOut <- msg:
 		s.log.OnOutgoing(msg)
+		s.metrics.MessageOut(s.sessionID, string(msgTypeFromBytes(msg)))
+		s.status.setLastSent(time.Now())
 		s.stateTimer.Reset(s.HeartBtInt)
 		return true
 	default:
@@ -452,6 +790,7 @@ func (s *session) sendBytes(msg []byte, blockUntilSent bool) bool {
 
 func (s *session) doTargetTooHigh(reject targetTooHigh) (nextState resendState, err error) {
 	s.log.OnEventf("MsgSeqNum too high, expecting %v but received %v", reject.ExpectedTarget, reject.ReceivedTarget)
+	s.emit(SessionEvent{Type: SessionGapDetected, BeginSeqNo: reject.ExpectedTarget, EndSeqNo: reject.ReceivedTarget - 1})
 	return s.sendResendRequest(reject.ExpectedTarget, reject.ReceivedTarget-1)
 }
 
@@ -484,10 +823,63 @@ func (s *session) sendResendRequest(beginSeq, endSeq int) (nextState resendState
 		return
 	}
 	s.log.OnEventf("Sent ResendRequest FROM: %v TO: %v", beginSeq, endSeqNo)
+	s.metrics.ResendRequestSent(s.sessionID)
 
 	return
 }
 
+// sendResendRequestReceived sends a ResendRequest for messages from beginSeq to endSeq already received, 0 for endSeq
+// is the last message received. Unlike a gap, the messages resent are not expected and the session stays in session.
+func (s *session) sendResendRequestReceived(beginSeq, endSeq int) error {
+	if _, ok := s.State.(inSession); !ok {
+		return errors.New("Not in session")
+	}
+
+	nextTargetSeq := s.store.NextTargetMsgSeqNum()
+	if endSeq == 0 {
+		endSeq = nextTargetSeq - 1
+	}
+	if beginSeq < 1 || beginSeq > endSeq || endSeq >= nextTargetSeq {
+		return fmt.Errorf("Invalid resend range %v to %v, next expected MsgSeqNum is %v", beginSeq, endSeq, nextTargetSeq)
+	}
+
+	resend := NewMessage()
+	resend.Header.SetBytes(tagMsgType, msgTypeResendRequest)
+	resend.Body.SetField(tagBeginSeqNo, FIXInt(beginSeq))
+	resend.Body.SetField(tagEndSeqNo, FIXInt(endSeq))
+	if err := s.send(resend); err != nil {
+		return err
+	}
+	s.log.OnEventf("Sent ResendRequest FROM: %v TO: %v", beginSeq, endSeq)
+	s.metrics.ResendRequestSent(s.sessionID)
+
+	return nil
+}
+
+// replayMessages resends the stored messages from beginSeq to endSeq already sent, 0 for endSeq is the last message
+// sent. Messages are resent as for a ResendRequest received, with PossDupFlag, and admin messages and gaps are gap filled.
+func (s *session) replayMessages(beginSeq, endSeq int) error {
+	state, ok := s.State.(inSession)
+	if !ok {
+		return errors.New("Not in session")
+	}
+
+	nextSenderSeq := s.store.NextSenderMsgSeqNum()
+	if endSeq == 0 {
+		endSeq = nextSenderSeq - 1
+	}
+	if beginSeq < 1 || beginSeq > endSeq || endSeq >= nextSenderSeq {
+		return fmt.Errorf("Invalid replay range %v to %v, next MsgSeqNum is %v", beginSeq, endSeq, nextSenderSeq)
+	}
+
+	s.log.OnEventf("Replaying messages FROM: %v TO: %v", beginSeq, endSeq)
+
+	// The last message received is the message replied to, for LastMsgSeqNumProcessed.
+	inReplyTo := NewMessage()
+	inReplyTo.Header.SetInt(tagMsgSeqNum, s.store.NextTargetMsgSeqNum()-1)
+	return state.resendMessages(s, beginSeq, endSeq, *inReplyTo)
+}
+
 func (s *session) handleLogon(msg *Message) error {
 	// Grab default app ver id from fixt.1.1 logon.
 	if s.sessionID.BeginString == BeginStringFIXT11 {
@@ -516,6 +908,16 @@ func (s *session) handleLogon(msg *Message) error {
 
 	nextSenderMsgNumAtLogonReceived := s.store.NextSenderMsgSeqNum()
 
+	// Authenticate before the application sees the logon.
+	var sessionStatus *LogonStatus
+	if !s.InitiateLogon && s.authenticator != nil {
+		status, err := s.authenticate(msg)
+		if err != nil {
+			return err
+		}
+		sessionStatus = &status
+	}
+
 	// Make sure this is a valid session before resetting the store.
 	if err := s.verifyMsgAgainstAppImpl(msg); err != nil {
 		return err
@@ -551,8 +953,14 @@ func (s *session) handleLogon(msg *Message) error {
 			}
 		}
 
+		if sessionStatus != nil && *sessionStatus == LogonStatusPasswordChanged {
+			if err := s.changePassword(msg); err != nil {
+				return err
+			}
+		}
+
 		s.log.OnEvent("Responding to logon request")
-		if err := s.sendLogonInReplyTo(resetSeqNumFlag.Bool(), msg); err != nil {
+		if err := s.sendLogonInReplyTo(resetSeqNumFlag.Bool(), msg, sessionStatus); err != nil {
 			return err
 		}
 	}
@@ -582,7 +990,7 @@ func (s *session) handleLogon(msg *Message) error {
 		return err
 	}
 
-	return s.store.IncrNextTargetMsgSeqNum()
+	return s.incrNextTargetMsgSeqNum()
 }
 
 func (s *session) initiateLogout(reason string) (err error) {
@@ -595,6 +1003,7 @@ func (s *session) initiateLogoutInReplyTo(reason string, inReplyTo *Message) (er
 		return
 	}
 	s.log.OnEvent("Inititated logout request")
+	s.emit(SessionEvent{Type: SessionLoggedOut, Reason: reason})
 	time.AfterFunc(s.LogoutTimeout, func() { s.sessionEvent <- internal.LogoutTimeout })
 	return
 }
@@ -802,12 +1211,36 @@ func (s *session) doReject(msg *Message, rej MessageRejectError) error {
 	}
 
 	s.log.OnEventf("Message Rejected: %v", rej.Error())
+	if replyMsgType, err := reply.Header.GetString(tagMsgType); err == nil {
+		s.metrics.Reject(s.sessionID, replyMsgType, rej.RejectReason())
+	}
 	return s.sendInReplyTo(reply, msg)
 }
 
+// recordIncoming reports an incoming message to the metrics sink.
+func (s *session) recordIncoming(msg *Message) {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil {
+		return
+	}
+	s.metrics.MessageIn(s.sessionID, string(msgType))
+
+	if bytes.Equal(msgType, msgTypeHeartbeat) {
+		if sendingTime, err := msg.Header.GetTime(tagSendingTime); err == nil {
+			s.metrics.HeartbeatLatency(s.sessionID, msg.ReceiveTime.Sub(sendingTime))
+		}
+	}
+}
+
 type fixIn struct {
 	bytes       *bytes.Buffer
 	receiveTime time.Time
+
+	// The inbound limit the message is over, if any.
+	breach error
+
+	// Set instead of bytes if the connection is closed for a message that cannot be read.
+	err error
 }
 
 func (s *session) onDisconnect() {
@@ -824,6 +1257,16 @@ func (s *session) onDisconnect() {
 	}
 
 	s.messageIn = nil
+	s.status.setRemoteAddr(nil, "")
+	s.status.setMessageIn(nil)
+
+	if s.pendingHeartBtInt != 0 {
+		s.HeartBtInt = s.pendingHeartBtInt
+		s.pendingHeartBtInt = 0
+	}
+
+	s.emit(SessionEvent{Type: SessionDisconnected, Reason: s.disconnectReason})
+	s.disconnectReason = ""
 }
 
 func (s *session) onAdmin(msg interface{}) {
@@ -848,6 +1291,20 @@ func (s *session) onAdmin(msg interface{}) {
 			return
 		}
 
+		if msg.parser != nil {
+			msg.parser.configure(s.SessionSettings, s.inboundLimits, s.log)
+			msg.parser.disconnectWhenQueueFull = nil
+			if s.queueSettings.inboundPolicy == queueDisconnect {
+				messageIn := msg.messageIn
+				msg.parser.disconnectWhenQueueFull = func(err error) {
+					_ = s.sendAdmin(connectionErrorReq{messageIn: messageIn, err: err})
+				}
+			}
+		}
+		if msg.writer != nil {
+			msg.writer.configure(s.SessionSettings)
+		}
+
 		if msg.err != nil {
 			close(msg.err)
 		}
@@ -855,6 +1312,10 @@ func (s *session) onAdmin(msg interface{}) {
 		s.messageIn = msg.messageIn
 		s.messageOut = msg.messageOut
 		s.sentReset = false
+		s.disconnectReason = ""
+		s.peerCertificates = msg.peerCertificates
+		s.status.setRemoteAddr(msg.remoteAddr, msg.endpoint)
+		s.status.setMessageIn(msg.messageIn)
 
 		s.Connect(s)
 
@@ -866,10 +1327,71 @@ func (s *session) onAdmin(msg interface{}) {
 			msg.rep <- s.stateMachine.notifyOnInSessionTime
 		}
 		close(msg.rep)
+
+	case logoutReq:
+		if !s.IsLoggedOn() {
+			msg.rep <- errors.New("Not logged on")
+			return
+		}
+
+		if err := s.initiateLogout(msg.reason); err != nil {
+			msg.rep <- err
+			return
+		}
+		s.setState(s, logoutState{})
+		msg.rep <- nil
+
+	case reconfigureReq:
+		s.sendMutex.Lock()
+		s.applySettings(msg.from)
+		s.sendMutex.Unlock()
+		s.applyThrottleSettings()
+
+		s.log.OnEvent("Settings reloaded")
+		msg.rep <- nil
+
+	case resendReq:
+		msg.rep <- s.sendResendRequestReceived(msg.beginSeq, msg.endSeq)
+
+	case replayReq:
+		msg.rep <- s.replayMessages(msg.beginSeq, msg.endSeq)
+
+	case connectionErrorReq:
+		// The connection may have been replaced since.
+		if msg.messageIn == s.messageIn {
+			s.Incoming(s, fixIn{err: msg.err})
+		}
+
+	case disconnectReq:
+		if !s.IsConnected() {
+			msg.rep <- errors.New("Not connected")
+			return
+		}
+
+		s.log.OnEvent("Disconnect requested")
+		s.setDisconnectReason("Disconnect requested")
+		s.setState(s, latentState{})
+		msg.rep <- nil
 	}
 }
 
 func (s *session) run() {
+	// Running is set under sendMutex, so reconfigure either applies settings before the session starts,
+	// or hands them to the session goroutine.
+	running := make(chan struct{})
+	s.sendMutex.Lock()
+	s.status.setRunning(running)
+	s.sendMutex.Unlock()
+	defer func() {
+		s.sendMutex.Lock()
+		s.status.setRunning(nil)
+		close(running)
+
+		// Senders waiting for queue space fail once the session is not running.
+		s.queueDrained()
+		s.sendMutex.Unlock()
+	}()
+
 	s.Start(s)
 	var stopChan = make(chan struct{})
 	s.stateTimer = internal.NewEventTimer(func() {
@@ -902,6 +1424,11 @@ func (s *session) run() {
 		s.stateTimer.Stop()
 		s.peerTimer.Stop()
 		ticker.Stop()
+		if s.throttle != nil {
+			if dropped := s.throttle.stop(); dropped > 0 {
+				s.log.OnEventf("Dropped %v throttled messages", dropped)
+			}
+		}
 	}()
 
 	for !s.Stopped() {
@@ -0,0 +1,92 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import "time"
+
+// SessionEventType identifies the kind of a SessionEvent.
+type SessionEventType int
+
+const (
+	// SessionStateChanged is emitted when the session state machine moves to a new state.
+	SessionStateChanged SessionEventType = iota
+	// SessionLoggedOn is emitted when the session becomes logged on.
+	SessionLoggedOn
+	// SessionLoggedOut is emitted when a Logout is sent or received. Reason holds the Logout Text(58), if any.
+	SessionLoggedOut
+	// SessionGapDetected is emitted when an incoming MsgSeqNum is higher than expected.
+	// BeginSeqNo and EndSeqNo hold the missing range.
+	SessionGapDetected
+	// SessionResendRequested is emitted when the counterparty sends a ResendRequest.
+	// BeginSeqNo and EndSeqNo hold the requested range.
+	SessionResendRequested
+	// SessionSequenceReset is emitted when the counterparty sends a SequenceReset.
+	// BeginSeqNo holds the expected MsgSeqNum and EndSeqNo holds NewSeqNo(36).
+	SessionSequenceReset
+	// SessionDisconnected is emitted when the session connection is dropped. Reason holds the cause, if known.
+	SessionDisconnected
+)
+
+func (t SessionEventType) String() string {
+	switch t {
+	case SessionStateChanged:
+		return "StateChanged"
+	case SessionLoggedOn:
+		return "LoggedOn"
+	case SessionLoggedOut:
+		return "LoggedOut"
+	case SessionGapDetected:
+		return "GapDetected"
+	case SessionResendRequested:
+		return "ResendRequested"
+	case SessionSequenceReset:
+		return "SequenceReset"
+	case SessionDisconnected:
+		return "Disconnected"
+	}
+	return "Unknown"
+}
+
+// SessionEvent is a typed session lifecycle event delivered to Registry subscribers.
+type SessionEvent struct {
+	Type      SessionEventType
+	SessionID SessionID
+	Time      time.Time
+
+	// FromState and ToState are set for SessionStateChanged.
+	FromState, ToState string
+
+	// Reason is set for SessionLoggedOut and SessionDisconnected.
+	Reason string
+
+	// BeginSeqNo and EndSeqNo are set for SessionGapDetected, SessionResendRequested and SessionSequenceReset.
+	BeginSeqNo, EndSeqNo int
+}
+
+func (s *session) emit(evt SessionEvent) {
+	if s.registry == nil {
+		return
+	}
+
+	evt.SessionID = s.sessionID
+	evt.Time = time.Now()
+	s.registry.publish(evt)
+}
+
+// setDisconnectReason records the cause reported with the next SessionDisconnected event.
+func (s *session) setDisconnectReason(reason string) {
+	s.disconnectReason = reason
+}
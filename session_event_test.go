@@ -0,0 +1,137 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/internal"
+)
+
+type SessionEventTestSuite struct {
+	SessionSuiteRig
+	registry *Registry
+	events   <-chan SessionEvent
+}
+
+func TestSessionEventTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionEventTestSuite))
+}
+
+func (s *SessionEventTestSuite) SetupTest() {
+	s.Init()
+	s.registry = NewRegistry()
+	s.Require().Nil(s.registry.registerSession(s.session))
+	s.events = s.registry.Subscribe(10)
+}
+
+func (s *SessionEventTestSuite) nextEvent(expected SessionEventType) SessionEvent {
+	select {
+	case evt := <-s.events:
+		s.Require().Equal(expected, evt.Type, "unexpected event %v", evt)
+		s.Equal(s.sessionID, evt.SessionID)
+		s.False(evt.Time.IsZero())
+		return evt
+	default:
+		s.FailNow("no event received", "expected %v", expected)
+	}
+	return SessionEvent{}
+}
+
+func (s *SessionEventTestSuite) noEvent() {
+	select {
+	case evt := <-s.events:
+		s.Failf("unexpected event", "%v", evt)
+	default:
+	}
+}
+
+func (s *SessionEventTestSuite) TestLogon() {
+	s.session.State = logonState{}
+	s.session.setState(s.session, inSession{})
+
+	evt := s.nextEvent(SessionStateChanged)
+	s.Equal(logonState{}.String(), evt.FromState)
+	s.Equal(inSession{}.String(), evt.ToState)
+	s.nextEvent(SessionLoggedOn)
+	s.noEvent()
+}
+
+func (s *SessionEventTestSuite) TestLogonTimeout() {
+	s.session.State = logonState{}
+	s.session.Timeout(s.session, internal.LogonTimeout)
+
+	evt := s.nextEvent(SessionDisconnected)
+	s.Equal("Timed out waiting for logon response", evt.Reason)
+	s.nextEvent(SessionStateChanged)
+	s.noEvent()
+}
+
+func (s *SessionEventTestSuite) TestLogoutReceived() {
+	s.session.State = inSession{}
+	s.MockApp.On("FromAdmin").Return(nil)
+	s.MockApp.On("ToAdmin")
+	s.MockApp.On("OnLogout")
+
+	logout := s.Logout()
+	logout.Body.SetField(tagText, FIXString("end of day"))
+	s.session.fixMsgIn(s.session, logout)
+	s.MockApp.AssertExpectations(s.T())
+
+	evt := s.nextEvent(SessionLoggedOut)
+	s.Equal("end of day", evt.Reason)
+	evt = s.nextEvent(SessionDisconnected)
+	s.Equal("Logout", evt.Reason)
+	s.nextEvent(SessionStateChanged)
+	s.noEvent()
+}
+
+func (s *SessionEventTestSuite) TestGapDetected() {
+	s.session.State = inSession{}
+	s.MockApp.On("ToAdmin")
+
+	s.MessageFactory.SetNextSeqNum(5)
+	s.session.fixMsgIn(s.session, s.NewOrderSingle())
+	s.MockApp.AssertExpectations(s.T())
+
+	evt := s.nextEvent(SessionGapDetected)
+	s.Equal(1, evt.BeginSeqNo)
+	s.Equal(4, evt.EndSeqNo)
+	evt = s.nextEvent(SessionStateChanged)
+	s.Equal(resendState{}.String(), evt.ToState)
+}
+
+func (s *SessionEventTestSuite) TestUnsubscribe() {
+	s.registry.Unsubscribe(s.events)
+	_, ok := <-s.events
+	s.False(ok, "channel should be closed")
+
+	s.session.State = logonState{}
+	s.session.setState(s.session, inSession{})
+}
+
+func (s *SessionEventTestSuite) TestDroppedWhenFull() {
+	s.registry.Unsubscribe(s.events)
+	s.events = s.registry.Subscribe(1)
+
+	s.session.State = logonState{}
+	s.session.setState(s.session, inSession{})
+
+	s.nextEvent(SessionStateChanged)
+	s.noEvent()
+}
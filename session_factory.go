@@ -62,6 +62,12 @@ type sessionFactory struct {
 	// True if building sessions that initiate logon.
 	BuildInitiators bool
 	*Registry
+
+	// Receives measurements from created sessions, optional.
+	metricsSink MetricsSink
+
+	// Checks the Logon received by acceptor sessions, overrides LogonCredentialsFile, optional.
+	authenticator Authenticator
 }
 
 // Creates Session, associates with internal session registry.
@@ -91,6 +97,36 @@ func (f sessionFactory) newSession(
 		stopOnce:  sync.Once{},
 	}
 
+	if err = f.configureSession(s, settings); err != nil {
+		return
+	}
+
+	var log Log
+	if log, err = logFactory.CreateSessionLog(s.sessionID); err != nil {
+		return
+	}
+	s.log = &swappableLog{log: log}
+
+	if s.store, err = storeFactory.Create(s.sessionID); err != nil {
+		return
+	}
+
+	s.metrics = f.metricsSink
+	if s.metrics == nil {
+		s.metrics = nullMetrics{}
+	}
+
+	s.sessionEvent = make(chan internal.Event)
+	s.messageEvent = make(chan bool, 1)
+	s.admin = make(chan interface{})
+	s.application = application
+	s.throttle = newThrottle(s.throttleSettings, s.releaseThrottled)
+	return
+}
+
+// configureSession applies settings to the settings, validator and data dictionaries of s.
+// It does not touch the log, store or channels of s, so it can be used to re-read settings for a running session.
+func (f sessionFactory) configureSession(s *session, settings *SessionSettings) (err error) {
 	var validatorSettings = defaultValidatorSettings
 	if settings.HasSetting(config.ValidateFieldsOutOfOrder) {
 		if validatorSettings.CheckFieldsOutOfOrder, err = settings.BoolSetting(config.ValidateFieldsOutOfOrder); err != nil {
@@ -116,7 +152,7 @@ func (f sessionFactory) newSession(
 		}
 	}
 
-	if sessionID.IsFIXT() {
+	if s.sessionID.IsFIXT() {
 		if s.DefaultApplVerID, err = settings.Setting(config.DefaultApplVerID); err != nil {
 			return
 		}
@@ -400,36 +436,223 @@ func (f sessionFactory) newSession(
 		s.DisableMessagePersist = !persistMessages
 	}
 
-	if f.BuildInitiators {
-		if err = f.buildInitiatorSettings(s, settings); err != nil {
+	if settings.HasSetting(config.MessageStoreTimeout) {
+		if s.StoreTimeout, err = settings.DurationSetting(config.MessageStoreTimeout); err != nil {
 			return
 		}
-	} else if err = f.buildAcceptorSettings(s, settings); err != nil {
+
+		if s.StoreTimeout <= 0 {
+			err = errors.New("MessageStoreTimeout must be greater than zero")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.MessageStoreResendTimeout) {
+		if s.StoreResendTimeout, err = settings.DurationSetting(config.MessageStoreResendTimeout); err != nil {
+			return
+		}
+
+		if s.StoreResendTimeout <= 0 {
+			err = errors.New("MessageStoreResendTimeout must be greater than zero")
+			return
+		}
+	}
+
+	if err = buildParserSettings(&s.SessionSettings, settings); err != nil {
 		return
 	}
 
-	if s.log, err = logFactory.CreateSessionLog(s.sessionID); err != nil {
+	if err = buildWriterSettings(&s.SessionSettings, settings); err != nil {
 		return
 	}
 
-	if s.store, err = storeFactory.Create(s.sessionID); err != nil {
+	if err = f.buildThrottleSettings(s, settings); err != nil {
 		return
 	}
 
-	s.sessionEvent = make(chan internal.Event)
-	s.messageEvent = make(chan bool, 1)
-	s.admin = make(chan interface{})
-	s.application = application
+	if s.queueSettings, err = parseQueueSettings(settings); err != nil {
+		return
+	}
+
+	if f.BuildInitiators {
+		err = f.buildInitiatorSettings(s, settings)
+	} else {
+		err = f.buildAcceptorSettings(s, settings)
+	}
 	return
 }
 
-func (f sessionFactory) buildAcceptorSettings(session *session, settings *SessionSettings) error {
-	if err := f.buildHeartBtIntSettings(session, settings, false); err != nil {
-		return err
+// buildParserSettings reads the settings applied by the parser of a connection.
+func buildParserSettings(s *internal.SessionSettings, settings *SessionSettings) (err error) {
+	if settings.HasSetting(config.MaxMessageSize) {
+		if s.MaxMessageSize, err = settings.IntSetting(config.MaxMessageSize); err != nil {
+			return
+		}
+
+		if s.MaxMessageSize <= 0 {
+			return errors.New("MaxMessageSize must be a positive integer")
+		}
+	}
+
+	if settings.HasSetting(config.ResyncOnCorruptMessage) {
+		if s.ResyncOnCorruptMessage, err = settings.BoolSetting(config.ResyncOnCorruptMessage); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// buildWriterSettings reads the settings applied by the writer of a connection.
+func buildWriterSettings(s *internal.SessionSettings, settings *SessionSettings) (err error) {
+	s.WriteBufferSize = defaultWriteBufferSize
+	if settings.HasSetting(config.SocketWriteBufferSize) {
+		if s.WriteBufferSize, err = settings.IntSetting(config.SocketWriteBufferSize); err != nil {
+			return
+		}
+
+		if s.WriteBufferSize <= 0 {
+			return errors.New("SocketWriteBufferSize must be a positive integer")
+		}
+	}
+
+	policy := "IDLE"
+	if settings.HasSetting(config.SocketFlushPolicy) {
+		if policy, err = settings.Setting(config.SocketFlushPolicy); err != nil {
+			return
+		}
+	}
+
+	switch policy {
+	case "IDLE":
+	case "IMMEDIATE":
+		s.FlushImmediately = true
+	case "INTERVAL":
+		s.FlushInterval = defaultFlushInterval
+		if settings.HasSetting(config.SocketFlushInterval) {
+			if s.FlushInterval, err = settings.DurationSetting(config.SocketFlushInterval); err != nil {
+				return
+			}
+
+			if s.FlushInterval <= 0 {
+				return errors.New("SocketFlushInterval must be greater than zero")
+			}
+		}
+	default:
+		return IncorrectFormatForSetting{Setting: config.SocketFlushPolicy, Value: []byte(policy)}
+	}
+
+	return
+}
+
+func (f sessionFactory) buildThrottleSettings(session *session, settings *SessionSettings) error {
+	session.throttleSettings = throttleSettings{interval: time.Second, queueLimit: defaultThrottleQueueLimit}
+	if settings.HasSetting(config.ThrottleMessages) {
+		messages, err := settings.IntSetting(config.ThrottleMessages)
+		if err != nil {
+			return err
+		}
+
+		if messages <= 0 {
+			return errors.New("ThrottleMessages must be a positive integer")
+		}
+		session.throttleSettings.messages = messages
+	}
+
+	if settings.HasSetting(config.ThrottleInterval) {
+		interval, err := settings.DurationSetting(config.ThrottleInterval)
+		if err != nil {
+			intervalInt, err := settings.IntSetting(config.ThrottleInterval)
+			if err != nil {
+				return err
+			}
+
+			interval = time.Duration(intervalInt) * time.Second
+		}
+
+		if interval <= 0 {
+			return errors.New("ThrottleInterval must be greater than zero")
+		}
+		session.throttleSettings.interval = interval
+	}
+
+	if settings.HasSetting(config.ThrottleMsgTypes) {
+		msgTypesStr, err := settings.Setting(config.ThrottleMsgTypes)
+		if err != nil {
+			return err
+		}
+
+		for _, msgType := range strings.Split(msgTypesStr, ",") {
+			msgType = strings.TrimSpace(msgType)
+			if msgType == "" {
+				return IncorrectFormatForSetting{Setting: config.ThrottleMsgTypes, Value: []byte(msgTypesStr)}
+			}
+			session.throttleSettings.msgTypes = append(session.throttleSettings.msgTypes, msgType)
+		}
 	}
+
+	if settings.HasSetting(config.ThrottlePolicy) {
+		policy, err := settings.Setting(config.ThrottlePolicy)
+		if err != nil {
+			return err
+		}
+
+		switch policy {
+		case "REJECT":
+			session.throttleSettings.policy = throttleReject
+		case "BLOCK":
+			session.throttleSettings.policy = throttleBlock
+		case "QUEUE":
+			session.throttleSettings.policy = throttleQueue
+		default:
+			return IncorrectFormatForSetting{Setting: config.ThrottlePolicy, Value: []byte(policy)}
+		}
+	}
+
+	if settings.HasSetting(config.ThrottleQueueLimit) {
+		queueLimit, err := settings.IntSetting(config.ThrottleQueueLimit)
+		if err != nil {
+			return err
+		}
+
+		if queueLimit <= 0 {
+			return errors.New("ThrottleQueueLimit must be a positive integer")
+		}
+		session.throttleSettings.queueLimit = queueLimit
+	}
+
 	return nil
 }
 
+func (f sessionFactory) buildAcceptorSettings(session *session, settings *SessionSettings) (err error) {
+	if err = f.buildHeartBtIntSettings(session, settings, false); err != nil {
+		return
+	}
+
+	if session.inboundLimits, err = parseInboundLimitSettings(settings); err != nil {
+		return
+	}
+
+	if _, err = parseCertificateIdentityPolicy(settings); err != nil {
+		return
+	}
+
+	switch {
+	case f.authenticator != nil:
+		session.authenticator = f.authenticator
+
+	case settings.HasSetting(config.LogonCredentialsFile):
+		var path string
+		if path, err = settings.Setting(config.LogonCredentialsFile); err != nil {
+			return
+		}
+
+		session.authenticator, err = NewCredentialsFileAuthenticator(path)
+	}
+
+	return
+}
+
 func (f sessionFactory) buildInitiatorSettings(session *session, settings *SessionSettings) error {
 	session.InitiateLogon = true
 
@@ -456,6 +679,59 @@ func (f sessionFactory) buildInitiatorSettings(session *session, settings *Sessi
 		}
 	}
 
+	session.ReconnectBackoffMultiplier = 1
+	if settings.HasSetting(config.ReconnectBackoffMultiplier) {
+		multiplier, err := settings.FloatSetting(config.ReconnectBackoffMultiplier)
+		if err != nil {
+			return err
+		}
+
+		if multiplier < 1 {
+			return errors.New("ReconnectBackoffMultiplier must be greater than or equal to one")
+		}
+		session.ReconnectBackoffMultiplier = multiplier
+	}
+
+	session.ReconnectMaxInterval = 0
+	if settings.HasSetting(config.ReconnectMaxInterval) {
+		interval, err := settings.DurationSetting(config.ReconnectMaxInterval)
+		if err != nil {
+			intervalInt, err := settings.IntSetting(config.ReconnectMaxInterval)
+			if err != nil {
+				return err
+			}
+
+			interval = time.Duration(intervalInt) * time.Second
+		}
+
+		if interval < session.ReconnectInterval {
+			return errors.New("ReconnectMaxInterval must not be less than ReconnectInterval")
+		}
+		session.ReconnectMaxInterval = interval
+	}
+
+	session.ReconnectJitter = 0
+	if settings.HasSetting(config.ReconnectJitter) {
+		jitter, err := settings.FloatSetting(config.ReconnectJitter)
+		if err != nil {
+			return err
+		}
+
+		if jitter < 0 || jitter > 1 {
+			return errors.New("ReconnectJitter must be between zero and one")
+		}
+		session.ReconnectJitter = jitter
+	}
+
+	session.ReconnectResetOnLogon = true
+	if settings.HasSetting(config.ReconnectResetOnLogon) {
+		resetOnLogon, err := settings.BoolSetting(config.ReconnectResetOnLogon)
+		if err != nil {
+			return err
+		}
+		session.ReconnectResetOnLogon = resetOnLogon
+	}
+
 	session.LogoutTimeout = 2 * time.Second
 	if settings.HasSetting(config.LogoutTimeout) {
 		timeout, err := settings.DurationSetting(config.LogoutTimeout)
@@ -499,16 +775,36 @@ func (f sessionFactory) buildInitiatorSettings(session *session, settings *Sessi
 
 func (f sessionFactory) configureSocketConnectAddress(session *session, settings *SessionSettings) (err error) {
 	session.SocketConnectAddress = []string{}
+	session.SocketConnectServerName = []string{}
 
-	var socketConnectHost, socketConnectPort string
+	session.FailoverThreshold = 1
+	if settings.HasSetting(config.SocketConnectFailoverThreshold) {
+		if session.FailoverThreshold, err = settings.IntSetting(config.SocketConnectFailoverThreshold); err != nil {
+			return
+		}
+
+		if session.FailoverThreshold <= 0 {
+			return errors.New("SocketConnectFailoverThreshold must be greater than zero")
+		}
+	}
+
+	if settings.HasSetting(config.SocketConnectFailbackInterval) {
+		if session.FailbackInterval, err = settings.DurationSetting(config.SocketConnectFailbackInterval); err != nil {
+			return
+		}
+	}
+
+	var socketConnectHost, socketConnectPort, serverName string
 	for i := 0; ; {
 
 		hostConfig := config.SocketConnectHost
 		portConfig := config.SocketConnectPort
+		serverNameConfig := config.SocketServerName
 
 		if i > 0 {
 			hostConfig = hostConfig + strconv.Itoa(i)
 			portConfig = portConfig + strconv.Itoa(i)
+			serverNameConfig = serverNameConfig + strconv.Itoa(i)
 
 			if !(settings.HasSetting(hostConfig) || settings.HasSetting(portConfig)) {
 				return
@@ -523,7 +819,16 @@ func (f sessionFactory) configureSocketConnectAddress(session *session, settings
 			return
 		}
 
+		// SocketServerName without a suffix applies to all hosts and is handled by loadTLSConfig.
+		serverName = ""
+		if i > 0 && settings.HasSetting(serverNameConfig) {
+			if serverName, err = settings.Setting(serverNameConfig); err != nil {
+				return
+			}
+		}
+
 		session.SocketConnectAddress = append(session.SocketConnectAddress, net.JoinHostPort(socketConnectHost, socketConnectPort))
+		session.SocketConnectServerName = append(session.SocketConnectServerName, serverName)
 		i++
 	}
 }
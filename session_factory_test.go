@@ -16,6 +16,8 @@
 package quickfix
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -688,6 +690,80 @@ func (s *SessionFactorySuite) TestConfigureSocketConnectAddressMulti() {
 	s.NotNil(err, "must have both host and port to be valid")
 }
 
+func (s *SessionFactorySuite) TestConfigureSocketConnectFailover() {
+	session := new(session)
+	s.SessionSettings.Set(config.SocketConnectHost, "primary.example.com")
+	s.SessionSettings.Set(config.SocketConnectPort, "3000")
+	s.SessionSettings.Set(config.SocketConnectHost+"1", "127.0.0.2")
+	s.SessionSettings.Set(config.SocketConnectPort+"1", "4000")
+	s.SessionSettings.Set(config.SocketServerName+"1", "backup.example.com")
+
+	s.Require().Nil(s.configureSocketConnectAddress(session, s.SessionSettings))
+	s.Equal([]string{"", "backup.example.com"}, session.SocketConnectServerName)
+	s.Equal(1, session.FailoverThreshold)
+	s.Zero(session.FailbackInterval)
+
+	s.SessionSettings.Set(config.SocketConnectFailoverThreshold, "3")
+	s.SessionSettings.Set(config.SocketConnectFailbackInterval, "5m")
+	s.Require().Nil(s.configureSocketConnectAddress(session, s.SessionSettings))
+	s.Equal(3, session.FailoverThreshold)
+	s.Equal(5*time.Minute, session.FailbackInterval)
+
+	s.SessionSettings.Set(config.SocketConnectFailoverThreshold, "0")
+	s.NotNil(s.configureSocketConnectAddress(session, s.SessionSettings))
+
+	s.SessionSettings.Set(config.SocketConnectFailoverThreshold, "1")
+	s.SessionSettings.Set(config.SocketConnectFailbackInterval, "soon")
+	s.NotNil(s.configureSocketConnectAddress(session, s.SessionSettings))
+}
+
+func (s *SessionFactorySuite) TestNewSessionReconnectBackoff() {
+	s.sessionFactory.BuildInitiators = true
+	s.SessionSettings.Set(config.HeartBtInt, "34")
+	s.SessionSettings.Set(config.SocketConnectHost, "127.0.0.1")
+	s.SessionSettings.Set(config.SocketConnectPort, "5000")
+
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(1.0, session.ReconnectBackoffMultiplier)
+	s.Zero(session.ReconnectMaxInterval)
+	s.Zero(session.ReconnectJitter)
+	s.True(session.ReconnectResetOnLogon)
+
+	s.SessionSettings.Set(config.ReconnectBackoffMultiplier, "1.5")
+	s.SessionSettings.Set(config.ReconnectMaxInterval, "300")
+	s.SessionSettings.Set(config.ReconnectJitter, "0.25")
+	s.SessionSettings.Set(config.ReconnectResetOnLogon, "N")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(1.5, session.ReconnectBackoffMultiplier)
+	s.Equal(300*time.Second, session.ReconnectMaxInterval)
+	s.Equal(0.25, session.ReconnectJitter)
+	s.False(session.ReconnectResetOnLogon)
+
+	s.SessionSettings.Set(config.ReconnectMaxInterval, "5m")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(5*time.Minute, session.ReconnectMaxInterval)
+
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.ReconnectBackoffMultiplier, "0.5"},
+		{config.ReconnectBackoffMultiplier, "fast"},
+		{config.ReconnectMaxInterval, "10"},
+		{config.ReconnectJitter, "1.5"},
+		{config.ReconnectResetOnLogon, "maybe"},
+	}
+
+	for _, test := range tests {
+		settings := s.SessionSettings.clone()
+		settings.Set(test.setting, test.value)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, settings, s.LogFactory, s.App)
+		s.NotNil(err, test.setting+"="+test.value)
+	}
+}
+
 func (s *SessionFactorySuite) TestNewSessionTimestampPrecision() {
 	s.SessionSettings.Set(config.TimeStampPrecision, "blah")
 
@@ -748,3 +824,155 @@ func (s *SessionFactorySuite) TestPersistMessages() {
 		s.Equal(test.expected, session.DisableMessagePersist)
 	}
 }
+
+func (s *SessionFactorySuite) TestMessageStoreTimeouts() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Zero(session.StoreTimeout)
+	s.Zero(session.StoreResendTimeout)
+
+	s.SessionSettings.Set(config.MessageStoreTimeout, "500ms")
+	s.SessionSettings.Set(config.MessageStoreResendTimeout, "30s")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(500*time.Millisecond, session.StoreTimeout)
+	s.Equal(30*time.Second, session.StoreResendTimeout)
+
+	for _, setting := range []string{config.MessageStoreTimeout, config.MessageStoreResendTimeout} {
+		for _, value := range []string{"0s", "-1s", "5"} {
+			s.SetupTest()
+			s.SessionSettings.Set(setting, value)
+			_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+			s.NotNil(err, setting+"="+value)
+		}
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionThrottle() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.NotNil(session.throttle)
+	s.Equal(throttleSettings{interval: time.Second, queueLimit: defaultThrottleQueueLimit}, session.throttleSettings)
+
+	s.SessionSettings.Set(config.ThrottleMessages, "50")
+	s.SessionSettings.Set(config.ThrottleInterval, "500ms")
+	s.SessionSettings.Set(config.ThrottleMsgTypes, "D, F,G")
+	s.SessionSettings.Set(config.ThrottlePolicy, "QUEUE")
+	s.SessionSettings.Set(config.ThrottleQueueLimit, "100")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(throttleSettings{
+		messages:   50,
+		interval:   500 * time.Millisecond,
+		msgTypes:   []string{"D", "F", "G"},
+		policy:     throttleQueue,
+		queueLimit: 100,
+	}, session.throttleSettings)
+
+	s.SessionSettings.Set(config.ThrottleInterval, "2")
+	s.SessionSettings.Set(config.ThrottlePolicy, "BLOCK")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(2*time.Second, session.throttleSettings.interval)
+	s.Equal(throttleBlock, session.throttleSettings.policy)
+
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.ThrottleMessages, "0"},
+		{config.ThrottleMessages, "many"},
+		{config.ThrottleInterval, "0"},
+		{config.ThrottleMsgTypes, "D,,F"},
+		{config.ThrottlePolicy, "DROP"},
+		{config.ThrottleQueueLimit, "0"},
+	}
+
+	for _, test := range tests {
+		settings := s.SessionSettings.clone()
+		settings.Set(test.setting, test.value)
+		_, err = s.newSession(s.SessionID, s.MessageStoreFactory, settings, s.LogFactory, s.App)
+		s.NotNil(err, test.setting+"="+test.value)
+	}
+}
+
+func (s *SessionFactorySuite) TestNewSessionParserSettings() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Zero(session.MaxMessageSize)
+	s.False(session.ResyncOnCorruptMessage)
+
+	s.SessionSettings.Set(config.MaxMessageSize, "65536")
+	s.SessionSettings.Set(config.ResyncOnCorruptMessage, "Y")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(65536, session.MaxMessageSize)
+	s.True(session.ResyncOnCorruptMessage)
+
+	s.SessionSettings.Set(config.MaxMessageSize, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionWriterSettings() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(defaultWriteBufferSize, session.WriteBufferSize)
+	s.False(session.FlushImmediately)
+	s.Zero(session.FlushInterval)
+
+	s.SessionSettings.Set(config.SocketWriteBufferSize, "65536")
+	s.SessionSettings.Set(config.SocketFlushPolicy, "IMMEDIATE")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(65536, session.WriteBufferSize)
+	s.True(session.FlushImmediately)
+	s.Zero(session.FlushInterval)
+
+	s.SessionSettings.Set(config.SocketFlushPolicy, "INTERVAL")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.False(session.FlushImmediately)
+	s.Equal(defaultFlushInterval, session.FlushInterval)
+
+	s.SessionSettings.Set(config.SocketFlushInterval, "250us")
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Equal(250*time.Microsecond, session.FlushInterval)
+
+	s.SessionSettings.Set(config.SocketFlushInterval, "0s")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.SocketFlushPolicy, "NEVER")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+
+	s.SessionSettings.Set(config.SocketFlushPolicy, "IDLE")
+	s.SessionSettings.Set(config.SocketWriteBufferSize, "0")
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
+
+func (s *SessionFactorySuite) TestNewSessionAuthenticator() {
+	session, err := s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Nil(session.authenticator)
+
+	path := filepath.Join(s.T().TempDir(), "credentials")
+	s.Require().Nil(os.WriteFile(path, []byte("alice:secret\n"), 0600))
+	s.SessionSettings.Set(config.LogonCredentialsFile, path)
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.IsType(&credentialsFile{}, session.authenticator)
+
+	auth := &mockAuthenticator{}
+	s.sessionFactory.authenticator = auth
+	session, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.Require().Nil(err)
+	s.Same(auth, session.authenticator)
+
+	s.sessionFactory.authenticator = nil
+	s.SessionSettings.Set(config.LogonCredentialsFile, filepath.Join(s.T().TempDir(), "missing"))
+	_, err = s.newSession(s.SessionID, s.MessageStoreFactory, s.SessionSettings, s.LogFactory, s.App)
+	s.NotNil(err)
+}
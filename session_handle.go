@@ -0,0 +1,192 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// sessionStatus holds the runtime details of a session that are published for SessionHandle.
+// It is written by the session goroutine and may be read from any goroutine.
+type sessionStatus struct {
+	sync.RWMutex
+	state        sessionState
+	heartBtInt   time.Duration
+	remoteAddr   net.Addr
+	endpoint     string
+	lastSent     time.Time
+	lastReceived time.Time
+	lastLogon    time.Time
+
+	// Closed when the session goroutine exits, nil if it is not running.
+	running chan struct{}
+
+	// Inbound queue of the current connection, nil if not connected.
+	messageIn <-chan fixIn
+}
+
+func (st *sessionStatus) setRunning(running chan struct{}) {
+	st.Lock()
+	defer st.Unlock()
+	st.running = running
+}
+
+func (st *sessionStatus) runningChan() chan struct{} {
+	st.RLock()
+	defer st.RUnlock()
+	return st.running
+}
+
+func (st *sessionStatus) setState(state sessionState, heartBtInt time.Duration) {
+	st.Lock()
+	defer st.Unlock()
+	st.state = state
+	st.heartBtInt = heartBtInt
+}
+
+func (st *sessionStatus) setRemoteAddr(addr net.Addr, endpoint string) {
+	st.Lock()
+	defer st.Unlock()
+	st.remoteAddr = addr
+	st.endpoint = endpoint
+}
+
+func (st *sessionStatus) setMessageIn(messageIn <-chan fixIn) {
+	st.Lock()
+	defer st.Unlock()
+	st.messageIn = messageIn
+}
+
+func (st *sessionStatus) setLastSent(t time.Time) {
+	st.Lock()
+	defer st.Unlock()
+	st.lastSent = t
+}
+
+func (st *sessionStatus) setLastReceived(t time.Time) {
+	st.Lock()
+	defer st.Unlock()
+	st.lastReceived = t
+}
+
+func (st *sessionStatus) setLastLogon(t time.Time) {
+	st.Lock()
+	defer st.Unlock()
+	st.lastLogon = t
+}
+
+func (st *sessionStatus) lastLogonTime() time.Time {
+	st.RLock()
+	defer st.RUnlock()
+	return st.lastLogon
+}
+
+// SessionHandle gives read-only access to the runtime state of a session.
+// A SessionHandle is obtained from a Registry and is safe for concurrent use.
+type SessionHandle struct {
+	s *session
+}
+
+// SessionID returns the id of the session.
+func (h *SessionHandle) SessionID() SessionID {
+	return h.s.sessionID
+}
+
+func (h *SessionHandle) currentState() sessionState {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+
+	if h.s.status.state == nil {
+		return latentState{}
+	}
+	return h.s.status.state
+}
+
+// State returns the name of the current session state, e.g. "In Session", "Resend" or "Logout State".
+func (h *SessionHandle) State() string {
+	return h.currentState().String()
+}
+
+// IsLoggedOn returns true if the session is connected and logged on.
+func (h *SessionHandle) IsLoggedOn() bool {
+	return h.currentState().IsLoggedOn()
+}
+
+// IsConnected returns true if the session has a connection to the counterparty.
+func (h *SessionHandle) IsConnected() bool {
+	return h.currentState().IsConnected()
+}
+
+// IsSessionTime returns true if the session is within its configured session time.
+func (h *SessionHandle) IsSessionTime() bool {
+	return h.currentState().IsSessionTime()
+}
+
+// RemoteAddr returns the address of the counterparty, or nil if the session is not connected.
+func (h *SessionHandle) RemoteAddr() net.Addr {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return h.s.status.remoteAddr
+}
+
+// Endpoint returns the SocketConnectHost and SocketConnectPort an initiator session is connected to,
+// or an empty string if the session is not connected or is an acceptor session.
+func (h *SessionHandle) Endpoint() string {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return h.s.status.endpoint
+}
+
+// LastSentTime returns the time the last message was handed to the connection, or the zero time if none was sent.
+func (h *SessionHandle) LastSentTime() time.Time {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return h.s.status.lastSent
+}
+
+// LastReceivedTime returns the time the last message was received from the counterparty, or the zero time if none was received.
+func (h *SessionHandle) LastReceivedTime() time.Time {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return h.s.status.lastReceived
+}
+
+// HeartBtInt returns the heartbeat interval in effect for the session.
+func (h *SessionHandle) HeartBtInt() time.Duration {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return h.s.status.heartBtInt
+}
+
+// QueueDepth returns the number of messages waiting in the session's send queue,
+// including messages held by the outbound throttle. See config.OutboundQueueLimit.
+func (h *SessionHandle) QueueDepth() int {
+	depth := int(h.s.toSendDepth.Load())
+	if h.s.throttle != nil {
+		depth += h.s.throttle.queued()
+	}
+	return depth
+}
+
+// InboundQueueDepth returns the number of messages read from the connection and waiting to be processed by the session,
+// 0 if the session is not connected. See config.InboundQueueLimit.
+func (h *SessionHandle) InboundQueueDepth() int {
+	h.s.status.RLock()
+	defer h.s.status.RUnlock()
+	return len(h.s.status.messageIn)
+}
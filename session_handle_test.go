@@ -0,0 +1,118 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionHandleTestSuite struct {
+	SessionSuiteRig
+	registry *Registry
+	handle   *SessionHandle
+}
+
+func TestSessionHandleTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionHandleTestSuite))
+}
+
+func (s *SessionHandleTestSuite) SetupTest() {
+	s.Init()
+	s.registry = NewRegistry()
+	s.Require().Nil(s.registry.registerSession(s.session))
+
+	var err error
+	s.handle, err = s.registry.GetSession(s.sessionID)
+	s.Require().Nil(err)
+}
+
+func (s *SessionHandleTestSuite) TestUnknownSession() {
+	_, err := s.registry.GetSession(SessionID{BeginString: "FIX.4.2", TargetCompID: "X", SenderCompID: "Y"})
+	s.Equal(errUnknownSession, err)
+}
+
+func (s *SessionHandleTestSuite) TestSessions() {
+	s.Equal([]SessionID{s.sessionID}, s.registry.Sessions())
+}
+
+func (s *SessionHandleTestSuite) TestNotStarted() {
+	s.Equal(s.sessionID, s.handle.SessionID())
+	s.Equal(latentState{}.String(), s.handle.State())
+	s.False(s.handle.IsLoggedOn())
+	s.False(s.handle.IsConnected())
+	s.Nil(s.handle.RemoteAddr())
+	s.True(s.handle.LastSentTime().IsZero())
+	s.True(s.handle.LastReceivedTime().IsZero())
+	s.Zero(s.handle.QueueDepth())
+}
+
+func (s *SessionHandleTestSuite) TestStateTransitions() {
+	s.session.HeartBtInt = 30 * time.Second
+	s.session.setState(s.session, logonState{})
+	s.Equal(logonState{}.String(), s.handle.State())
+	s.True(s.handle.IsConnected())
+	s.False(s.handle.IsLoggedOn())
+
+	s.session.setState(s.session, inSession{})
+	s.Equal(inSession{}.String(), s.handle.State())
+	s.True(s.handle.IsLoggedOn())
+	s.Equal(30*time.Second, s.handle.HeartBtInt())
+
+	s.session.setState(s.session, resendState{})
+	s.Equal(resendState{}.String(), s.handle.State())
+	s.True(s.handle.IsLoggedOn())
+}
+
+func (s *SessionHandleTestSuite) TestRemoteAddr() {
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5001}
+	s.session.State = latentState{}
+	s.session.onAdmin(connect{messageOut: s.Receiver.sendChannel, remoteAddr: addr, endpoint: "localhost:5001"})
+	s.Equal(addr, s.handle.RemoteAddr())
+	s.Equal("localhost:5001", s.handle.Endpoint())
+
+	s.session.onDisconnect()
+	s.Nil(s.handle.RemoteAddr())
+	s.Empty(s.handle.Endpoint())
+}
+
+func (s *SessionHandleTestSuite) TestQueueDepthAndLastSent() {
+	s.session.State = latentState{}
+	s.MockApp.On("ToApp").Return(nil)
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Require().Nil(s.session.queueForSend(s.NewOrderSingle()))
+	s.Equal(2, s.handle.QueueDepth())
+	s.True(s.handle.LastSentTime().IsZero())
+
+	s.session.State = inSession{}
+	s.session.SendAppMessages(s.session)
+	s.Zero(s.handle.QueueDepth())
+	s.False(s.handle.LastSentTime().IsZero())
+}
+
+func (s *SessionHandleTestSuite) TestLastReceived() {
+	s.session.State = inSession{}
+	s.MockApp.On("FromAdmin").Return(nil)
+
+	receiveTime := time.Now()
+	s.session.Incoming(s.session, fixIn{bytes: bytes.NewBuffer(s.Heartbeat().build()), receiveTime: receiveTime})
+	s.MockApp.AssertExpectations(s.T())
+	s.Equal(receiveTime, s.handle.LastReceivedTime())
+}
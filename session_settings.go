@@ -135,6 +135,22 @@ func (s *SessionSettings) DurationSetting(setting string) (time.Duration, error)
 	return 0, IncorrectFormatForSetting{Setting: setting, Value: rawVal, Err: err}
 }
 
+// FloatSetting returns the requested setting parsed as a float64.
+// Returns an error if the setting is not set or cannot be parsed as a float64.
+func (s *SessionSettings) FloatSetting(setting string) (float64, error) {
+	rawVal, err := s.RawSetting(setting)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := strconv.ParseFloat(string(rawVal), 64)
+	if err != nil {
+		return 0, IncorrectFormatForSetting{Setting: setting, Value: rawVal, Err: err}
+	}
+
+	return val, nil
+}
+
 // BoolSetting returns the requested setting parsed as a boolean.  Returns an error if the setting is not set or cannot be parsed as a bool.
 func (s SessionSettings) BoolSetting(setting string) (bool, error) {
 	rawVal, err := s.RawSetting(setting)
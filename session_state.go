@@ -33,6 +33,7 @@ func (sm *stateMachine) Start(s *session) {
 	sm.stopped = false
 
 	sm.State = latentState{}
+	s.status.setState(sm.State, s.HeartBtInt)
 	sm.CheckSessionTime(s, time.Now())
 }
 
@@ -79,6 +80,7 @@ func (sm *stateMachine) Stopped() bool {
 
 func (sm *stateMachine) Disconnected(session *session) {
 	if sm.IsConnected() {
+		session.setDisconnectReason("Connection closed")
 		sm.setState(session, latentState{})
 	}
 }
@@ -89,14 +91,27 @@ func (sm *stateMachine) Incoming(session *session, m fixIn) {
 		return
 	}
 
+	if m.err != nil {
+		session.log.OnEventf("Closing connection: %v", m.err)
+		session.setDisconnectReason(m.err.Error())
+		sm.setState(session, latentState{})
+		return
+	}
+
 	session.log.OnIncoming(m.bytes.Bytes())
+	session.status.setLastReceived(m.receiveTime)
 
 	msg := NewMessage()
 	if err := ParseMessageWithDataDictionary(msg, m.bytes, session.transportDataDictionary, session.appDataDictionary); err != nil {
 		session.log.OnEventf("Msg Parse Error: %v, %q", err.Error(), m.bytes)
 	} else {
 		msg.ReceiveTime = m.receiveTime
-		sm.fixMsgIn(session, msg)
+		session.recordIncoming(msg)
+		if m.breach != nil {
+			sm.setState(session, session.inboundLimitExceeded(msg, m.breach))
+		} else {
+			sm.fixMsgIn(session, msg)
+		}
 	}
 
 	session.peerTimer.Reset(time.Duration(float64(1.2) * float64(session.HeartBtInt)))
@@ -110,12 +125,22 @@ func (sm *stateMachine) SendAppMessages(session *session) {
 	sm.CheckSessionTime(session, time.Now())
 
 	session.sendMutex.Lock()
-	defer session.sendMutex.Unlock()
+	disconnectErr := session.disconnectErr
+	session.disconnectErr = nil
+	disconnect := disconnectErr != nil && sm.IsConnected()
+	if !disconnect {
+		if session.IsLoggedOn() {
+			session.sendQueued(false)
+		} else {
+			session.dropQueued()
+		}
+	}
+	session.sendMutex.Unlock()
 
-	if session.IsLoggedOn() {
-		session.sendQueued(false)
-	} else {
-		session.dropQueued()
+	if disconnect {
+		session.log.OnEventf("Closing connection: %v", disconnectErr)
+		session.setDisconnectReason(disconnectErr.Error())
+		sm.setState(session, latentState{})
 	}
 }
 
@@ -128,6 +153,7 @@ func (sm *stateMachine) CheckSessionTime(session *session, now time.Time) {
 	if !session.SessionTime.IsInRange(now) {
 		if sm.IsSessionTime() {
 			session.log.OnEvent("Not in session")
+			session.setDisconnectReason("Not in session")
 		}
 
 		sm.State.ShutdownNow(session)
@@ -147,6 +173,7 @@ func (sm *stateMachine) CheckSessionTime(session *session, now time.Time) {
 
 	if !session.SessionTime.IsInSameRange(session.store.CreationTime(), now) {
 		session.log.OnEvent("Session reset")
+		session.setDisconnectReason("Session reset")
 		sm.State.ShutdownNow(session)
 		if err := session.dropAndReset(); err != nil {
 			session.logError(err)
@@ -159,7 +186,7 @@ func (sm *stateMachine) CheckResetTime(session *session, now time.Time) {
 	if session.EnableResetSeqTime {
 		ts := internal.NewTimeOfDay(now.Clock())
 		if session.ResetSeqTime == ts {
-			session.sendLogonInReplyTo(true, nil)
+			session.sendLogonInReplyTo(true, nil, nil)
 		}
 	}
 }
@@ -176,7 +203,22 @@ func (sm *stateMachine) setState(session *session, nextState sessionState) {
 		}
 	}
 
+	prevState := sm.State
 	sm.State = nextState
+	session.status.setState(nextState, session.HeartBtInt)
+
+	if prevState == nil || prevState.String() != nextState.String() {
+		var fromState string
+		if prevState != nil {
+			fromState = prevState.String()
+		}
+		session.emit(SessionEvent{Type: SessionStateChanged, FromState: fromState, ToState: nextState.String()})
+	}
+
+	if nextState.IsLoggedOn() && (prevState == nil || !prevState.IsLoggedOn()) {
+		session.status.setLastLogon(time.Now())
+		session.emit(SessionEvent{Type: SessionLoggedOn})
+	}
 }
 
 func (sm *stateMachine) notifyInSessionTime() {
@@ -219,6 +261,7 @@ func (sm *stateMachine) IsSessionTime() bool {
 
 func handleStateError(s *session, err error) sessionState {
 	s.logError(err)
+	s.setDisconnectReason(err.Error())
 	return latentState{}
 }
 
@@ -272,6 +315,7 @@ func (loggedOn) ShutdownNow(s *session) {
 	if err := s.sendLogout(""); err != nil {
 		s.logError(err)
 	}
+	s.emit(SessionEvent{Type: SessionLoggedOut})
 }
 
 func (loggedOn) Stop(s *session) (nextState sessionState) {
@@ -17,6 +17,9 @@ package quickfix
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -544,6 +547,16 @@ func (s *SessionSuite) TestIncomingNotInSessionTime() {
 	}
 }
 
+func (s *SessionSuite) TestIncomingMessageTooLarge() {
+	s.session.State = inSession{}
+	s.MockApp.On("OnLogout")
+
+	s.session.Incoming(s.session, fixIn{err: MessageTooLargeError{Size: 2048, MaxMessageSize: 1024}})
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Disconnected()
+}
+
 func (s *SessionSuite) TestSendAppMessagesNotInSessionTime() {
 	var tests = []struct {
 		before           sessionState
@@ -667,6 +680,35 @@ func (s *SessionSuite) TestOnAdminConnectInitiateLogon() {
 	s.NextSenderMsgSeqNum(3)
 }
 
+func (s *SessionSuite) TestOnAdminInboundQueueFullDisconnect() {
+	s.session.State = latentState{}
+	s.session.queueSettings = queueSettings{inboundLimit: 1, inboundPolicy: queueDisconnect}
+	msgIn := make(chan fixIn, 1)
+	parser := newParser(strings.NewReader(strings.Repeat("8=FIX.4.0\x019=5\x01blah\x0110=103\x01", 2)))
+	s.session.onAdmin(connect{messageOut: s.Receiver.sendChannel, messageIn: msgIn, parser: parser})
+	s.session.State = inSession{}
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+	s.session.admin = make(chan interface{})
+	go readLoop(parser, msgIn, nullLog{})
+
+	// The connection is closed ahead of the queued message.
+	req := <-s.session.admin
+	s.Len(msgIn, 1)
+
+	// An error from a connection since replaced is ignored.
+	s.session.onAdmin(connectionErrorReq{messageIn: make(chan fixIn), err: errors.New("stale")})
+	s.State(inSession{})
+
+	s.MockApp.On("OnLogout")
+	s.session.onAdmin(req)
+	s.MockApp.AssertExpectations(s.T())
+	s.State(latentState{})
+	s.Disconnected()
+}
+
 func (s *SessionSuite) TestInitiateLogonResetSeqNumFlag() {
 	adminMsg := connect{
 		messageOut: s.Receiver.sendChannel,
@@ -843,6 +885,185 @@ func (suite *SessionSendTestSuite) TestQueueForSendDoNotSendAppMessage() {
 	suite.NextSenderMsgSeqNum(2)
 }
 
+func (suite *SessionSendTestSuite) TestQueueForSendThrottledAppMessage() {
+	suite.session.throttle = newThrottle(throttleSettings{messages: 1, interval: time.Hour}, suite.releaseThrottled)
+	suite.MockApp.On("ToApp").Return(nil)
+	require.Nil(suite.T(), suite.queueForSend(suite.NewOrderSingle()))
+	suite.NextSenderMsgSeqNum(2)
+
+	suite.Equal(ErrThrottled, suite.queueForSend(suite.NewOrderSingle()))
+	suite.NoMessagePersisted(2)
+	suite.NextSenderMsgSeqNum(2)
+
+	suite.MockApp.On("ToAdmin")
+	require.Nil(suite.T(), suite.queueForSend(suite.Heartbeat()))
+	suite.MessagePersisted(suite.MockApp.lastToAdmin)
+	suite.NextSenderMsgSeqNum(3)
+}
+
+func (suite *SessionSendTestSuite) TestSendToTargetThrottledQueueCopiesMessage() {
+	th := newThrottle(throttleSettings{messages: 1, interval: time.Hour, policy: throttleQueue, queueLimit: 1}, suite.releaseThrottled)
+	defer th.stop()
+	suite.session.throttle = th
+	registry := NewRegistry()
+	suite.Require().Nil(registry.registerSession(suite.session))
+	suite.MockApp.On("ToApp").Return(nil)
+
+	msg := suite.NewOrderSingle()
+	msg.Body.SetField(Tag(11), FIXString("first"))
+	suite.Require().Nil(registry.SendToTarget(msg, suite.session.sessionID))
+	suite.NextSenderMsgSeqNum(2)
+
+	// The queued message is not changed by reusing msg after it is sent.
+	msg.Body.SetField(Tag(11), FIXString("second"))
+	suite.Require().Nil(registry.SendToTarget(msg, suite.session.sessionID))
+	suite.NoMessagePersisted(2)
+	msg.Body.SetField(Tag(11), FIXString("changed"))
+
+	th.now = func() time.Time { return time.Now().Add(time.Hour) }
+	th.releaseDue()
+	suite.NextSenderMsgSeqNum(3)
+	suite.MessagePersisted(suite.MockApp.lastToApp)
+	suite.FieldEquals(Tag(11), "second", suite.MockApp.lastToApp.Body)
+}
+
+func (suite *SessionSendTestSuite) TestReleaseThrottledOutboundQueueLimit() {
+	suite.session.queueSettings = queueSettings{outboundLimit: 1, outboundPolicy: queueReject}
+	suite.MockApp.On("ToApp").Return(nil)
+	suite.releaseThrottled(suite.NewOrderSingle())
+	suite.NextSenderMsgSeqNum(2)
+
+	// Released messages do not bypass the limit of the send queue.
+	suite.releaseThrottled(suite.NewOrderSingle())
+	suite.NoMessagePersisted(2)
+	suite.NextSenderMsgSeqNum(2)
+	suite.Equal(1, (&SessionHandle{s: suite.session}).QueueDepth())
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendOutboundQueueReject() {
+	suite.session.queueSettings = queueSettings{outboundLimit: 1, outboundPolicy: queueReject}
+	suite.MockApp.On("ToApp").Return(nil)
+	require.Nil(suite.T(), suite.queueForSend(suite.NewOrderSingle()))
+	suite.NextSenderMsgSeqNum(2)
+
+	suite.Equal(QueueFullError{Limit: 1}, suite.queueForSend(suite.NewOrderSingle()))
+	suite.NoMessagePersisted(2)
+	suite.NextSenderMsgSeqNum(2)
+	suite.Equal(1, (&SessionHandle{s: suite.session}).QueueDepth())
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendOutboundQueueBlock() {
+	suite.session.queueSettings = queueSettings{outboundLimit: 1, outboundPolicy: queueBlock}
+	suite.MockApp.On("ToApp").Return(nil)
+	require.Nil(suite.T(), suite.queueForSend(suite.NewOrderSingle()))
+
+	// Without the session goroutine to drain the queue the sender is not blocked.
+	suite.Equal(QueueFullError{Limit: 1}, suite.queueForSend(suite.NewOrderSingle()))
+
+	running := make(chan struct{})
+	suite.session.status.setRunning(running)
+	sent := make(chan error)
+	go func() { sent <- suite.queueForSend(suite.NewOrderSingle()) }()
+
+	select {
+	case err := <-sent:
+		suite.FailNow("sender not blocked", "%v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	suite.SendAppMessages(suite.session)
+	suite.Nil(<-sent)
+	suite.NextSenderMsgSeqNum(3)
+
+	go func() { sent <- suite.queueForSend(suite.NewOrderSingle()) }()
+	time.Sleep(10 * time.Millisecond)
+	suite.session.status.setRunning(nil)
+	suite.session.sendMutex.Lock()
+	suite.session.queueDrained()
+	suite.session.sendMutex.Unlock()
+	suite.Equal(QueueFullError{Limit: 1}, <-sent)
+}
+
+func (suite *SessionSendTestSuite) TestQueueForSendOutboundQueueDisconnect() {
+	suite.session.queueSettings = queueSettings{outboundLimit: 1, outboundPolicy: queueDisconnect}
+	suite.MockApp.On("ToApp").Return(nil)
+	require.Nil(suite.T(), suite.queueForSend(suite.NewOrderSingle()))
+	suite.Equal(QueueFullError{Limit: 1}, suite.queueForSend(suite.NewOrderSingle()))
+
+	suite.MockApp.On("OnLogout")
+	suite.SendAppMessages(suite.session)
+	suite.State(latentState{})
+	suite.Disconnected()
+	suite.Nil(suite.session.disconnectErr)
+}
+
+// stalledStore is a MessageStoreWithContext whose context operations block until their context is done.
+type stalledStore struct {
+	MessageStore
+}
+
+func (stalledStore) IncrNextSenderMsgSeqNumContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) IncrNextTargetMsgSeqNumContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) SetNextSenderMsgSeqNumContext(ctx context.Context, _ int) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) SetNextTargetMsgSeqNumContext(ctx context.Context, _ int) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) SaveMessageContext(ctx context.Context, _ int, _ []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx context.Context, _ int, _ []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stalledStore) IterateMessagesContext(ctx context.Context, _, _ int, _ func([]byte) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (suite *SessionSendTestSuite) TestSendMessageStoreTimeout() {
+	suite.session.store = stalledStore{suite.session.store}
+	suite.session.StoreTimeout = time.Millisecond
+	suite.MockApp.On("ToApp").Return(nil)
+	suite.ErrorIs(suite.send(suite.NewOrderSingle()), context.DeadlineExceeded)
+
+	suite.NextSenderMsgSeqNum(1)
+	suite.NoMessageSent()
+
+	// The session disconnects, as the store may be stalled.
+	suite.MockApp.On("OnLogout")
+	suite.SendAppMessages(suite.session)
+	suite.State(latentState{})
+	suite.Disconnected()
+}
+
+func (suite *SessionSendTestSuite) TestSendMessageStoreWithoutTimeout() {
+	// Without MessageStoreTimeout, the operations without context are used.
+	suite.session.store = stalledStore{suite.session.store}
+	suite.MockApp.On("ToApp").Return(nil)
+	require.Nil(suite.T(), suite.send(suite.NewOrderSingle()))
+
+	suite.MessagePersisted(suite.MockApp.lastToApp)
+	suite.LastToAppMessageSent()
+	suite.NextSenderMsgSeqNum(2)
+}
+
 func (suite *SessionSendTestSuite) TestQueueForSendAdminMessage() {
 	suite.MockApp.On("ToAdmin")
 	require.Nil(suite.T(), suite.queueForSend(suite.Heartbeat()))
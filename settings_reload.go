@@ -0,0 +1,292 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// ReloadReport describes the outcome of reloading Settings into a running Initiator or Acceptor.
+type ReloadReport struct {
+	// Sessions created for session sections that were not configured before.
+	Added []SessionID
+
+	// Sessions stopped and unregistered because their session section was removed.
+	Removed []SessionID
+
+	// Changed settings applied to the running session without dropping its connection.
+	// A changed HeartBtInt is used from the next logon.
+	Applied map[SessionID][]string
+
+	// Changed connection settings, for each session that was restarted to apply them.
+	Restarted map[SessionID][]string
+
+	// Changed settings that are only read when the Initiator or Acceptor, or its log and store factories,
+	// are created. These take effect after a restart of the engine. Log settings such as FileLogPath are listed
+	// unless the reload is given a LogFactory with WithReloadLogFactory, and changes to the default log settings are
+	// always listed, as the global log of the engine is not recreated.
+	RestartRequired []string
+}
+
+// ReloadOption configures how Initiator.Reload and Acceptor.Reload apply the reloaded settings.
+type ReloadOption func(*reloadOptions)
+
+type reloadOptions struct {
+	logFactory LogFactory
+}
+
+// WithReloadLogFactory applies changed log settings, such as FileLogPath, to the running sessions.
+// logFactory must be created from the reloaded settings. The logs of sessions whose log settings changed are
+// recreated with it, and it creates the logs of the sessions added or restarted from then on.
+func WithReloadLogFactory(logFactory LogFactory) ReloadOption {
+	return func(o *reloadOptions) {
+		o.logFactory = logFactory
+	}
+}
+
+func newReloadOptions(opts []ReloadOption) reloadOptions {
+	var o reloadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// connectionSetting returns true if a change to setting requires the session connection to be re-established.
+func connectionSetting(setting string) bool {
+	switch setting {
+	case config.SocketConnectHost, config.SocketConnectPort, config.SocketTimeout,
+		config.ProxyType, config.ProxyHost, config.ProxyPort, config.ProxyUser, config.ProxyPassword, config.ProxyCAFile,
+		config.SocketAcceptPort, config.SocketUseSSL, config.SocketServerName, config.SocketInsecureSkipVerify,
+		config.SocketMinimumTLSVersion, config.SocketPrivateKeyFile, config.SocketCertificateFile, config.SocketCAFile,
+		config.SocketPrivateKeyBytes, config.SocketCertificateBytes, config.SocketCABytes,
+		config.SocketMaximumTLSVersion, config.SocketCipherSuites, config.SocketPrivateKeyPassword,
+		config.SocketPKCS12File, config.SocketPKCS12Password,
+		config.SocketLocalHost, config.SocketLocalPort, config.SocketNodelay, config.SocketSendBufferSize,
+		config.SocketReceiveBufferSize, config.SocketKeepAlivePeriod,
+		config.SocketConnectFailoverThreshold, config.SocketConnectFailbackInterval,
+		config.ReconnectInterval, config.ReconnectBackoffMultiplier, config.ReconnectMaxInterval,
+		config.ReconnectJitter, config.ReconnectResetOnLogon, config.MaxMessageSize, config.ResyncOnCorruptMessage,
+		config.SocketFlushPolicy, config.SocketFlushInterval, config.SocketWriteBufferSize,
+		config.OutboundQueueLimit, config.OutboundQueuePolicy, config.InboundQueueLimit, config.InboundQueuePolicy:
+		return true
+	}
+
+	// Backup hosts, e.g. SocketConnectHost1.
+	return strings.HasPrefix(setting, config.SocketConnectHost) || strings.HasPrefix(setting, config.SocketConnectPort) ||
+		strings.HasPrefix(setting, config.SocketServerName)
+}
+
+// engineSetting returns true if setting is only read when the engine or its store factory is created.
+func engineSetting(setting string) bool {
+	switch setting {
+	case config.SocketAcceptHost, config.UseTCPProxy, config.DynamicSessions, config.DynamicQualifier:
+		return true
+	}
+
+	for _, prefix := range []string{"FileStore", "BoltStore", "SQLStore", "MongoStore", "AdminServer"} {
+		if strings.HasPrefix(setting, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// logSetting returns true if setting is read by a log factory, see WithReloadLogFactory.
+func logSetting(setting string) bool {
+	for _, prefix := range []string{"FileLog", "SQLLog", "MongoLog"} {
+		if strings.HasPrefix(setting, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedSettings returns the sorted names of settings that differ between from and to.
+func changedSettings(from, to *SessionSettings) (changed []string) {
+	for setting, value := range from.settings {
+		if toValue, ok := to.settings[setting]; !ok || !bytes.Equal(value, toValue) {
+			changed = append(changed, setting)
+		}
+	}
+
+	for setting := range to.settings {
+		if _, ok := from.settings[setting]; !ok {
+			changed = append(changed, setting)
+		}
+	}
+
+	sort.Strings(changed)
+	return
+}
+
+// sessionReloader is implemented by Initiator and Acceptor.
+type sessionReloader interface {
+	AddSession(sessionSettings *SessionSettings) (SessionID, error)
+	RemoveSession(sessionID SessionID) error
+
+	// reconfigureSession applies the settings of from, built from settings, to a session and keeps settings as its current settings.
+	reconfigureSession(sessionID SessionID, from *session, settings *SessionSettings) error
+}
+
+// reloadPlan holds the checked changes from the current session settings of an engine to new Settings.
+type reloadPlan struct {
+	report *ReloadReport
+	next   map[SessionID]*SessionSettings
+
+	// Sessions re-configured from the new settings, for the sessions in report.Applied.
+	live map[SessionID]*session
+
+	// Sessions in live whose log is recreated with logFactory.
+	relog      map[SessionID]bool
+	logFactory LogFactory
+}
+
+// planReload diffs the current global and session settings of an engine against settings,
+// and checks that the new settings of added and changed sessions are valid.
+// If check is not nil, it is also called with the new settings of the sessions to be added or restarted,
+// so that a session is not removed by a restart that cannot add it again.
+func planReload(f sessionFactory, currentGlobal *SessionSettings, current map[SessionID]*SessionSettings, settings *Settings,
+	options reloadOptions, check func(*SessionSettings) error) (*reloadPlan, error) {
+	report := &ReloadReport{Applied: make(map[SessionID][]string), Restarted: make(map[SessionID][]string)}
+	next := settings.SessionSettings()
+
+	restartRequired := make(map[string]bool)
+	for _, setting := range changedSettings(currentGlobal, settings.GlobalSettings()) {
+		if engineSetting(setting) || logSetting(setting) {
+			restartRequired[setting] = true
+		}
+	}
+
+	live := make(map[SessionID]*session)
+	relog := make(map[SessionID]bool)
+	for sessionID, nextSettings := range next {
+		currentSettings, ok := current[sessionID]
+		if !ok {
+			if err := f.configureSession(&session{sessionID: sessionID}, nextSettings); err != nil {
+				return nil, err
+			}
+			if check != nil {
+				if err := check(nextSettings); err != nil {
+					return nil, err
+				}
+			}
+			report.Added = append(report.Added, sessionID)
+			continue
+		}
+
+		var applied, restarted []string
+		var logChanged bool
+		for _, setting := range changedSettings(currentSettings, nextSettings) {
+			switch {
+			case engineSetting(setting), logSetting(setting) && options.logFactory == nil:
+				restartRequired[setting] = true
+			case connectionSetting(setting):
+				restarted = append(restarted, setting)
+			default:
+				logChanged = logChanged || logSetting(setting)
+				applied = append(applied, setting)
+			}
+		}
+
+		if len(restarted) == 0 && len(applied) == 0 {
+			continue
+		}
+
+		candidate := &session{sessionID: sessionID}
+		if err := f.configureSession(candidate, nextSettings); err != nil {
+			return nil, err
+		}
+
+		if len(restarted) > 0 {
+			if check != nil {
+				if err := check(nextSettings); err != nil {
+					return nil, err
+				}
+			}
+			report.Restarted[sessionID] = append(restarted, applied...)
+			sort.Strings(report.Restarted[sessionID])
+		} else {
+			live[sessionID] = candidate
+			relog[sessionID] = logChanged
+			report.Applied[sessionID] = applied
+		}
+	}
+
+	for sessionID := range current {
+		if _, ok := next[sessionID]; !ok {
+			report.Removed = append(report.Removed, sessionID)
+		}
+	}
+
+	for setting := range restartRequired {
+		report.RestartRequired = append(report.RestartRequired, setting)
+	}
+	sort.Strings(report.RestartRequired)
+	sortSessionIDs(report.Added)
+	sortSessionIDs(report.Removed)
+
+	return &reloadPlan{report: report, next: next, live: live, relog: relog, logFactory: options.logFactory}, nil
+}
+
+// apply makes the planned changes to the sessions of e.
+func (p *reloadPlan) apply(e sessionReloader) (*ReloadReport, error) {
+	report := p.report
+	for _, sessionID := range report.Removed {
+		if err := e.RemoveSession(sessionID); err != nil {
+			return report, err
+		}
+	}
+
+	for sessionID := range report.Restarted {
+		if err := e.RemoveSession(sessionID); err != nil {
+			return report, err
+		}
+		if _, err := e.AddSession(p.next[sessionID]); err != nil {
+			return report, err
+		}
+	}
+
+	for _, sessionID := range report.Added {
+		if _, err := e.AddSession(p.next[sessionID]); err != nil {
+			return report, err
+		}
+	}
+
+	for sessionID, candidate := range p.live {
+		if p.relog[sessionID] {
+			log, err := p.logFactory.CreateSessionLog(sessionID)
+			if err != nil {
+				return report, err
+			}
+			candidate.log = log
+		}
+
+		if err := e.reconfigureSession(sessionID, candidate, p.next[sessionID]); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func sortSessionIDs(sessionIDs []SessionID) {
+	sort.Slice(sessionIDs, func(i, j int) bool { return sessionIDs[i].String() < sessionIDs[j].String() })
+}
@@ -0,0 +1,313 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+const reloadSettingsTemplate = `
+[DEFAULT]
+SocketConnectHost=127.0.0.1
+SocketConnectPort=5001
+HeartBtInt=30
+FileStorePath=store
+
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET1
+
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET2
+`
+
+func parseReloadSettings(t *testing.T, cfg string) *Settings {
+	settings, err := ParseSettings(strings.NewReader(cfg))
+	require.Nil(t, err)
+	return settings
+}
+
+func TestChangedSettings(t *testing.T) {
+	from := NewSessionSettings()
+	from.Set(config.HeartBtInt, "30")
+	from.Set(config.StartTime, "00:00:00")
+	from.Set(config.SocketConnectHost, "a")
+
+	to := NewSessionSettings()
+	to.Set(config.HeartBtInt, "60")
+	to.Set(config.SocketConnectHost, "a")
+	to.Set(config.EndTime, "00:00:00")
+
+	assert.Equal(t, []string{config.EndTime, config.HeartBtInt, config.StartTime}, changedSettings(from, to))
+	assert.Empty(t, changedSettings(from, from))
+}
+
+func TestReloadSettingClasses(t *testing.T) {
+	assert.True(t, connectionSetting(config.SocketConnectHost))
+	assert.True(t, connectionSetting(config.SocketConnectPort+"1"))
+	assert.True(t, connectionSetting(config.SocketUseSSL))
+	assert.False(t, connectionSetting(config.HeartBtInt))
+
+	assert.True(t, engineSetting(config.FileStorePath))
+	assert.True(t, engineSetting(config.BoltStorePath))
+	assert.True(t, engineSetting(config.AdminServerAddress))
+	assert.False(t, engineSetting(config.SQLLogDriver))
+	assert.False(t, engineSetting(config.PersistMessages))
+	assert.False(t, engineSetting(config.StartTime))
+
+	assert.True(t, logSetting(config.SQLLogDriver))
+	assert.True(t, logSetting(config.FileLogPath))
+	assert.False(t, logSetting(config.FileStorePath))
+}
+
+func TestInitiatorReload(t *testing.T) {
+	registry := NewRegistry()
+	i, err := NewInitiator(EmptyApplication{}, NewMemoryStoreFactory(), parseReloadSettings(t, reloadSettingsTemplate), nullLogFactory{}, WithInitiatorRegistry(registry))
+	require.Nil(t, err)
+
+	target1 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET1"}
+	target2 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET2"}
+	target3 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET3"}
+	original := i.sessions[target2]
+
+	cfg := strings.Replace(reloadSettingsTemplate, "FileStorePath=store", "FileStorePath=other", 1)
+	cfg = strings.Replace(cfg, "TargetCompID=TARGET1", "TargetCompID=TARGET1\nHeartBtInt=60\nValidateFieldsOutOfOrder=N", 1)
+	cfg = strings.Replace(cfg, "TargetCompID=TARGET2", "TargetCompID=TARGET2\nSocketConnectPort=5002", 1)
+	cfg += `
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET3
+`
+
+	report, err := i.Reload(parseReloadSettings(t, cfg))
+	require.Nil(t, err)
+
+	assert.Equal(t, []SessionID{target3}, report.Added)
+	assert.Empty(t, report.Removed)
+	assert.Equal(t, map[SessionID][]string{target1: {config.HeartBtInt, config.ValidateFieldsOutOfOrder}}, report.Applied)
+	assert.Equal(t, map[SessionID][]string{target2: {config.SocketConnectPort}}, report.Restarted)
+	assert.Equal(t, []string{config.FileStorePath}, report.RestartRequired)
+
+	assert.Equal(t, 60*time.Second, i.sessions[target1].HeartBtInt)
+	assert.NotSame(t, original, i.sessions[target2])
+	assert.Equal(t, []string{"127.0.0.1:5002"}, i.sessions[target2].SocketConnectAddress)
+	assert.Len(t, registry.Sessions(), 3)
+
+	report, err = i.Reload(parseReloadSettings(t, reloadSettingsTemplate))
+	require.Nil(t, err)
+	assert.Equal(t, []SessionID{target3}, report.Removed)
+	assert.Len(t, registry.Sessions(), 2)
+	assert.Equal(t, 30*time.Second, i.sessions[target1].HeartBtInt)
+}
+
+func TestInitiatorReloadInvalid(t *testing.T) {
+	i, err := NewInitiator(EmptyApplication{}, NewMemoryStoreFactory(), parseReloadSettings(t, reloadSettingsTemplate), nullLogFactory{}, WithInitiatorRegistry(NewRegistry()))
+	require.Nil(t, err)
+
+	cfg := strings.Replace(reloadSettingsTemplate, "TargetCompID=TARGET1", "TargetCompID=TARGET1\nHeartBtInt=60", 1)
+	cfg = strings.Replace(cfg, "TargetCompID=TARGET2", "TargetCompID=TARGET2\nMaxLatency=-1", 1)
+
+	_, err = i.Reload(parseReloadSettings(t, cfg))
+	require.NotNil(t, err)
+
+	target1 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET1"}
+	assert.Equal(t, 30*time.Second, i.sessions[target1].HeartBtInt)
+}
+
+// closableLog records whether it was closed.
+type closableLog struct {
+	nullLog
+	closed bool
+}
+
+func (l *closableLog) Close() error {
+	l.closed = true
+	return nil
+}
+
+type closableLogFactory map[SessionID]*closableLog
+
+func (f closableLogFactory) Create() (Log, error) {
+	return nullLog{}, nil
+}
+
+func (f closableLogFactory) CreateSessionLog(sessionID SessionID) (Log, error) {
+	f[sessionID] = &closableLog{}
+	return f[sessionID], nil
+}
+
+func TestInitiatorReloadLogSettings(t *testing.T) {
+	logs := closableLogFactory{}
+	i, err := NewInitiator(EmptyApplication{}, NewMemoryStoreFactory(), parseReloadSettings(t, reloadSettingsTemplate), logs, WithInitiatorRegistry(NewRegistry()))
+	require.Nil(t, err)
+
+	target1 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET1"}
+	cfg := strings.Replace(reloadSettingsTemplate, "TargetCompID=TARGET1", "TargetCompID=TARGET1\nFileLogPath=other", 1)
+
+	// Without a LogFactory created from the new settings the log cannot be recreated.
+	report, err := i.Reload(parseReloadSettings(t, cfg))
+	require.Nil(t, err)
+	assert.Empty(t, report.Applied)
+	assert.Equal(t, []string{config.FileLogPath}, report.RestartRequired)
+
+	reloadedLogs := closableLogFactory{}
+	report, err = i.Reload(parseReloadSettings(t, cfg), WithReloadLogFactory(reloadedLogs))
+	require.Nil(t, err)
+	assert.Equal(t, map[SessionID][]string{target1: {config.FileLogPath}}, report.Applied)
+	assert.Empty(t, report.RestartRequired)
+
+	assert.True(t, logs[target1].closed)
+	assert.Same(t, reloadedLogs[target1], i.sessions[target1].log.(*swappableLog).log)
+	assert.Len(t, reloadedLogs, 1, "only the log of the changed session is recreated")
+
+	// Sessions added from then on log with the new LogFactory.
+	cfg += `
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET3
+`
+	_, err = i.Reload(parseReloadSettings(t, cfg), WithReloadLogFactory(reloadedLogs))
+	require.Nil(t, err)
+	assert.Len(t, reloadedLogs, 2)
+}
+
+type ReloadSessionTestSuite struct {
+	SessionSuiteRig
+}
+
+func TestReloadSessionTestSuite(t *testing.T) {
+	suite.Run(t, new(ReloadSessionTestSuite))
+}
+
+func (s *ReloadSessionTestSuite) SetupTest() {
+	s.Init()
+	s.session.HeartBtInt = 30 * time.Second
+}
+
+func (s *ReloadSessionTestSuite) reconfigure(settings *SessionSettings) {
+	from := &session{sessionID: s.sessionID}
+	s.Require().Nil(sessionFactory{BuildInitiators: true}.configureSession(from, settings))
+
+	running := make(chan struct{})
+	s.session.status.setRunning(running)
+	defer close(running)
+
+	s.session.admin = make(chan interface{})
+	go func() { s.session.onAdmin(<-s.session.admin) }()
+	s.Require().Nil(s.session.reconfigure(from))
+}
+
+func (s *ReloadSessionTestSuite) settings() *SessionSettings {
+	settings := NewSessionSettings()
+	settings.Set(config.SocketConnectHost, "127.0.0.1")
+	settings.Set(config.SocketConnectPort, "5001")
+	settings.Set(config.HeartBtInt, "60")
+	settings.Set(config.StartTime, "00:00:00")
+	settings.Set(config.EndTime, "00:00:00")
+	settings.Set(config.TimeStampPrecision, "MICROS")
+	return settings
+}
+
+func (s *ReloadSessionTestSuite) TestReconfigureNotConnected() {
+	s.session.State = latentState{}
+	s.reconfigure(s.settings())
+
+	s.Equal(60*time.Second, s.session.HeartBtInt)
+	s.NotNil(s.session.SessionTime)
+	s.Equal(Micros, s.session.timestampPrecision)
+}
+
+func (s *ReloadSessionTestSuite) TestReconfigureHeartBtIntOnNextLogon() {
+	s.session.State = inSession{}
+	s.reconfigure(s.settings())
+
+	s.Equal(30*time.Second, s.session.HeartBtInt, "HeartBtInt applies from the next logon")
+	s.NotNil(s.session.SessionTime)
+	s.Equal(Micros, s.session.timestampPrecision)
+
+	s.MockApp.On("OnLogout")
+	s.session.Disconnected(s.session)
+	s.Equal(60*time.Second, s.session.HeartBtInt)
+}
+
+func TestAcceptorReload(t *testing.T) {
+	cfg := strings.Replace(reloadSettingsTemplate, "SocketConnectHost=127.0.0.1\nSocketConnectPort=5001\nHeartBtInt=30", "SocketAcceptPort=5001", 1)
+	registry := NewRegistry()
+	a, err := NewAcceptor(EmptyApplication{}, NewMemoryStoreFactory(), parseReloadSettings(t, cfg), nullLogFactory{}, WithAcceptorRegistry(registry))
+	require.Nil(t, err)
+
+	target1 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET1"}
+	target2 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET2"}
+
+	reloaded := strings.Replace(cfg, "TargetCompID=TARGET1", "TargetCompID=TARGET1\nMaxLatency=10", 1)
+	reloaded = reloaded[:strings.LastIndex(reloaded, "[SESSION]")]
+	reloaded = strings.Replace(reloaded, "SocketAcceptPort=5001", "SocketAcceptPort=5001\nSocketAcceptHost=127.0.0.1", 1)
+
+	report, err := a.Reload(parseReloadSettings(t, reloaded))
+	require.Nil(t, err)
+	assert.Empty(t, report.Added)
+	assert.Equal(t, []SessionID{target2}, report.Removed)
+	assert.Equal(t, map[SessionID][]string{target1: {config.MaxLatency}}, report.Applied)
+	assert.Equal(t, []string{config.SocketAcceptHost}, report.RestartRequired)
+
+	assert.Equal(t, 10*time.Second, a.sessions[target1].MaxLatency)
+	assert.Equal(t, []SessionID{target1}, registry.Sessions())
+}
+
+func TestAcceptorReloadNotListening(t *testing.T) {
+	cfg := strings.Replace(reloadSettingsTemplate, "SocketConnectHost=127.0.0.1\nSocketConnectPort=5001\nHeartBtInt=30",
+		"SocketAcceptHost=127.0.0.1\nSocketAcceptPort=0\nHeartBtInt=30", 1)
+	registry := NewRegistry()
+	a, err := NewAcceptor(EmptyApplication{}, NewMemoryStoreFactory(), parseReloadSettings(t, cfg), nullLogFactory{}, WithAcceptorRegistry(registry))
+	require.Nil(t, err)
+	require.Nil(t, a.Start())
+	defer a.Stop()
+
+	target1 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET1"}
+	target2 := SessionID{BeginString: BeginStringFIX42, SenderCompID: "SENDER", TargetCompID: "TARGET2"}
+
+	// Restarting TARGET1 on a port without a listener fails before TARGET2 is removed or TARGET1 restarted.
+	reloaded := strings.Replace(cfg, "TargetCompID=TARGET1", "TargetCompID=TARGET1\nSocketAcceptPort=5001", 1)
+	reloaded = reloaded[:strings.LastIndex(reloaded, "[SESSION]")]
+	_, err = a.Reload(parseReloadSettings(t, reloaded))
+	assert.NotNil(t, err)
+	assert.ElementsMatch(t, []SessionID{target1, target2}, registry.Sessions())
+
+	// As does adding a session on a port without a listener.
+	added := cfg + `
+[SESSION]
+BeginString=FIX.4.2
+SenderCompID=SENDER
+TargetCompID=TARGET3
+SocketAcceptPort=5001
+`
+	_, err = a.Reload(parseReloadSettings(t, added))
+	assert.NotNil(t, err)
+	assert.ElementsMatch(t, []SessionID{target1, target2}, registry.Sessions())
+}
@@ -0,0 +1,157 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// socketOptions are the parsed socket settings, see config.SocketNodelay.
+type socketOptions struct {
+	noDelay bool
+	// Buffer sizes in bytes, 0 for the operating system default.
+	sendBufferSize    int
+	receiveBufferSize int
+	// As net.Dialer.KeepAlive, 0 for the default period and negative to disable keepalive.
+	keepAlivePeriod time.Duration
+}
+
+func parseSocketOptions(settings *SessionSettings) (options socketOptions, err error) {
+	options.noDelay = true
+	if settings.HasSetting(config.SocketNodelay) {
+		if options.noDelay, err = settings.BoolSetting(config.SocketNodelay); err != nil {
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketSendBufferSize) {
+		if options.sendBufferSize, err = settings.IntSetting(config.SocketSendBufferSize); err != nil {
+			return
+		}
+
+		if options.sendBufferSize <= 0 {
+			err = errors.New("SocketSendBufferSize must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketReceiveBufferSize) {
+		if options.receiveBufferSize, err = settings.IntSetting(config.SocketReceiveBufferSize); err != nil {
+			return
+		}
+
+		if options.receiveBufferSize <= 0 {
+			err = errors.New("SocketReceiveBufferSize must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketKeepAlivePeriod) {
+		if options.keepAlivePeriod, err = settings.DurationSetting(config.SocketKeepAlivePeriod); err != nil {
+			var periodInt int
+			if periodInt, err = settings.IntSetting(config.SocketKeepAlivePeriod); err != nil {
+				return
+			}
+			options.keepAlivePeriod = time.Duration(periodInt) * time.Second
+		}
+
+		switch {
+		case options.keepAlivePeriod < 0:
+			err = errors.New("SocketKeepAlivePeriod must not be negative")
+			return
+		case options.keepAlivePeriod == 0:
+			options.keepAlivePeriod = -1
+		}
+	}
+
+	return
+}
+
+// tuned returns true if the options change the defaults of a connection, other than keepalive.
+func (o socketOptions) tuned() bool {
+	return !o.noDelay || o.sendBufferSize > 0 || o.receiveBufferSize > 0
+}
+
+// apply sets the options on the TCP connection underlying netConn. Keepalive is set by the dialer or listener.
+func (o socketOptions) apply(netConn net.Conn) error {
+	conn, ok := tcpConn(netConn)
+	if !ok {
+		return nil
+	}
+
+	if err := conn.SetNoDelay(o.noDelay); err != nil {
+		return err
+	}
+
+	if o.sendBufferSize > 0 {
+		if err := conn.SetWriteBuffer(o.sendBufferSize); err != nil {
+			return err
+		}
+	}
+
+	if o.receiveBufferSize > 0 {
+		if err := conn.SetReadBuffer(o.receiveBufferSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tcpConn returns the TCP connection underlying netConn, looking through TLS and PROXY protocol connections.
+func tcpConn(netConn net.Conn) (*net.TCPConn, bool) {
+	for {
+		switch conn := netConn.(type) {
+		case *net.TCPConn:
+			return conn, true
+		case interface{ NetConn() net.Conn }:
+			netConn = conn.NetConn()
+		case interface{ Raw() net.Conn }:
+			netConn = conn.Raw()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// tcpDialer applies socket options to the connections it makes.
+type tcpDialer struct {
+	*net.Dialer
+	options socketOptions
+}
+
+// Dial implements proxy.Dialer.
+func (d *tcpDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext implements proxy.ContextDialer.
+func (d *tcpDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.options.apply(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	proxyproto "github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quickfixgo/quickfix/config"
+)
+
+func TestParseSocketOptions(t *testing.T) {
+	settings := NewSessionSettings()
+	options, err := parseSocketOptions(settings)
+	require.Nil(t, err)
+	assert.Equal(t, socketOptions{noDelay: true}, options)
+	assert.False(t, options.tuned())
+
+	settings.Set(config.SocketNodelay, "N")
+	settings.Set(config.SocketSendBufferSize, "65536")
+	settings.Set(config.SocketReceiveBufferSize, "131072")
+	settings.Set(config.SocketKeepAlivePeriod, "30s")
+	options, err = parseSocketOptions(settings)
+	require.Nil(t, err)
+	assert.Equal(t, socketOptions{sendBufferSize: 65536, receiveBufferSize: 131072, keepAlivePeriod: 30 * time.Second}, options)
+	assert.True(t, options.tuned())
+
+	settings.Set(config.SocketKeepAlivePeriod, "10")
+	options, err = parseSocketOptions(settings)
+	require.Nil(t, err)
+	assert.Equal(t, 10*time.Second, options.keepAlivePeriod)
+
+	settings.Set(config.SocketKeepAlivePeriod, "0")
+	options, err = parseSocketOptions(settings)
+	require.Nil(t, err)
+	assert.Negative(t, options.keepAlivePeriod, "keepalive disabled")
+
+	var tests = []struct {
+		setting, value string
+	}{
+		{config.SocketNodelay, "maybe"},
+		{config.SocketSendBufferSize, "0"},
+		{config.SocketReceiveBufferSize, "-1"},
+		{config.SocketKeepAlivePeriod, "-5s"},
+		{config.SocketKeepAlivePeriod, "often"},
+	}
+
+	for _, test := range tests {
+		s := settings.clone()
+		s.Set(test.setting, test.value)
+		_, err = parseSocketOptions(s)
+		assert.NotNil(t, err, test.setting+"="+test.value)
+	}
+}
+
+func TestTCPConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	for _, wrapped := range []net.Conn{conn, tls.Client(conn, &tls.Config{}), proxyproto.NewConn(conn)} {
+		underlying, ok := tcpConn(wrapped)
+		assert.True(t, ok)
+		assert.Same(t, conn, underlying)
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	_, ok := tcpConn(client)
+	assert.False(t, ok)
+	assert.Nil(t, socketOptions{noDelay: false, sendBufferSize: 4096}.apply(client))
+}
+
+func TestTCPDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	dialer := &tcpDialer{Dialer: &net.Dialer{}, options: socketOptions{sendBufferSize: 32768, receiveBufferSize: 32768}}
+	conn, err := dialer.Dial("tcp", listener.Addr().String())
+	require.Nil(t, err)
+	defer conn.Close()
+
+	serverConn := <-accepted
+	defer serverConn.Close()
+	assert.Nil(t, socketOptions{noDelay: true, sendBufferSize: 32768}.apply(serverConn))
+
+	_, err = conn.Write([]byte("8=FIX.4.4"))
+	assert.Nil(t, err)
+}
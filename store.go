@@ -16,7 +16,11 @@
 package quickfix
 
 import (
+	"bytes"
+	"context"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 // The MessageStore interface provides methods to record and retrieve messages for resend purposes.
@@ -44,7 +48,68 @@ type MessageStore interface {
 	Close() error
 }
 
+// MessageStoreWithContext is implemented by the MessageStores whose operations can be canceled, such as those backed by
+// a database server. The methods behave as those of MessageStore, failing with the error of ctx once it is done.
+// Sessions use them to bound the time a stalled store can block the session, see config.MessageStoreTimeout
+// and config.MessageStoreResendTimeout.
+type MessageStoreWithContext interface {
+	MessageStore
+
+	IncrNextSenderMsgSeqNumContext(ctx context.Context) error
+	IncrNextTargetMsgSeqNumContext(ctx context.Context) error
+
+	SetNextSenderMsgSeqNumContext(ctx context.Context, next int) error
+	SetNextTargetMsgSeqNumContext(ctx context.Context, next int) error
+
+	SaveMessageContext(ctx context.Context, seqNum int, msg []byte) error
+	SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx context.Context, seqNum int, msg []byte) error
+	IterateMessagesContext(ctx context.Context, beginSeqNum, endSeqNum int, cb func([]byte) error) error
+}
+
 // The MessageStoreFactory interface is used by session to create a session specific message store.
 type MessageStoreFactory interface {
 	Create(sessionID SessionID) (MessageStore, error)
 }
+
+// CreationTimeSaver is implemented by the MessageStores that persist their creation time. SetCreationTime of these
+// stores only changes their copy in memory, SaveCreationTime also writes it to the backing store.
+type CreationTimeSaver interface {
+	SaveCreationTime(t time.Time) error
+}
+
+// CopyMessageStore resets dst and copies the state of src to it: the creation time, both seqnums and the messages
+// stored with a seqnum below the next sender seqnum of src. The creation time is set with SaveCreationTime if dst
+// implements CreationTimeSaver, with SetCreationTime otherwise.
+func CopyMessageStore(dst, src MessageStore) error {
+	if err := dst.Reset(); err != nil {
+		return errors.Wrap(err, "reset")
+	}
+
+	msg := NewMessage()
+	err := src.IterateMessages(1, src.NextSenderMsgSeqNum()-1, func(msgBytes []byte) error {
+		if err := ParseMessage(msg, bytes.NewBuffer(msgBytes)); err != nil {
+			return err
+		}
+		seqNum, err := msg.Header.GetInt(tagMsgSeqNum)
+		if err != nil {
+			return err
+		}
+		return dst.SaveMessage(seqNum, msgBytes)
+	})
+	if err != nil {
+		return errors.Wrap(err, "copy messages")
+	}
+
+	if err := dst.SetNextSenderMsgSeqNum(src.NextSenderMsgSeqNum()); err != nil {
+		return errors.Wrap(err, "set next sender")
+	}
+	if err := dst.SetNextTargetMsgSeqNum(src.NextTargetMsgSeqNum()); err != nil {
+		return errors.Wrap(err, "set next target")
+	}
+
+	if saver, ok := dst.(CreationTimeSaver); ok {
+		return errors.Wrap(saver.SaveCreationTime(src.CreationTime()), "save creation time")
+	}
+	dst.SetCreationTime(src.CreationTime())
+	return nil
+}
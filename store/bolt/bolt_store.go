@@ -0,0 +1,381 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package bolt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
+)
+
+// How long to wait for the lock on the database file, held by another process using the store.
+const openTimeout = time.Second
+
+// Number of messages read by each transaction of IterateMessages. The callback runs outside of the transaction,
+// so that a long resend does not hold a read transaction, which keeps the database file from growing.
+const iterateBatchSize = 256
+
+var (
+	sessionBucket  = []byte("session")
+	messagesBucket = []byte("messages")
+
+	creationTimeKey = []byte("creation_time")
+	senderSeqNumKey = []byte("outgoing_seqnum")
+	targetSeqNumKey = []byte("incoming_seqnum")
+)
+
+type boltStoreFactory struct {
+	settings *quickfix.Settings
+}
+
+type boltStore struct {
+	sessionID quickfix.SessionID
+	cache     quickfix.MessageStore
+	fname     string
+	db        *bbolt.DB
+}
+
+// NewStoreFactory returns an implementation of MessageStoreFactory storing each session in an embedded
+// bbolt database file.
+func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return boltStoreFactory{settings: settings}
+}
+
+// Create creates a new BoltStore implementation of the MessageStore interface.
+func (f boltStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
+	globalSettings := f.settings.GlobalSettings()
+	dynamicSessions, _ := globalSettings.BoolSetting(config.DynamicSessions)
+
+	sessionSettings, ok := f.settings.SessionSettings()[sessionID]
+	if !ok {
+		if dynamicSessions {
+			sessionSettings = globalSettings
+		} else {
+			return nil, fmt.Errorf("unknown session: %v", sessionID)
+		}
+	}
+
+	dirname, err := sessionSettings.Setting(config.BoltStorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fsync := true
+	if sessionSettings.HasSetting(config.BoltStoreSync) {
+		if fsync, err = sessionSettings.BoolSetting(config.BoltStoreSync); err != nil {
+			return nil, err
+		}
+	}
+
+	return newBoltStore(sessionID, dirname, fsync)
+}
+
+func newBoltStore(sessionID quickfix.SessionID, dirname string, fsync bool) (*boltStore, error) {
+	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if memErr != nil {
+		return nil, errors.Wrap(memErr, "cache creation")
+	}
+
+	store := &boltStore{
+		sessionID: sessionID,
+		cache:     memStore,
+		fname:     path.Join(dirname, createFilenamePrefix(sessionID)+".db"),
+	}
+
+	db, err := bbolt.Open(store.fname, 0660, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.Wrapf(err, "open %v", store.fname)
+	}
+	db.NoSync = !fsync
+	store.db = db
+
+	if err := store.Refresh(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Reset deletes the stored messages and sets the seqnums back to 1.
+func (store *boltStore) Reset() error {
+	if err := store.cache.Reset(); err != nil {
+		return errors.Wrap(err, "cache reset")
+	}
+
+	return store.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.DeleteBucket(messagesBucket); err != nil && err != bbolt.ErrBucketNotFound {
+			return err
+		}
+		if _, err := tx.CreateBucket(messagesBucket); err != nil {
+			return err
+		}
+		return store.putSession(tx)
+	})
+}
+
+// Refresh reloads the store from the database.
+func (store *boltStore) Refresh() error {
+	if err := store.cache.Reset(); err != nil {
+		return errors.Wrap(err, "cache reset")
+	}
+
+	return store.db.Update(func(tx *bbolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(messagesBucket); err != nil {
+			return err
+		}
+
+		bucket := tx.Bucket(sessionBucket)
+		if bucket == nil {
+			// New database, store the creation time and seqnums of the cache.
+			return store.putSession(tx)
+		}
+
+		var creationTime time.Time
+		if err := creationTime.UnmarshalBinary(bucket.Get(creationTimeKey)); err != nil {
+			return errors.Wrap(err, "read creation time")
+		}
+		store.cache.SetCreationTime(creationTime)
+
+		if err := store.cache.SetNextSenderMsgSeqNum(getSeqNum(bucket, senderSeqNumKey)); err != nil {
+			return errors.Wrap(err, "cache set next sender")
+		}
+		if err := store.cache.SetNextTargetMsgSeqNum(getSeqNum(bucket, targetSeqNumKey)); err != nil {
+			return errors.Wrap(err, "cache set next target")
+		}
+		return nil
+	})
+}
+
+// putSession writes the creation time and seqnums of the cache to the database.
+func (store *boltStore) putSession(tx *bbolt.Tx) error {
+	bucket, err := tx.CreateBucketIfNotExists(sessionBucket)
+	if err != nil {
+		return err
+	}
+
+	creationTime, err := store.cache.CreationTime().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := bucket.Put(creationTimeKey, creationTime); err != nil {
+		return err
+	}
+	if err := putSeqNum(bucket, senderSeqNumKey, store.cache.NextSenderMsgSeqNum()); err != nil {
+		return err
+	}
+	return putSeqNum(bucket, targetSeqNumKey, store.cache.NextTargetMsgSeqNum())
+}
+
+// NextSenderMsgSeqNum returns the next MsgSeqNum that will be sent.
+func (store *boltStore) NextSenderMsgSeqNum() int {
+	return store.cache.NextSenderMsgSeqNum()
+}
+
+// NextTargetMsgSeqNum returns the next MsgSeqNum that should be received.
+func (store *boltStore) NextTargetMsgSeqNum() int {
+	return store.cache.NextTargetMsgSeqNum()
+}
+
+// SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
+func (store *boltStore) SetNextSenderMsgSeqNum(next int) error {
+	err := store.db.Update(func(tx *bbolt.Tx) error {
+		return putSeqNum(tx.Bucket(sessionBucket), senderSeqNumKey, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+// SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
+func (store *boltStore) SetNextTargetMsgSeqNum(next int) error {
+	err := store.db.Update(func(tx *bbolt.Tx) error {
+		return putSeqNum(tx.Bucket(sessionBucket), targetSeqNumKey, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextTargetMsgSeqNum(next)
+}
+
+// IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
+func (store *boltStore) IncrNextSenderMsgSeqNum() error {
+	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
+func (store *boltStore) IncrNextTargetMsgSeqNum() error {
+	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+		return errors.Wrap(err, "store next")
+	}
+	return nil
+}
+
+// CreationTime returns the creation time of the store.
+func (store *boltStore) CreationTime() time.Time {
+	return store.cache.CreationTime()
+}
+
+// SetCreationTime is a no-op for BoltStore.
+func (store *boltStore) SetCreationTime(_ time.Time) {
+}
+
+// SaveCreationTime sets the creation time of the store, see quickfix.CreationTimeSaver.
+func (store *boltStore) SaveCreationTime(t time.Time) error {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	err = store.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(sessionBucket).Put(creationTimeKey, data)
+	})
+	if err != nil {
+		return err
+	}
+	store.cache.SetCreationTime(t)
+	return nil
+}
+
+func (store *boltStore) SaveMessage(seqNum int, msg []byte) error {
+	return store.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(messagesBucket).Put(seqNumKey(seqNum), msg)
+	})
+}
+
+// SaveMessageAndIncrNextSenderMsgSeqNum stores the message and the incremented seqnum in a single transaction.
+func (store *boltStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	next := store.cache.NextSenderMsgSeqNum() + 1
+	err := store.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(messagesBucket).Put(seqNumKey(seqNum), msg); err != nil {
+			return err
+		}
+		return putSeqNum(tx.Bucket(sessionBucket), senderSeqNumKey, next)
+	})
+	if err != nil {
+		return err
+	}
+	return store.cache.SetNextSenderMsgSeqNum(next)
+}
+
+func (store *boltStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	for seqNum := beginSeqNum; seqNum <= endSeqNum; {
+		var msgs [][]byte
+		err := store.db.View(func(tx *bbolt.Tx) error {
+			c := tx.Bucket(messagesBucket).Cursor()
+			for k, v := c.Seek(seqNumKey(seqNum)); k != nil && len(msgs) < iterateBatchSize; k, v = c.Next() {
+				if seqNum = int(binary.BigEndian.Uint64(k)); seqNum > endSeqNum {
+					break
+				}
+				// Values are only valid during the transaction.
+				msgs = append(msgs, append([]byte(nil), v...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			if err := cb(msg); err != nil {
+				return err
+			}
+		}
+		if len(msgs) < iterateBatchSize {
+			return nil
+		}
+		seqNum++
+	}
+	return nil
+}
+
+func (store *boltStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := store.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// Close closes the store's database file.
+func (store *boltStore) Close() error {
+	if store.db != nil {
+		if err := store.db.Close(); err != nil {
+			return err
+		}
+		store.db = nil
+	}
+	return nil
+}
+
+// seqNumKey returns the key of a message, ordered by seqNum.
+func seqNumKey(seqNum int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(seqNum))
+	return key
+}
+
+func putSeqNum(bucket *bbolt.Bucket, key []byte, seqNum int) error {
+	return bucket.Put(key, seqNumKey(seqNum))
+}
+
+func getSeqNum(bucket *bbolt.Bucket, key []byte) int {
+	if v := bucket.Get(key); len(v) == 8 {
+		return int(binary.BigEndian.Uint64(v))
+	}
+	return 1
+}
+
+func createFilenamePrefix(s quickfix.SessionID) string {
+	sender := []string{s.SenderCompID}
+	if s.SenderSubID != "" {
+		sender = append(sender, s.SenderSubID)
+	}
+	if s.SenderLocationID != "" {
+		sender = append(sender, s.SenderLocationID)
+	}
+
+	target := []string{s.TargetCompID}
+	if s.TargetSubID != "" {
+		target = append(target, s.TargetSubID)
+	}
+	if s.TargetLocationID != "" {
+		target = append(target, s.TargetLocationID)
+	}
+
+	fname := []string{s.BeginString, strings.Join(sender, "_"), strings.Join(target, "_")}
+	if s.Qualifier != "" {
+		fname = append(fname, s.Qualifier)
+	}
+	return strings.Join(fname, "-")
+}
@@ -0,0 +1,162 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package bolt
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/internal/testsuite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var testSessionID = quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+// BoltStoreTestSuite runs all tests in the MessageStoreTestSuite against the BoltStore implementation.
+type BoltStoreTestSuite struct {
+	testsuite.StoreTestSuite
+}
+
+func (suite *BoltStoreTestSuite) SetupTest() {
+	// create settings
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+BoltStorePath=%s
+
+[SESSION]
+BeginString=%s
+SenderCompID=%s
+TargetCompID=%s`, suite.T().TempDir(), testSessionID.BeginString, testSessionID.SenderCompID, testSessionID.TargetCompID)))
+	require.Nil(suite.T(), err)
+
+	// create store
+	suite.MsgStore, err = NewStoreFactory(settings).Create(testSessionID)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *BoltStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+}
+
+func TestBoltStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltStoreTestSuite))
+}
+
+func TestBoltStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newBoltStore(testSessionID, dir, false)
+	require.Nil(t, err)
+	assert.Equal(t, path.Join(dir, "FIX.4.4-SENDER-TARGET.db"), store.fname)
+
+	require.Nil(t, store.SetNextTargetMsgSeqNum(7))
+	for seqNum := 1; seqNum <= 3; seqNum++ {
+		require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, []byte(strconv.Itoa(seqNum))))
+	}
+	creationTime := store.CreationTime()
+	require.Nil(t, store.Close())
+
+	store, err = newBoltStore(testSessionID, dir, false)
+	require.Nil(t, err)
+	defer store.Close()
+
+	assert.Equal(t, 4, store.NextSenderMsgSeqNum())
+	assert.Equal(t, 7, store.NextTargetMsgSeqNum())
+	assert.True(t, creationTime.Equal(store.CreationTime()))
+
+	msgs, err := store.GetMessages(1, 3)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("1"), []byte("2"), []byte("3")}, msgs)
+}
+
+func TestBoltStoreLocked(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newBoltStore(testSessionID, dir, true)
+	require.Nil(t, err)
+	defer store.Close()
+
+	// The database file is locked by the first store.
+	_, err = newBoltStore(testSessionID, dir, true)
+	assert.NotNil(t, err)
+}
+
+func TestBoltStoreIterateMessagesBatches(t *testing.T) {
+	store, err := newBoltStore(testSessionID, t.TempDir(), false)
+	require.Nil(t, err)
+	defer store.Close()
+
+	// Gaps, as left by messages that are not persisted.
+	count := 2*iterateBatchSize + 10
+	for seqNum := 1; seqNum <= count; seqNum++ {
+		if seqNum%100 != 0 {
+			require.Nil(t, store.SaveMessage(seqNum, []byte(strconv.Itoa(seqNum))))
+		}
+	}
+
+	var seqNums []int
+	err = store.IterateMessages(5, count-1, func(msg []byte) error {
+		seqNum, err := strconv.Atoi(string(msg))
+		seqNums = append(seqNums, seqNum)
+		return err
+	})
+	require.Nil(t, err)
+
+	var expected []int
+	for seqNum := 5; seqNum < count; seqNum++ {
+		if seqNum%100 != 0 {
+			expected = append(expected, seqNum)
+		}
+	}
+	assert.Equal(t, expected, seqNums)
+
+	// Iteration stops at the first error of the callback.
+	stop := errors.New("stop")
+	calls := 0
+	err = store.IterateMessages(1, count, func([]byte) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBoltStoreFactorySettings(t *testing.T) {
+	dir := t.TempDir()
+	settings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(`
+[DEFAULT]
+BoltStorePath=%s
+BoltStoreSync=N
+
+[SESSION]
+BeginString=FIX.4.4
+SenderCompID=SENDER
+TargetCompID=TARGET`, dir)))
+	require.Nil(t, err)
+
+	store, err := NewStoreFactory(settings).Create(testSessionID)
+	require.Nil(t, err)
+	defer store.Close()
+	assert.True(t, store.(*boltStore).db.NoSync)
+
+	_, err = NewStoreFactory(settings).Create(quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "OTHER", TargetCompID: "TARGET"})
+	assert.NotNil(t, err)
+}
@@ -32,24 +32,37 @@ import (
 
 type fileStoreFactory struct {
 	settings *quickfix.Settings
+	readOnly bool
+
+	// Keep the segments past retention, see NewRepairStoreFactory.
+	keepSegments bool
 }
 
+var errReadOnly = errors.New("store is read-only")
+
 type fileStore struct {
 	sessionID          quickfix.SessionID
 	cache              quickfix.MessageStore
-	bodyFname          string
-	headerFname        string
+	dirname            string
+	sessionPrefix      string
 	sessionFname       string
 	senderSeqNumsFname string
 	targetSeqNumsFname string
+	segmentSettings    segmentSettings
 
-	fileMu            sync.Mutex
+	fileMu sync.Mutex
+	// Segments holding the messages, oldest first. Messages are written to the last segment,
+	// through bodyFile and headerFile.
+	segments          []*segment
 	bodyFile          *os.File
 	headerFile        *os.File
 	sessionFile       *os.File
 	senderSeqNumsFile *os.File
 	targetSeqNumsFile *os.File
 	fileSync          bool
+
+	// A read-only store reads the store files once and fails all writes, see NewReadOnlyStoreFactory.
+	readOnly bool
 }
 
 // NewStoreFactory returns a file-based implementation of MessageStoreFactory.
@@ -57,6 +70,19 @@ func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
 	return fileStoreFactory{settings: settings}
 }
 
+// NewReadOnlyStoreFactory returns a MessageStoreFactory opening the existing files of a FileStore without creating,
+// writing or removing any. Create fails if the store of the session does not exist, and the stores fail all writes.
+func NewReadOnlyStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return fileStoreFactory{settings: settings, readOnly: true}
+}
+
+// NewRepairStoreFactory returns a MessageStoreFactory opening FileStores as NewStoreFactory, except that the segments
+// past FileStoreRetentionDays and FileStoreRetentionSeqNums are kept, for tools changing a store while the engine is
+// not running.
+func NewRepairStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
+	return fileStoreFactory{settings: settings, keepSegments: true}
+}
+
 // Create creates a new FileStore implementation of the MessageStore interface.
 func (f fileStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfix.MessageStore, err error) {
 	globalSettings := f.settings.GlobalSettings()
@@ -84,14 +110,101 @@ func (f fileStoreFactory) Create(sessionID quickfix.SessionID) (msgStore quickfi
 	} else {
 		fsync = true //existing behavior is to fsync writes
 	}
-	return newFileStore(sessionID, dirname, fsync)
+
+	segments, err := parseSegmentSettings(sessionSettings)
+	if err != nil {
+		return nil, err
+	}
+	if f.readOnly {
+		return openReadOnlyFileStore(sessionID, dirname, segments)
+	}
+	if f.keepSegments {
+		segments.retentionAge, segments.retentionSeqNums = 0, 0
+	}
+	return newFileStore(sessionID, dirname, fsync, segments)
+}
+
+func parseSegmentSettings(settings *quickfix.SessionSettings) (segments segmentSettings, err error) {
+	if settings.HasSetting(config.FileStoreSegmentSize) {
+		var size int
+		if size, err = settings.IntSetting(config.FileStoreSegmentSize); err != nil {
+			return
+		}
+		if size <= 0 {
+			err = errors.New("FileStoreSegmentSize must be a positive integer")
+			return
+		}
+		segments.maxSize = int64(size)
+	}
+
+	if settings.HasSetting(config.FileStoreSegmentMessages) {
+		if segments.maxMessages, err = settings.IntSetting(config.FileStoreSegmentMessages); err != nil {
+			return
+		}
+		if segments.maxMessages <= 0 {
+			err = errors.New("FileStoreSegmentMessages must be a positive integer")
+			return
+		}
+	}
+
+	if settings.HasSetting(config.FileStoreRetentionDays) {
+		var days int
+		if days, err = settings.IntSetting(config.FileStoreRetentionDays); err != nil {
+			return
+		}
+		if days <= 0 {
+			err = errors.New("FileStoreRetentionDays must be a positive integer")
+			return
+		}
+		segments.retentionAge = time.Duration(days) * 24 * time.Hour
+	}
+
+	if settings.HasSetting(config.FileStoreRetentionSeqNums) {
+		if segments.retentionSeqNums, err = settings.IntSetting(config.FileStoreRetentionSeqNums); err != nil {
+			return
+		}
+		if segments.retentionSeqNums <= 0 {
+			err = errors.New("FileStoreRetentionSeqNums must be a positive integer")
+			return
+		}
+	}
+
+	return
 }
 
-func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (*fileStore, error) {
+func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool, segments segmentSettings) (*fileStore, error) {
 	if err := os.MkdirAll(dirname, os.ModePerm); err != nil {
 		return nil, err
 	}
 
+	store, err := makeFileStore(sessionID, dirname, fileSync, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// openReadOnlyFileStore returns a read-only store of the existing files of the session in dirname.
+func openReadOnlyFileStore(sessionID quickfix.SessionID, dirname string, segments segmentSettings) (*fileStore, error) {
+	store, err := makeFileStore(sessionID, dirname, false, segments)
+	if err != nil {
+		return nil, err
+	}
+	store.readOnly = true
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func makeFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool, segments segmentSettings) (*fileStore, error) {
 	sessionPrefix := createFilenamePrefix(sessionID)
 
 	memStore, memErr := quickfix.NewMemoryStoreFactory().Create(sessionID)
@@ -99,26 +212,24 @@ func newFileStore(sessionID quickfix.SessionID, dirname string, fileSync bool) (
 		return nil, errors.Wrap(memErr, "cache creation")
 	}
 
-	store := &fileStore{
+	return &fileStore{
 		sessionID:          sessionID,
 		cache:              memStore,
-		bodyFname:          path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "body")),
-		headerFname:        path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "header")),
+		dirname:            dirname,
+		sessionPrefix:      sessionPrefix,
 		sessionFname:       path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "session")),
 		senderSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "senderseqnums")),
 		targetSeqNumsFname: path.Join(dirname, fmt.Sprintf("%s.%s", sessionPrefix, "targetseqnums")),
 		fileSync:           fileSync,
-	}
-
-	if err := store.Refresh(); err != nil {
-		return nil, err
-	}
-
-	return store, nil
+		segmentSettings:    segments,
+	}, nil
 }
 
 // Reset deletes the store files and sets the seqnums back to 1.
 func (store *fileStore) Reset() error {
+	if store.readOnly {
+		return errReadOnly
+	}
 	if err := store.cache.Reset(); err != nil {
 		return errors.Wrap(err, "cache reset")
 	}
@@ -126,11 +237,14 @@ func (store *fileStore) Reset() error {
 	if err := store.Close(); err != nil {
 		return errors.Wrap(err, "close")
 	}
-	if err := removeFile(store.bodyFname); err != nil {
+	segments, err := loadSegments(store.dirname, store.sessionPrefix)
+	if err != nil {
 		return err
 	}
-	if err := removeFile(store.headerFname); err != nil {
-		return err
+	for _, segment := range segments {
+		if err := segment.remove(); err != nil {
+			return err
+		}
 	}
 	if err := removeFile(store.sessionFname); err != nil {
 		return err
@@ -146,6 +260,10 @@ func (store *fileStore) Reset() error {
 
 // Refresh closes the store files and then reloads from them.
 func (store *fileStore) Refresh() (err error) {
+	if store.readOnly {
+		return store.load()
+	}
+
 	if err = store.cache.Reset(); err != nil {
 		err = errors.Wrap(err, "cache reset")
 		return
@@ -160,10 +278,13 @@ func (store *fileStore) Refresh() (err error) {
 		return err
 	}
 
-	if store.bodyFile, err = openOrCreateFile(store.bodyFname, 0660); err != nil {
+	if store.segments, err = loadSegments(store.dirname, store.sessionPrefix); err != nil {
 		return err
 	}
-	if store.headerFile, err = openOrCreateFile(store.headerFname, 0660); err != nil {
+	if len(store.segments) == 0 {
+		store.segments = []*segment{newSegment(store.dirname, store.sessionPrefix, 0)}
+	}
+	if err = store.openSegment(store.currentSegment()); err != nil {
 		return err
 	}
 	if store.sessionFile, err = openOrCreateFile(store.sessionFname, 0660); err != nil {
@@ -189,6 +310,101 @@ func (store *fileStore) Refresh() (err error) {
 	if err := store.SetNextTargetMsgSeqNum(store.NextTargetMsgSeqNum()); err != nil {
 		return errors.Wrap(err, "set next target")
 	}
+
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+	return store.removeExpiredSegmentsLocked()
+}
+
+// load reads the seqnums, creation time and segments from the store files without opening them for writing.
+// It fails if the session file does not exist.
+func (store *fileStore) load() error {
+	if err := store.cache.Reset(); err != nil {
+		return errors.Wrap(err, "cache reset")
+	}
+
+	if _, err := os.Stat(store.sessionFname); err != nil {
+		return errors.Wrapf(err, "no store for session %v", store.sessionID)
+	}
+	creationTimePopulated, err := store.populateCache()
+	if err != nil {
+		return err
+	}
+	if !creationTimePopulated {
+		return fmt.Errorf("unable to read creation time from file: %s", store.sessionFname)
+	}
+
+	segments, err := loadSegments(store.dirname, store.sessionPrefix)
+	if err != nil {
+		return err
+	}
+
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+	store.segments = segments
+	return nil
+}
+
+func (store *fileStore) currentSegment() *segment {
+	return store.segments[len(store.segments)-1]
+}
+
+// openSegment opens the files of s for writing.
+func (store *fileStore) openSegment(s *segment) (err error) {
+	if store.bodyFile, err = openOrCreateFile(s.bodyFname, 0660); err != nil {
+		return err
+	}
+	if store.headerFile, err = openOrCreateFile(s.headerFname, 0660); err != nil {
+		return err
+	}
+	if s.modTime.IsZero() {
+		s.modTime = time.Now()
+	}
+	return nil
+}
+
+// startSegmentLocked closes the files of the current segment and starts writing to a new segment,
+// then removes the segments past retention. fileMu must be held.
+func (store *fileStore) startSegmentLocked() error {
+	if err := closeSyncFile(store.bodyFile); err != nil {
+		return err
+	}
+	if err := closeSyncFile(store.headerFile); err != nil {
+		return err
+	}
+	store.bodyFile = nil
+	store.headerFile = nil
+
+	s := newSegment(store.dirname, store.sessionPrefix, store.currentSegment().index+1)
+	if err := store.openSegment(s); err != nil {
+		return err
+	}
+	store.segments = append(store.segments, s)
+
+	return store.removeExpiredSegmentsLocked()
+}
+
+// removeExpiredSegmentsLocked removes the segments past retention, other than the current segment. fileMu must be held.
+// It runs on each SaveMessage, so that segments expire while the current segment is filling up.
+func (store *fileStore) removeExpiredSegmentsLocked() error {
+	if len(store.segments) < 2 || !store.segmentSettings.retains() {
+		return nil
+	}
+
+	now := time.Now()
+	nextSenderMsgSeqNum := store.cache.NextSenderMsgSeqNum()
+
+	kept := store.segments[:0]
+	for i, s := range store.segments {
+		if i < len(store.segments)-1 && store.segmentSettings.expired(s, now, nextSenderMsgSeqNum) {
+			if err := s.remove(); err != nil {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, s)
+	}
+	store.segments = kept
 	return nil
 }
 
@@ -221,12 +437,19 @@ func (store *fileStore) populateCache() (creationTimePopulated bool, err error)
 }
 
 func (store *fileStore) setSession() error {
+	if store.readOnly {
+		return errReadOnly
+	}
+
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
 
 	if _, err := store.sessionFile.Seek(0, io.SeekStart); err != nil {
 		return fmt.Errorf("unable to rewind file: %s: %s", store.sessionFname, err.Error())
 	}
+	if err := store.sessionFile.Truncate(0); err != nil {
+		return fmt.Errorf("unable to truncate file: %s: %s", store.sessionFname, err.Error())
+	}
 
 	data, err := store.cache.CreationTime().MarshalText()
 	if err != nil {
@@ -244,6 +467,10 @@ func (store *fileStore) setSession() error {
 }
 
 func (store *fileStore) setSeqNum(f *os.File, seqNum int) error {
+	if store.readOnly {
+		return errReadOnly
+	}
+
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
 	if _, err := f.Seek(0, io.SeekStart); err != nil {
@@ -311,23 +538,43 @@ func (store *fileStore) CreationTime() time.Time {
 func (store *fileStore) SetCreationTime(_ time.Time) {
 }
 
+// SaveCreationTime sets the creation time of the store, see quickfix.CreationTimeSaver.
+func (store *fileStore) SaveCreationTime(t time.Time) error {
+	store.cache.SetCreationTime(t)
+	return store.setSession()
+}
+
 func (store *fileStore) SaveMessage(seqNum int, msg []byte) error {
+	if store.readOnly {
+		return errReadOnly
+	}
+
 	store.fileMu.Lock()
 	defer store.fileMu.Unlock()
+	if store.segmentSettings.full(store.currentSegment()) {
+		if err := store.startSegmentLocked(); err != nil {
+			return err
+		}
+	} else if err := store.removeExpiredSegmentsLocked(); err != nil {
+		return err
+	}
+
+	current := store.currentSegment()
 	offset, err := store.bodyFile.Seek(0, io.SeekEnd)
 	if err != nil {
-		return fmt.Errorf("unable to seek to end of file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to seek to end of file: %s: %s", current.bodyFname, err.Error())
 	}
 	if _, err := store.headerFile.Seek(0, io.SeekEnd); err != nil {
-		return fmt.Errorf("unable to seek to end of file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to seek to end of file: %s: %s", current.headerFname, err.Error())
 	}
 	if _, err := fmt.Fprintf(store.headerFile, "%d,%d,%d\n", seqNum, offset, len(msg)); err != nil {
-		return fmt.Errorf("unable to write to file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to write to file: %s: %s", current.headerFname, err.Error())
 	}
 
 	if _, err := store.bodyFile.Write(msg); err != nil {
-		return fmt.Errorf("unable to write to file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to write to file: %s: %s", current.bodyFname, err.Error())
 	}
+	current.add(seqNum, len(msg))
 	if store.fileSync {
 		return store.syncBodyAndHeaderFilesLocked()
 	}
@@ -343,63 +590,79 @@ func (store *fileStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []
 }
 
 func (store *fileStore) syncBodyAndHeaderFilesLocked() error {
+	if store.readOnly {
+		return nil
+	}
 	if err := store.bodyFile.Sync(); err != nil {
-		return fmt.Errorf("unable to flush file: %s: %s", store.bodyFname, err.Error())
+		return fmt.Errorf("unable to flush file: %s: %s", store.bodyFile.Name(), err.Error())
 	} else if err = store.headerFile.Sync(); err != nil {
-		return fmt.Errorf("unable to flush file: %s: %s", store.headerFname, err.Error())
+		return fmt.Errorf("unable to flush file: %s: %s", store.headerFile.Name(), err.Error())
 	}
 	return nil
 }
 
 func (store *fileStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
-	// Sync files
+	// Sync files and take the segments holding the range
 	store.fileMu.Lock()
 	err := store.syncBodyAndHeaderFilesLocked()
+	var segments []segment
+	for _, s := range store.segments {
+		if s.overlaps(beginSeqNum, endSeqNum) {
+			segments = append(segments, *s)
+		}
+	}
 	store.fileMu.Unlock()
 	if err != nil {
 		return err
 	}
 
-	// Open a read only view to body and header file
-	bodyFile, err := openOrCreateFile(store.bodyFname, 0440)
-	if err != nil {
+	for _, s := range segments {
+		if err := iterateSegment(s, beginSeqNum, endSeqNum, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterateSegment calls cb with the messages of s from beginSeqNum to endSeqNum, reading its header file as an index.
+func iterateSegment(s segment, beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	// Open a read only view to body and header file, a segment removed since is skipped
+	bodyFile, err := os.Open(s.bodyFname)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
 		return err
 	}
 	defer func() { _ = bodyFile.Close() }()
-	headerFile, err := openOrCreateFile(store.headerFname, 0440)
-	if err != nil {
+	headerFile, err := os.Open(s.headerFname)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
 		return err
 	}
 	defer func() { _ = headerFile.Close() }()
-	if _, err = headerFile.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("unable to seek to start of file: %s: %s", store.headerFname, err.Error())
-	}
 
 	// Iterate over the header file
-	for {
-		var seqNum, size int
-		var offset int64
-		if cnt, err := fmt.Fscanf(headerFile, "%d,%d,%d\n", &seqNum, &offset, &size); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return fmt.Errorf("unable to read from file: %s: %s", store.headerFname, err.Error())
-		} else if cnt < 3 || seqNum > endSeqNum {
-			// If we have reached the end of possible iteration then break
-			break
+	errDone := errors.New("done")
+	err = readHeader(headerFile, s.headerFname, func(seqNum int, offset int64, size int) error {
+		if seqNum > endSeqNum {
+			// If we have reached the end of possible iteration then stop
+			return errDone
 		} else if seqNum < beginSeqNum {
 			// If we have not yet reached the starting sequence number then continue
-			continue
+			return nil
 		}
 		// Otherwise process the file
 		msg := make([]byte, size)
 		if _, err := bodyFile.ReadAt(msg, offset); err != nil {
-			return fmt.Errorf("unable to read from file: %s: %s", store.bodyFname, err.Error())
-		} else if err = cb(msg); err != nil {
-			return err
+			return fmt.Errorf("unable to read from file: %s: %s", s.bodyFname, err.Error())
 		}
+		return cb(msg)
+	})
+	if err == errDone {
+		return nil
 	}
-	return nil
+	return err
 }
 
 func (store *fileStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
 	"github.com/quickfixgo/quickfix/internal/testsuite"
 	assert2 "github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,6 +68,199 @@ func TestFileStoreTestSuite(t *testing.T) {
 	suite.Run(t, new(FileStoreTestSuite))
 }
 
+// SegmentedFileStoreTestSuite runs all tests in the MessageStoreTestSuite against a FileStore starting a segment every two messages.
+type SegmentedFileStoreTestSuite struct {
+	testsuite.StoreTestSuite
+	dir string
+}
+
+func (suite *SegmentedFileStoreTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	var err error
+	suite.MsgStore, err = newFileStore(sessionID, suite.dir, false, segmentSettings{maxMessages: 2})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *SegmentedFileStoreTestSuite) TearDownTest() {
+	suite.MsgStore.Close()
+}
+
+func TestSegmentedFileStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(SegmentedFileStoreTestSuite))
+}
+
+func newTestFileStore(t *testing.T, dir string, segments segmentSettings) *fileStore {
+	store, err := newFileStore(quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}, dir, false, segments)
+	require.Nil(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func saveMessages(t *testing.T, store *fileStore, from, to int) {
+	for seqNum := from; seqNum <= to; seqNum++ {
+		require.Nil(t, store.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, []byte(fmt.Sprintf("msg%03d", seqNum))))
+	}
+}
+
+func storedSeqNums(t *testing.T, store *fileStore, begin, end int) (seqNums []int) {
+	require.Nil(t, store.IterateMessages(begin, end, func(msg []byte) error {
+		seqNum, err := strconv.Atoi(strings.TrimPrefix(string(msg), "msg"))
+		seqNums = append(seqNums, seqNum)
+		return err
+	}))
+	return
+}
+
+func bodyFiles(t *testing.T, dir string) (names []string) {
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".body") {
+			names = append(names, entry.Name())
+		}
+	}
+	return
+}
+
+func TestFileStoreSegments(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestFileStore(t, dir, segmentSettings{maxMessages: 3, maxSize: 12})
+	saveMessages(t, store, 1, 7)
+
+	// msg001 is 6 bytes, so segments are full after two messages.
+	assert2.Equal(t, []string{"FIX.4.4-SENDER-TARGET.1.body", "FIX.4.4-SENDER-TARGET.2.body", "FIX.4.4-SENDER-TARGET.3.body", "FIX.4.4-SENDER-TARGET.body"}, bodyFiles(t, dir))
+	assert2.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, storedSeqNums(t, store, 1, 7))
+	assert2.Equal(t, []int{3, 4, 5}, storedSeqNums(t, store, 3, 5))
+
+	// The segments are found again on refresh, and new messages continue the last segment.
+	require.Nil(t, store.Refresh())
+	assert2.Len(t, store.segments, 4)
+	assert2.Equal(t, []int{2, 3, 4, 5, 6, 7}, storedSeqNums(t, store, 2, 10))
+	saveMessages(t, store, 8, 9)
+	assert2.Equal(t, 4, store.currentSegment().index)
+	assert2.Equal(t, []int{6, 7, 8, 9}, storedSeqNums(t, store, 6, 9))
+
+	require.Nil(t, store.Reset())
+	assert2.Equal(t, []string{"FIX.4.4-SENDER-TARGET.body"}, bodyFiles(t, dir))
+	assert2.Empty(t, storedSeqNums(t, store, 1, 9))
+}
+
+func TestFileStoreWithoutSegments(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestFileStore(t, dir, segmentSettings{})
+	saveMessages(t, store, 1, 10)
+
+	assert2.Equal(t, []string{"FIX.4.4-SENDER-TARGET.body"}, bodyFiles(t, dir))
+
+	// A store without segments is continued in segments once they are enabled.
+	require.Nil(t, store.Close())
+	store = newTestFileStore(t, dir, segmentSettings{maxMessages: 5})
+	saveMessages(t, store, 11, 12)
+	assert2.Equal(t, []string{"FIX.4.4-SENDER-TARGET.1.body", "FIX.4.4-SENDER-TARGET.body"}, bodyFiles(t, dir))
+	assert2.Equal(t, []int{9, 10, 11, 12}, storedSeqNums(t, store, 9, 12))
+}
+
+func TestFileStoreSegmentRetentionSeqNums(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestFileStore(t, dir, segmentSettings{maxMessages: 2, retentionSeqNums: 3})
+	saveMessages(t, store, 1, 7)
+
+	// Segments are removed when a message is stored: 1-2 is more than 3 below 7 once 7 is stored.
+	assert2.Equal(t, []int{3, 4, 5, 6, 7}, storedSeqNums(t, store, 1, 7))
+	assert2.Len(t, bodyFiles(t, dir), 3)
+
+	// The current segment is kept.
+	require.Nil(t, store.SetNextSenderMsgSeqNum(100))
+	require.Nil(t, store.Refresh())
+	assert2.Equal(t, []int{7}, storedSeqNums(t, store, 1, 7))
+}
+
+func TestFileStoreSegmentRetentionDays(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestFileStore(t, dir, segmentSettings{maxMessages: 2, retentionAge: 24 * time.Hour})
+	saveMessages(t, store, 1, 5)
+	assert2.Equal(t, []int{1, 2, 3, 4, 5}, storedSeqNums(t, store, 1, 5))
+
+	old := time.Now().Add(-48 * time.Hour)
+	require.Nil(t, os.Chtimes(store.segments[0].bodyFname, old, old))
+	require.Nil(t, store.Refresh())
+	assert2.Equal(t, []int{3, 4, 5}, storedSeqNums(t, store, 1, 5))
+}
+
+func TestFileStoreSegmentRetentionOnSave(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestFileStore(t, dir, segmentSettings{maxMessages: 2, retentionAge: 24 * time.Hour})
+	saveMessages(t, store, 1, 3)
+
+	// The current segment is not full, the expired segment is removed when the next message is stored.
+	old := time.Now().Add(-48 * time.Hour)
+	require.Nil(t, os.Chtimes(store.segments[0].bodyFname, old, old))
+	store.segments[0].modTime = old
+	saveMessages(t, store, 4, 4)
+	assert2.Equal(t, []int{3, 4}, storedSeqNums(t, store, 1, 4))
+	assert2.Len(t, bodyFiles(t, dir), 1)
+}
+
+func TestFileStoreReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "SENDER", TargetCompID: "TARGET"}
+
+	// A store that does not exist is not created.
+	_, err := openReadOnlyFileStore(sessionID, path.Join(dir, "missing"), segmentSettings{})
+	assert2.NotNil(t, err)
+	_, err = os.Stat(path.Join(dir, "missing"))
+	assert2.True(t, os.IsNotExist(err))
+
+	store := newTestFileStore(t, dir, segmentSettings{maxMessages: 2})
+	saveMessages(t, store, 1, 5)
+	require.Nil(t, store.SetNextTargetMsgSeqNum(8))
+	require.Nil(t, store.Close())
+
+	// Expired segments are kept.
+	old := time.Now().Add(-48 * time.Hour)
+	require.Nil(t, os.Chtimes(store.segments[0].bodyFname, old, old))
+	readOnly, err := openReadOnlyFileStore(sessionID, dir, segmentSettings{maxMessages: 2, retentionAge: 24 * time.Hour})
+	require.Nil(t, err)
+	defer readOnly.Close()
+
+	assert2.Equal(t, 6, readOnly.NextSenderMsgSeqNum())
+	assert2.Equal(t, 8, readOnly.NextTargetMsgSeqNum())
+	assert2.Equal(t, store.CreationTime().UTC(), readOnly.CreationTime().UTC())
+	assert2.Equal(t, []int{1, 2, 3, 4, 5}, storedSeqNums(t, readOnly, 1, 5))
+
+	assert2.NotNil(t, readOnly.SaveMessage(6, []byte("msg006")))
+	assert2.NotNil(t, readOnly.SetNextSenderMsgSeqNum(10))
+	assert2.NotNil(t, readOnly.Reset())
+	require.Nil(t, readOnly.Refresh())
+	assert2.Equal(t, []int{1, 2, 3, 4, 5}, storedSeqNums(t, readOnly, 1, 5))
+	assert2.Equal(t, 6, readOnly.NextSenderMsgSeqNum())
+	assert2.Len(t, bodyFiles(t, dir), 3)
+}
+
+func TestParseSegmentSettings(t *testing.T) {
+	settings := quickfix.NewSessionSettings()
+	segments, err := parseSegmentSettings(settings)
+	require.Nil(t, err)
+	assert2.Equal(t, segmentSettings{}, segments)
+
+	settings.Set(config.FileStoreSegmentSize, "1048576")
+	settings.Set(config.FileStoreSegmentMessages, "10000")
+	settings.Set(config.FileStoreRetentionDays, "7")
+	settings.Set(config.FileStoreRetentionSeqNums, "50000")
+	segments, err = parseSegmentSettings(settings)
+	require.Nil(t, err)
+	assert2.Equal(t, segmentSettings{maxSize: 1048576, maxMessages: 10000, retentionAge: 7 * 24 * time.Hour, retentionSeqNums: 50000}, segments)
+
+	for _, setting := range []string{config.FileStoreSegmentSize, config.FileStoreSegmentMessages, config.FileStoreRetentionDays, config.FileStoreRetentionSeqNums} {
+		settings := quickfix.NewSessionSettings()
+		settings.Set(setting, "0")
+		_, err = parseSegmentSettings(settings)
+		assert2.NotNil(t, err, setting)
+	}
+}
+
 func TestStringParse(t *testing.T) {
 	assert := assert2.New(t)
 	i, err := strconv.Atoi(strings.Trim("00005\n", "\r\n"))
@@ -0,0 +1,210 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// segmentSettings are the parsed FileStoreSegment* and FileStoreRetention* settings.
+type segmentSettings struct {
+	// Limits of a segment, 0 for no limit.
+	maxSize     int64
+	maxMessages int
+
+	// Retention of old segments, 0 to keep them.
+	retentionAge     time.Duration
+	retentionSeqNums int
+}
+
+// segment is a pair of body and header files holding part of the messages of a store.
+// Only the range of MsgSeqNums of a segment is kept in memory, its messages are read from the files.
+type segment struct {
+	// The first segment of a store uses the body and header files of a store without segments.
+	index       int
+	bodyFname   string
+	headerFname string
+
+	minSeqNum, maxSeqNum int
+	messages             int
+	size                 int64
+	modTime              time.Time
+}
+
+func (s *segment) add(seqNum int, size int) {
+	if s.messages == 0 || seqNum < s.minSeqNum {
+		s.minSeqNum = seqNum
+	}
+	if s.messages == 0 || seqNum > s.maxSeqNum {
+		s.maxSeqNum = seqNum
+	}
+	s.messages++
+	s.size += int64(size)
+	s.modTime = time.Now()
+}
+
+// overlaps returns true if the segment may hold messages from beginSeqNum to endSeqNum.
+func (s *segment) overlaps(beginSeqNum, endSeqNum int) bool {
+	return s.messages > 0 && s.minSeqNum <= endSeqNum && s.maxSeqNum >= beginSeqNum
+}
+
+// full returns true if a message should go to a new segment rather than s.
+func (c segmentSettings) full(s *segment) bool {
+	if s.messages == 0 {
+		return false
+	}
+	return (c.maxSize > 0 && s.size >= c.maxSize) || (c.maxMessages > 0 && s.messages >= c.maxMessages)
+}
+
+// retains returns true if old segments are removed.
+func (c segmentSettings) retains() bool {
+	return c.retentionAge > 0 || c.retentionSeqNums > 0
+}
+
+// expired returns true if the segment s can be removed at now, given the next sender MsgSeqNum of the store.
+func (c segmentSettings) expired(s *segment, now time.Time, nextSenderMsgSeqNum int) bool {
+	if c.retentionAge > 0 && now.Sub(s.modTime) > c.retentionAge {
+		return true
+	}
+	return c.retentionSeqNums > 0 && s.messages > 0 && s.maxSeqNum < nextSenderMsgSeqNum-c.retentionSeqNums
+}
+
+func newSegment(dirname, sessionPrefix string, index int) *segment {
+	name := sessionPrefix
+	if index > 0 {
+		name = fmt.Sprintf("%s.%d", sessionPrefix, index)
+	}
+	return &segment{
+		index:       index,
+		bodyFname:   path.Join(dirname, name+".body"),
+		headerFname: path.Join(dirname, name+".header"),
+	}
+}
+
+// segmentIndex returns the index of the segment a body or header file named fname belongs to.
+func segmentIndex(sessionPrefix, fname string) (int, bool) {
+	var name string
+	switch {
+	case strings.HasSuffix(fname, ".body"):
+		name = strings.TrimSuffix(fname, ".body")
+	case strings.HasSuffix(fname, ".header"):
+		name = strings.TrimSuffix(fname, ".header")
+	default:
+		return 0, false
+	}
+
+	if name == sessionPrefix {
+		return 0, true
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(name, sessionPrefix+"."))
+	if err != nil || !strings.HasPrefix(name, sessionPrefix+".") || index <= 0 {
+		return 0, false
+	}
+	return index, true
+}
+
+// loadSegments returns the segments of the session found in dirname, oldest first, with the range of MsgSeqNums of each.
+func loadSegments(dirname, sessionPrefix string) ([]*segment, error) {
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[int]bool)
+	for _, entry := range entries {
+		if index, ok := segmentIndex(sessionPrefix, entry.Name()); ok {
+			indexes[index] = true
+		}
+	}
+
+	var segments []*segment
+	for index := range indexes {
+		s := newSegment(dirname, sessionPrefix, index)
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].index < segments[j].index })
+	return segments, nil
+}
+
+// load reads the range of MsgSeqNums of the segment from its header file.
+func (s *segment) load() error {
+	headerFile, err := os.Open(s.headerFname)
+	if err == nil {
+		defer func() { _ = headerFile.Close() }()
+
+		err = readHeader(headerFile, s.headerFname, func(seqNum int, _ int64, size int) error {
+			s.add(seqNum, size)
+			return nil
+		})
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	info, err := os.Stat(s.bodyFname)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.size = info.Size()
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// remove deletes the files of the segment.
+func (s *segment) remove() error {
+	if err := removeFile(s.bodyFname); err != nil {
+		return err
+	}
+	return removeFile(s.headerFname)
+}
+
+// readHeader calls fn with each entry of a header file. A last line without a newline, left by an interrupted write, is ignored.
+func readHeader(r io.Reader, fname string, fn func(seqNum int, offset int64, size int) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read from file: %s: %s", fname, err.Error())
+		}
+
+		var seqNum, size int
+		var offset int64
+		if _, err := fmt.Sscanf(line, "%d,%d,%d\n", &seqNum, &offset, &size); err != nil {
+			return fmt.Errorf("unable to read from file: %s: %s", fname, err.Error())
+		}
+
+		if err := fn(seqNum, offset, size); err != nil {
+			return err
+		}
+	}
+}
@@ -46,6 +46,8 @@ type mongoStore struct {
 	allowTransactions  bool
 }
 
+var _ quickfix.MessageStoreWithContext = &mongoStore{}
+
 // NewStoreFactory returns a mongo-based implementation of MessageStoreFactory.
 func NewStoreFactory(settings *quickfix.Settings) quickfix.MessageStoreFactory {
 	return NewStoreFactoryPrefixed(settings, "")
@@ -235,12 +237,17 @@ func (store *mongoStore) NextTargetMsgSeqNum() int {
 
 // SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
 func (store *mongoStore) SetNextSenderMsgSeqNum(next int) error {
+	return store.SetNextSenderMsgSeqNumContext(context.Background(), next)
+}
+
+// SetNextSenderMsgSeqNumContext sets the next MsgSeqNum that will be sent.
+func (store *mongoStore) SetNextSenderMsgSeqNumContext(ctx context.Context, next int) error {
 	msgFilter := generateMessageFilter(&store.sessionID)
 	sessionUpdate := generateMessageFilter(&store.sessionID)
 	sessionUpdate.IncomingSeqNum = store.cache.NextTargetMsgSeqNum()
 	sessionUpdate.OutgoingSeqNum = next
 	sessionUpdate.CreationTime = store.cache.CreationTime()
-	if _, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(context.Background(), msgFilter, bson.M{"$set": sessionUpdate}); err != nil {
+	if _, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(ctx, msgFilter, bson.M{"$set": sessionUpdate}); err != nil {
 		return err
 	}
 	return store.cache.SetNextSenderMsgSeqNum(next)
@@ -248,12 +255,17 @@ func (store *mongoStore) SetNextSenderMsgSeqNum(next int) error {
 
 // SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
 func (store *mongoStore) SetNextTargetMsgSeqNum(next int) error {
+	return store.SetNextTargetMsgSeqNumContext(context.Background(), next)
+}
+
+// SetNextTargetMsgSeqNumContext sets the next MsgSeqNum that should be received.
+func (store *mongoStore) SetNextTargetMsgSeqNumContext(ctx context.Context, next int) error {
 	msgFilter := generateMessageFilter(&store.sessionID)
 	sessionUpdate := generateMessageFilter(&store.sessionID)
 	sessionUpdate.IncomingSeqNum = next
 	sessionUpdate.OutgoingSeqNum = store.cache.NextSenderMsgSeqNum()
 	sessionUpdate.CreationTime = store.cache.CreationTime()
-	if _, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(context.Background(), msgFilter, bson.M{"$set": sessionUpdate}); err != nil {
+	if _, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(ctx, msgFilter, bson.M{"$set": sessionUpdate}); err != nil {
 		return err
 	}
 	return store.cache.SetNextTargetMsgSeqNum(next)
@@ -261,7 +273,12 @@ func (store *mongoStore) SetNextTargetMsgSeqNum(next int) error {
 
 // IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
 func (store *mongoStore) IncrNextSenderMsgSeqNum() error {
-	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+	return store.IncrNextSenderMsgSeqNumContext(context.Background())
+}
+
+// IncrNextSenderMsgSeqNumContext increments the next MsgSeqNum that will be sent.
+func (store *mongoStore) IncrNextSenderMsgSeqNumContext(ctx context.Context) error {
+	if err := store.SetNextSenderMsgSeqNumContext(ctx, store.cache.NextSenderMsgSeqNum()+1); err != nil {
 		return errors.Wrap(err, "save sequence number")
 	}
 	return nil
@@ -269,7 +286,12 @@ func (store *mongoStore) IncrNextSenderMsgSeqNum() error {
 
 // IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
 func (store *mongoStore) IncrNextTargetMsgSeqNum() error {
-	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+	return store.IncrNextTargetMsgSeqNumContext(context.Background())
+}
+
+// IncrNextTargetMsgSeqNumContext increments the next MsgSeqNum that should be received.
+func (store *mongoStore) IncrNextTargetMsgSeqNumContext(ctx context.Context) error {
+	if err := store.SetNextTargetMsgSeqNumContext(ctx, store.cache.NextTargetMsgSeqNum()+1); err != nil {
 		return errors.Wrap(err, "save sequence number")
 	}
 	return nil
@@ -284,27 +306,49 @@ func (store *mongoStore) CreationTime() time.Time {
 func (store *mongoStore) SetCreationTime(_ time.Time) {
 }
 
+// SaveCreationTime sets the creation time of the store, see quickfix.CreationTimeSaver.
+func (store *mongoStore) SaveCreationTime(t time.Time) error {
+	msgFilter := generateMessageFilter(&store.sessionID)
+	sessionUpdate := generateMessageFilter(&store.sessionID)
+	sessionUpdate.IncomingSeqNum = store.cache.NextTargetMsgSeqNum()
+	sessionUpdate.OutgoingSeqNum = store.cache.NextSenderMsgSeqNum()
+	sessionUpdate.CreationTime = t
+	if _, err := store.db.Database(store.mongoDatabase).Collection(store.sessionsCollection).UpdateOne(context.Background(), msgFilter, bson.M{"$set": sessionUpdate}); err != nil {
+		return err
+	}
+	store.cache.SetCreationTime(t)
+	return nil
+}
+
 func (store *mongoStore) SaveMessage(seqNum int, msg []byte) (err error) {
+	return store.SaveMessageContext(context.Background(), seqNum, msg)
+}
+
+func (store *mongoStore) SaveMessageContext(ctx context.Context, seqNum int, msg []byte) (err error) {
 	msgFilter := generateMessageFilter(&store.sessionID)
 	msgFilter.Msgseq = seqNum
 	msgFilter.Message = msg
-	_, err = store.db.Database(store.mongoDatabase).Collection(store.messagesCollection).InsertOne(context.Background(), msgFilter)
+	_, err = store.db.Database(store.mongoDatabase).Collection(store.messagesCollection).InsertOne(ctx, msgFilter)
 	return
 }
 
 func (store *mongoStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	return store.SaveMessageAndIncrNextSenderMsgSeqNumContext(context.Background(), seqNum, msg)
+}
+
+func (store *mongoStore) SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx context.Context, seqNum int, msg []byte) error {
 
 	if !store.allowTransactions {
-		err := store.SaveMessage(seqNum, msg)
+		err := store.SaveMessageContext(ctx, seqNum, msg)
 		if err != nil {
 			return err
 		}
-		return store.IncrNextSenderMsgSeqNum()
+		return store.IncrNextSenderMsgSeqNumContext(ctx)
 	}
 
 	// If the mongodb supports replicasets, perform this operation as a transaction instead-
 	var next int
-	err := store.db.UseSession(context.Background(), func(sessionCtx mongo.SessionContext) error {
+	err := store.db.UseSession(ctx, func(sessionCtx mongo.SessionContext) error {
 		if err := sessionCtx.StartTransaction(); err != nil {
 			return err
 		}
@@ -329,7 +373,7 @@ func (store *mongoStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg [
 			return err
 		}
 
-		return sessionCtx.CommitTransaction(context.Background())
+		return sessionCtx.CommitTransaction(sessionCtx)
 	})
 	if err != nil {
 		return err
@@ -339,6 +383,10 @@ func (store *mongoStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg [
 }
 
 func (store *mongoStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	return store.IterateMessagesContext(context.Background(), beginSeqNum, endSeqNum, cb)
+}
+
+func (store *mongoStore) IterateMessagesContext(ctx context.Context, beginSeqNum, endSeqNum int, cb func([]byte) error) error {
 	msgFilter := generateMessageFilter(&store.sessionID)
 	// Marshal into database form.
 	msgFilterBytes, err := bson.Marshal(msgFilter)
@@ -356,19 +404,19 @@ func (store *mongoStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]b
 		"$lte": endSeqNum,
 	}
 	sortOpt := options.Find().SetSort(bson.D{{Key: "msgseq", Value: 1}})
-	cursor, err := store.db.Database(store.mongoDatabase).Collection(store.messagesCollection).Find(context.Background(), seqFilter, sortOpt)
+	cursor, err := store.db.Database(store.mongoDatabase).Collection(store.messagesCollection).Find(ctx, seqFilter, sortOpt)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = cursor.Close(context.Background()) }()
-	for cursor.Next(context.Background()) {
+	for cursor.Next(ctx) {
 		if err = cursor.Decode(&msgFilter); err != nil {
 			return err
 		} else if err = cb(msgFilter.Message); err != nil {
 			return err
 		}
 	}
-	return nil
+	return cursor.Err()
 }
 
 func (store *mongoStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
@@ -16,6 +16,7 @@
 package mongo
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -63,6 +64,22 @@ TargetCompID=%s`, mongoDbCxn, mongoDatabase, mongoReplicaSet, sessionID.BeginStr
 	require.Nil(suite.T(), err)
 }
 
+func (suite *MongoStoreTestSuite) TestContextCanceled() {
+	store := suite.MsgStore.(quickfix.MessageStoreWithContext)
+	require.Nil(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNumContext(context.Background(), 1, []byte("hello")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.NotNil(store.SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx, 2, []byte("world")))
+	suite.NotNil(store.IncrNextTargetMsgSeqNumContext(ctx))
+	suite.NotNil(store.IterateMessagesContext(ctx, 1, 2, func([]byte) error { return nil }))
+
+	// Canceled operations leave the store unchanged.
+	suite.Equal(2, store.NextSenderMsgSeqNum())
+	suite.Equal(1, store.NextTargetMsgSeqNum())
+}
+
 func (suite *MongoStoreTestSuite) TearDownTest() {
 	if suite.MsgStore != nil {
 		err := suite.MsgStore.Close()
@@ -16,6 +16,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
@@ -59,6 +60,8 @@ type sqlStore struct {
 	sqlDeleteMessages     string
 }
 
+var _ quickfix.MessageStoreWithContext = &sqlStore{}
+
 type placeholderFunc func(int) string
 
 var rePlaceholder = regexp.MustCompile(`\?`)
@@ -294,8 +297,13 @@ func (store *sqlStore) NextTargetMsgSeqNum() int {
 
 // SetNextSenderMsgSeqNum sets the next MsgSeqNum that will be sent.
 func (store *sqlStore) SetNextSenderMsgSeqNum(next int) error {
+	return store.SetNextSenderMsgSeqNumContext(context.Background(), next)
+}
+
+// SetNextSenderMsgSeqNumContext sets the next MsgSeqNum that will be sent.
+func (store *sqlStore) SetNextSenderMsgSeqNumContext(ctx context.Context, next int) error {
 	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
+	_, err := store.db.ExecContext(ctx, sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
 		next, s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
 		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
@@ -307,8 +315,13 @@ func (store *sqlStore) SetNextSenderMsgSeqNum(next int) error {
 
 // SetNextTargetMsgSeqNum sets the next MsgSeqNum that should be received.
 func (store *sqlStore) SetNextTargetMsgSeqNum(next int) error {
+	return store.SetNextTargetMsgSeqNumContext(context.Background(), next)
+}
+
+// SetNextTargetMsgSeqNumContext sets the next MsgSeqNum that should be received.
+func (store *sqlStore) SetNextTargetMsgSeqNumContext(ctx context.Context, next int) error {
 	s := store.sessionID
-	_, err := store.db.Exec(sqlString(store.sqlUpdateTargetSeqNum, store.placeholder),
+	_, err := store.db.ExecContext(ctx, sqlString(store.sqlUpdateTargetSeqNum, store.placeholder),
 		next, s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
 		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
@@ -320,7 +333,12 @@ func (store *sqlStore) SetNextTargetMsgSeqNum(next int) error {
 
 // IncrNextSenderMsgSeqNum increments the next MsgSeqNum that will be sent.
 func (store *sqlStore) IncrNextSenderMsgSeqNum() error {
-	if err := store.SetNextSenderMsgSeqNum(store.cache.NextSenderMsgSeqNum() + 1); err != nil {
+	return store.IncrNextSenderMsgSeqNumContext(context.Background())
+}
+
+// IncrNextSenderMsgSeqNumContext increments the next MsgSeqNum that will be sent.
+func (store *sqlStore) IncrNextSenderMsgSeqNumContext(ctx context.Context) error {
+	if err := store.SetNextSenderMsgSeqNumContext(ctx, store.cache.NextSenderMsgSeqNum()+1); err != nil {
 		return errors.Wrap(err, "store next")
 	}
 	return nil
@@ -328,7 +346,12 @@ func (store *sqlStore) IncrNextSenderMsgSeqNum() error {
 
 // IncrNextTargetMsgSeqNum increments the next MsgSeqNum that should be received.
 func (store *sqlStore) IncrNextTargetMsgSeqNum() error {
-	if err := store.SetNextTargetMsgSeqNum(store.cache.NextTargetMsgSeqNum() + 1); err != nil {
+	return store.IncrNextTargetMsgSeqNumContext(context.Background())
+}
+
+// IncrNextTargetMsgSeqNumContext increments the next MsgSeqNum that should be received.
+func (store *sqlStore) IncrNextTargetMsgSeqNumContext(ctx context.Context) error {
+	if err := store.SetNextTargetMsgSeqNumContext(ctx, store.cache.NextTargetMsgSeqNum()+1); err != nil {
 		return errors.Wrap(err, "store next")
 	}
 	return nil
@@ -343,10 +366,29 @@ func (store *sqlStore) CreationTime() time.Time {
 func (store *sqlStore) SetCreationTime(_ time.Time) {
 }
 
+// SaveCreationTime sets the creation time of the store, see quickfix.CreationTimeSaver.
+func (store *sqlStore) SaveCreationTime(t time.Time) error {
+	s := store.sessionID
+	_, err := store.db.Exec(sqlString(store.sqlUpdateSession, store.placeholder),
+		t, store.cache.NextTargetMsgSeqNum(), store.cache.NextSenderMsgSeqNum(),
+		s.BeginString, s.Qualifier,
+		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
+		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
+	if err != nil {
+		return err
+	}
+	store.cache.SetCreationTime(t)
+	return nil
+}
+
 func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
+	return store.SaveMessageContext(context.Background(), seqNum, msg)
+}
+
+func (store *sqlStore) SaveMessageContext(ctx context.Context, seqNum int, msg []byte) error {
 	s := store.sessionID
 
-	_, err := store.db.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
+	_, err := store.db.ExecContext(ctx, sqlString(store.sqlInsertMessage, store.placeholder),
 		seqNum, string(msg),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
@@ -356,15 +398,19 @@ func (store *sqlStore) SaveMessage(seqNum int, msg []byte) error {
 }
 
 func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	return store.SaveMessageAndIncrNextSenderMsgSeqNumContext(context.Background(), seqNum, msg)
+}
+
+func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx context.Context, seqNum int, msg []byte) error {
 	s := store.sessionID
 
-	tx, err := store.db.Begin()
+	tx, err := store.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(sqlString(store.sqlInsertMessage, store.placeholder),
+	_, err = tx.ExecContext(ctx, sqlString(store.sqlInsertMessage, store.placeholder),
 		seqNum, string(msg),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
@@ -374,7 +420,7 @@ func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []b
 	}
 
 	next := store.cache.NextSenderMsgSeqNum() + 1
-	_, err = tx.Exec(sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
+	_, err = tx.ExecContext(ctx, sqlString(store.sqlUpdateSenderSeqNum, store.placeholder),
 		next, s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
 		s.TargetCompID, s.TargetSubID, s.TargetLocationID)
@@ -391,8 +437,12 @@ func (store *sqlStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []b
 }
 
 func (store *sqlStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	return store.IterateMessagesContext(context.Background(), beginSeqNum, endSeqNum, cb)
+}
+
+func (store *sqlStore) IterateMessagesContext(ctx context.Context, beginSeqNum, endSeqNum int, cb func([]byte) error) error {
 	s := store.sessionID
-	rows, err := store.db.Query(sqlString(store.sqlGetMessages, store.placeholder),
+	rows, err := store.db.QueryContext(ctx, sqlString(store.sqlGetMessages, store.placeholder),
 		s.BeginString, s.Qualifier,
 		s.SenderCompID, s.SenderSubID, s.SenderLocationID,
 		s.TargetCompID, s.TargetSubID, s.TargetLocationID,
@@ -16,6 +16,7 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -159,6 +160,25 @@ TargetCompID=%s
 	suite.Equal(1, nextTarget)
 }
 
+func (suite *SQLStoreTestSuite) TestContextCanceled() {
+	store := suite.MsgStore.(quickfix.MessageStoreWithContext)
+	require.Nil(suite.T(), store.SaveMessageAndIncrNextSenderMsgSeqNumContext(context.Background(), 1, []byte("hello")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.ErrorIs(store.SaveMessageAndIncrNextSenderMsgSeqNumContext(ctx, 2, []byte("world")), context.Canceled)
+	suite.ErrorIs(store.IncrNextTargetMsgSeqNumContext(ctx), context.Canceled)
+	suite.ErrorIs(store.IterateMessagesContext(ctx, 1, 2, func([]byte) error { return nil }), context.Canceled)
+
+	// Canceled operations leave the store unchanged.
+	suite.Equal(2, store.NextSenderMsgSeqNum())
+	suite.Equal(1, store.NextTargetMsgSeqNum())
+	msgs, err := store.GetMessages(1, 2)
+	require.Nil(suite.T(), err)
+	suite.Equal([][]byte{[]byte("hello")}, msgs)
+}
+
 func (suite *SQLStoreTestSuite) TearDownTest() {
 	suite.MsgStore.Close()
 	os.RemoveAll(suite.sqlStoreRootPath)
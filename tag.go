@@ -71,6 +71,10 @@ const (
 	tagNewSeqNo             Tag = 36
 	tagBeginSeqNo           Tag = 7
 	tagEndSeqNo             Tag = 16
+	tagUsername             Tag = 553
+	tagPassword             Tag = 554
+	tagNewPassword          Tag = 925
+	tagSessionStatus        Tag = 1409
 
 	tagSignatureLength Tag = 93
 	tagSignature       Tag = 89
@@ -0,0 +1,295 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default number of messages the QUEUE policy holds, see config.ThrottleQueueLimit.
+const defaultThrottleQueueLimit = 10000
+
+type throttlePolicy int
+
+const (
+	throttleReject throttlePolicy = iota
+	throttleBlock
+	throttleQueue
+)
+
+// throttleSettings are the parsed Throttle* settings of a session, see config.ThrottleMessages.
+type throttleSettings struct {
+	// Messages allowed per interval, 0 if throttling is disabled.
+	messages int
+	interval time.Duration
+
+	// If set, only these MsgTypes are throttled, each with its own limit.
+	msgTypes []string
+	policy   throttlePolicy
+
+	// Messages the QUEUE policy holds at most.
+	queueLimit int
+}
+
+// tokenBucket allows bursts of up to capacity messages, refilled at capacity messages per interval.
+type tokenBucket struct {
+	capacity float64
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(messages int, interval time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{capacity: float64(messages), interval: interval, tokens: float64(messages), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !now.After(b.last) {
+		return
+	}
+
+	b.tokens = math.Min(b.capacity, b.tokens+b.capacity*float64(now.Sub(b.last))/float64(b.interval))
+	b.last = now
+}
+
+// take removes a token if one is available.
+func (b *tokenBucket) take(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// reserve removes a token, going into debt if none is available, and returns the wait until the token is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.interval) / b.capacity)
+}
+
+type throttledMessage struct {
+	msg     *Message
+	bucket  string
+	release time.Time
+}
+
+// throttle limits the rate application messages are sent with SendToTarget. Admin messages are never throttled.
+type throttle struct {
+	// Serializes releaseDue so queued messages are sent in order, locked before mu.
+	releaseMu sync.Mutex
+
+	mu       sync.Mutex
+	settings throttleSettings
+	buckets  map[string]*tokenBucket
+
+	// Messages held by the QUEUE policy, ordered by release time.
+	pending []throttledMessage
+	timer   *time.Timer
+
+	// Sends a message held by the QUEUE policy, called without mu held.
+	release func(*Message)
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newThrottle(settings throttleSettings, release func(*Message)) *throttle {
+	t := &throttle{release: release, now: time.Now, sleep: time.Sleep}
+	t.configure(settings)
+	return t
+}
+
+// configure applies settings. The limits start over, messages already queued keep their release time.
+func (t *throttle) configure(settings throttleSettings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.settings = settings
+	t.buckets = make(map[string]*tokenBucket)
+}
+
+// bucket returns the name and token bucket limiting msgType, or a nil bucket if msgType is not throttled.
+// t.mu must be held.
+func (t *throttle) bucket(msgType string, now time.Time) (string, *tokenBucket) {
+	if t.settings.messages == 0 {
+		return "", nil
+	}
+
+	var name string
+	if len(t.settings.msgTypes) > 0 {
+		if !slices.Contains(t.settings.msgTypes, msgType) {
+			return "", nil
+		}
+		name = msgType
+	}
+
+	b, ok := t.buckets[name]
+	if !ok {
+		b = newTokenBucket(t.settings.messages, t.settings.interval, now)
+		t.buckets[name] = b
+	}
+	return name, b
+}
+
+// admit applies the throttle to msg before it is sent. If queued is true, a copy of msg is held and sent with release later.
+// The BLOCK policy sleeps on the calling goroutine, so a caller holding a lock holds it until msg may be sent.
+func (t *throttle) admit(msg *Message) (queued bool, err error) {
+	msgType, err := msg.Header.GetBytes(tagMsgType)
+	if err != nil || isAdminMessageType(msgType) {
+		// Messages without a MsgType fail validation when sent.
+		return false, nil
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	name, b := t.bucket(string(msgType), now)
+	if b == nil {
+		t.mu.Unlock()
+		return false, nil
+	}
+
+	switch t.settings.policy {
+	case throttleReject:
+		ok := b.take(now)
+		t.mu.Unlock()
+		if !ok {
+			return false, ErrThrottled
+		}
+		return false, nil
+
+	case throttleBlock:
+		wait := b.reserve(now)
+		t.mu.Unlock()
+		if wait > 0 {
+			t.sleep(wait)
+		}
+		return false, nil
+	}
+
+	defer t.mu.Unlock()
+
+	if !t.hasPending(name) {
+		if b.take(now) {
+			return false, nil
+		}
+	}
+
+	if len(t.pending) >= t.settings.queueLimit {
+		return false, ErrThrottled
+	}
+
+	wait := b.reserve(now)
+
+	// The caller may reuse msg once it is queued, so a copy is held until it is sent.
+	queuedMsg := NewMessage()
+	msg.CopyInto(queuedMsg)
+
+	release := now.Add(wait)
+	i := sort.Search(len(t.pending), func(i int) bool { return t.pending[i].release.After(release) })
+	t.pending = slices.Insert(t.pending, i, throttledMessage{msg: queuedMsg, bucket: name, release: release})
+	if i == 0 {
+		t.schedule(now)
+	}
+	return true, nil
+}
+
+// hasPending returns true if messages limited by the named bucket are queued. t.mu must be held.
+func (t *throttle) hasPending(name string) bool {
+	for _, p := range t.pending {
+		if p.bucket == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule sets the timer for the first queued message. t.mu must be held.
+func (t *throttle) schedule(now time.Time) {
+	if len(t.pending) == 0 {
+		return
+	}
+
+	wait := t.pending[0].release.Sub(now)
+	if t.timer == nil {
+		t.timer = time.AfterFunc(wait, t.releaseDue)
+		return
+	}
+	t.timer.Reset(wait)
+}
+
+// releaseDue sends the queued messages whose release time has passed, in order.
+func (t *throttle) releaseDue() {
+	t.releaseMu.Lock()
+	defer t.releaseMu.Unlock()
+
+	for {
+		msg := t.nextDue()
+		if msg == nil {
+			return
+		}
+
+		// The release may wait for space in the send queue, see config.OutboundQueuePolicy.
+		t.release(msg)
+	}
+}
+
+// nextDue removes and returns the first queued message if its release time has passed,
+// or schedules the timer for it and returns nil.
+func (t *throttle) nextDue() *Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if len(t.pending) == 0 || t.pending[0].release.After(now) {
+		t.schedule(now)
+		return nil
+	}
+
+	msg := t.pending[0].msg
+	t.pending = t.pending[1:]
+	return msg
+}
+
+// stop drops the queued messages and returns the number dropped.
+func (t *throttle) stop() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	dropped := len(t.pending)
+	t.pending = nil
+	return dropped
+}
+
+// queued returns the number of messages held by the QUEUE policy.
+func (t *throttle) queued() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func throttleTestMessage(msgType string) *Message {
+	msg := NewMessage()
+	msg.Header.SetField(tagMsgType, FIXString(msgType))
+	return msg
+}
+
+func clOrdID(t *testing.T, msg *Message) string {
+	value, err := msg.Body.GetString(Tag(11))
+	require.Nil(t, err)
+	return value
+}
+
+type throttleTestClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func newTestThrottle(settings throttleSettings, release func(*Message)) (*throttle, *throttleTestClock) {
+	clock := &throttleTestClock{now: time.Now()}
+	t := newThrottle(settings, release)
+	t.now = func() time.Time { return clock.now }
+	t.sleep = func(d time.Duration) {
+		clock.slept = append(clock.slept, d)
+		clock.now = clock.now.Add(d)
+	}
+	return t, clock
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(2, time.Second, now)
+
+	assert.True(t, b.take(now))
+	assert.True(t, b.take(now))
+	assert.False(t, b.take(now))
+	assert.False(t, b.take(now.Add(400*time.Millisecond)))
+	assert.True(t, b.take(now.Add(500*time.Millisecond)))
+
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now.Add(500*time.Millisecond)))
+	assert.Equal(t, time.Second, b.reserve(now.Add(500*time.Millisecond)))
+
+	b.refill(now.Add(time.Hour))
+	assert.Equal(t, 2.0, b.tokens, "tokens are capped at capacity")
+}
+
+func TestThrottleDisabled(t *testing.T) {
+	th, _ := newTestThrottle(throttleSettings{interval: time.Second}, nil)
+	for i := 0; i < 100; i++ {
+		queued, err := th.admit(throttleTestMessage("D"))
+		require.Nil(t, err)
+		require.False(t, queued)
+	}
+}
+
+func TestThrottleReject(t *testing.T) {
+	th, clock := newTestThrottle(throttleSettings{messages: 2, interval: time.Second}, nil)
+
+	for i := 0; i < 2; i++ {
+		queued, err := th.admit(throttleTestMessage("D"))
+		assert.Nil(t, err)
+		assert.False(t, queued)
+	}
+
+	_, err := th.admit(throttleTestMessage("F"))
+	assert.Equal(t, ErrThrottled, err)
+
+	_, err = th.admit(throttleTestMessage(string(msgTypeHeartbeat)))
+	assert.Nil(t, err, "admin messages bypass the throttle")
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	_, err = th.admit(throttleTestMessage("D"))
+	assert.Nil(t, err)
+}
+
+func TestThrottleMsgTypes(t *testing.T) {
+	th, _ := newTestThrottle(throttleSettings{messages: 1, interval: time.Second, msgTypes: []string{"D", "F"}}, nil)
+
+	_, err := th.admit(throttleTestMessage("D"))
+	assert.Nil(t, err)
+	_, err = th.admit(throttleTestMessage("D"))
+	assert.Equal(t, ErrThrottled, err)
+
+	_, err = th.admit(throttleTestMessage("F"))
+	assert.Nil(t, err, "each MsgType has its own limit")
+
+	for i := 0; i < 10; i++ {
+		_, err = th.admit(throttleTestMessage("V"))
+		assert.Nil(t, err, "MsgTypes not listed are not throttled")
+	}
+}
+
+func TestThrottleBlock(t *testing.T) {
+	th, clock := newTestThrottle(throttleSettings{messages: 2, interval: time.Second, policy: throttleBlock}, nil)
+
+	for i := 0; i < 4; i++ {
+		queued, err := th.admit(throttleTestMessage("D"))
+		assert.Nil(t, err)
+		assert.False(t, queued)
+	}
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.slept)
+}
+
+func TestThrottleQueue(t *testing.T) {
+	var mu sync.Mutex
+	var released []*Message
+	th, clock := newTestThrottle(throttleSettings{messages: 1, interval: time.Second, policy: throttleQueue, queueLimit: 2}, func(msg *Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		released = append(released, msg)
+	})
+
+	first := throttleTestMessage("D")
+	queued, err := th.admit(first)
+	require.Nil(t, err)
+	assert.False(t, queued)
+
+	// The queued messages are copies, msg is reused.
+	msg := throttleTestMessage("D")
+	for _, clOrdID := range []string{"second", "third"} {
+		msg.Body.SetField(Tag(11), FIXString(clOrdID))
+		queued, err = th.admit(msg)
+		require.Nil(t, err)
+		assert.True(t, queued)
+	}
+	msg.Body.SetField(Tag(11), FIXString("changed"))
+	assert.Equal(t, 2, th.queued())
+
+	// The queue is full.
+	queued, err = th.admit(throttleTestMessage("D"))
+	assert.Equal(t, ErrThrottled, err)
+	assert.False(t, queued)
+	assert.Equal(t, 2, th.queued())
+
+	clock.now = clock.now.Add(time.Second)
+	th.releaseDue()
+	require.Len(t, released, 1)
+	assert.Equal(t, "second", clOrdID(t, released[0]))
+
+	clock.now = clock.now.Add(time.Second)
+	th.releaseDue()
+	require.Len(t, released, 2)
+	assert.Equal(t, "third", clOrdID(t, released[1]))
+	assert.Zero(t, th.queued())
+
+	queued, err = th.admit(throttleTestMessage("D"))
+	require.Nil(t, err)
+	assert.True(t, queued)
+	assert.Equal(t, 1, th.stop())
+	assert.Zero(t, th.queued())
+}
+
+func TestThrottleQueueTimer(t *testing.T) {
+	released := make(chan *Message, 2)
+	th := newThrottle(throttleSettings{messages: 1, interval: 20 * time.Millisecond, policy: throttleQueue, queueLimit: 2}, func(msg *Message) {
+		released <- msg
+	})
+	defer th.stop()
+
+	clOrdIDs := []string{"first", "second", "third"}
+	for _, id := range clOrdIDs {
+		msg := throttleTestMessage("D")
+		msg.Body.SetField(Tag(11), FIXString(id))
+		_, err := th.admit(msg)
+		require.Nil(t, err)
+	}
+
+	for _, id := range clOrdIDs[1:] {
+		select {
+		case m := <-released:
+			assert.Equal(t, id, clOrdID(t, m))
+		case <-time.After(time.Second):
+			t.Fatal("queued message was not released")
+		}
+	}
+}
@@ -21,17 +21,25 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/quickfixgo/quickfix/config"
 )
 
 func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
+	tlsConfig, _, err := loadTLSConfigWithCertificate(settings)
+	return tlsConfig, err
+}
+
+// loadTLSConfigWithCertificate returns the TLS config for settings, and the loader of its certificate
+// if the certificate is read from files and reloaded when they change.
+func loadTLSConfigWithCertificate(settings *SessionSettings) (*tls.Config, *certificateLoader, error) {
 	var err error
 	allowSkipClientCerts := false
 	if settings.HasSetting(config.SocketUseSSL) {
 		allowSkipClientCerts, err = settings.BoolSetting(config.SocketUseSSL)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -39,7 +47,7 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 	if settings.HasSetting(config.SocketServerName) {
 		serverName, err = settings.Setting(config.SocketServerName)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -47,16 +55,17 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 	if settings.HasSetting(config.SocketInsecureSkipVerify) {
 		insecureSkipVerify, err = settings.BoolSetting(config.SocketInsecureSkipVerify)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if !settings.HasSetting(config.SocketPrivateKeyFile) &&
 		!settings.HasSetting(config.SocketCertificateFile) &&
 		!settings.HasSetting(config.SocketPrivateKeyBytes) &&
-		!settings.HasSetting(config.SocketCertificateBytes) {
+		!settings.HasSetting(config.SocketCertificateBytes) &&
+		!settings.HasSetting(config.SocketPKCS12File) {
 		if !allowSkipClientCerts {
-			return nil, nil
+			return nil, nil, nil
 		}
 	}
 
@@ -64,88 +73,127 @@ func loadTLSConfig(settings *SessionSettings) (*tls.Config, error) {
 	tlsConfig.ServerName = serverName
 	tlsConfig.InsecureSkipVerify = insecureSkipVerify
 	setMinVersionExplicit(settings, tlsConfig)
+	if err = setMaxVersion(settings, tlsConfig); err != nil {
+		return nil, nil, err
+	}
+	if err = setCipherSuites(settings, tlsConfig); err != nil {
+		return nil, nil, err
+	}
+
+	var privateKeyPassword string
+	if settings.HasSetting(config.SocketPrivateKeyPassword) {
+		if privateKeyPassword, err = settings.Setting(config.SocketPrivateKeyPassword); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var loader *certificateLoader
+	if settings.HasSetting(config.SocketPKCS12File) {
+		var pkcs12File, pkcs12Password string
+		if pkcs12File, err = settings.Setting(config.SocketPKCS12File); err != nil {
+			return nil, nil, err
+		}
 
-	if settings.HasSetting(config.SocketPrivateKeyFile) || settings.HasSetting(config.SocketCertificateFile) {
+		if settings.HasSetting(config.SocketPKCS12Password) {
+			if pkcs12Password, err = settings.Setting(config.SocketPKCS12Password); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		loader, err = newCertificateLoader([]string{pkcs12File}, func() (tls.Certificate, error) {
+			return loadPKCS12(pkcs12File, pkcs12Password)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load PKCS#12 bundle: %w", err)
+		}
+	} else if settings.HasSetting(config.SocketPrivateKeyFile) || settings.HasSetting(config.SocketCertificateFile) {
 
 		var privateKeyFile string
 		var certificateFile string
 
 		privateKeyFile, err = settings.Setting(config.SocketPrivateKeyFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		certificateFile, err = settings.Setting(config.SocketCertificateFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		tlsConfig.Certificates = make([]tls.Certificate, 1)
-
-		if tlsConfig.Certificates[0], err = tls.LoadX509KeyPair(certificateFile, privateKeyFile); err != nil {
-			return nil, fmt.Errorf("failed to load key pair: %w", err)
+		loader, err = newCertificateLoader([]string{certificateFile, privateKeyFile}, func() (tls.Certificate, error) {
+			return loadX509KeyPair(certificateFile, privateKeyFile, privateKeyPassword)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load key pair: %w", err)
 		}
 	} else if settings.HasSetting(config.SocketPrivateKeyBytes) || settings.HasSetting(config.SocketCertificateBytes) {
 		privateKeyBytes, err := settings.RawSetting(config.SocketPrivateKeyBytes)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		certificateBytes, err := settings.RawSetting(config.SocketCertificateBytes)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		tlsConfig.Certificates = make([]tls.Certificate, 1)
 
-		certificate, err := tls.X509KeyPair(certificateBytes, privateKeyBytes)
+		certificate, err := x509KeyPair(certificateBytes, privateKeyBytes, privateKeyPassword)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse key pair: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse key pair: %w", err)
 		}
 
 		tlsConfig.Certificates[0] = certificate
 	}
 
+	if loader != nil {
+		// Certificates is left empty, a server only asks GetCertificate for clients that send no SNI if it is.
+		tlsConfig.GetCertificate = loader.getCertificate
+		tlsConfig.GetClientCertificate = loader.getClientCertificate
+	}
+
 	if !allowSkipClientCerts {
 		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
 	if !settings.HasSetting(config.SocketCAFile) && !settings.HasSetting(config.SocketCABytes) {
-		return tlsConfig, nil
+		return tlsConfig, loader, nil
 	}
 
 	certPool := x509.NewCertPool()
 	if settings.HasSetting(config.SocketCAFile) {
 		caFile, err := settings.Setting(config.SocketCAFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		pem, err := os.ReadFile(caFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+			return nil, nil, fmt.Errorf("failed to read CA bundle: %w", err)
 		}
 
 		if !certPool.AppendCertsFromPEM(pem) {
 			err = fmt.Errorf("failed to parse %v", caFile)
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		caBytes, err := settings.RawSetting(config.SocketCABytes)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if !certPool.AppendCertsFromPEM(caBytes) {
 			err = errors.New("failed to parse CA bundle from raw bytes")
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	tlsConfig.RootCAs = certPool
 	tlsConfig.ClientCAs = certPool
 
-	return tlsConfig, nil
+	return tlsConfig, loader, nil
 }
 
 // defaultTLSConfig brought to you by https://github.com/gtank/cryptopasta/
@@ -180,6 +228,149 @@ func setMinVersionExplicit(settings *SessionSettings, tlsConfig *tls.Config) {
 			tlsConfig.MinVersion = tls.VersionTLS11
 		case "TLS12":
 			tlsConfig.MinVersion = tls.VersionTLS12
+		case "TLS13":
+			tlsConfig.MinVersion = tls.VersionTLS13
+		}
+	}
+}
+
+func setMaxVersion(settings *SessionSettings, tlsConfig *tls.Config) error {
+	if !settings.HasSetting(config.SocketMaximumTLSVersion) {
+		return nil
+	}
+
+	maxVersion, err := settings.Setting(config.SocketMaximumTLSVersion)
+	if err != nil {
+		return err
+	}
+
+	switch maxVersion {
+	case "TLS10":
+		tlsConfig.MaxVersion = tls.VersionTLS10
+	case "TLS11":
+		tlsConfig.MaxVersion = tls.VersionTLS11
+	case "TLS12":
+		tlsConfig.MaxVersion = tls.VersionTLS12
+	case "TLS13":
+		tlsConfig.MaxVersion = tls.VersionTLS13
+	default:
+		return IncorrectFormatForSetting{Setting: config.SocketMaximumTLSVersion, Value: []byte(maxVersion)}
+	}
+	return nil
+}
+
+func setCipherSuites(settings *SessionSettings, tlsConfig *tls.Config) error {
+	if !settings.HasSetting(config.SocketCipherSuites) {
+		return nil
+	}
+
+	cipherSuites, err := settings.Setting(config.SocketCipherSuites)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		ids[suite.Name] = suite.ID
+	}
+
+	for _, name := range strings.Split(cipherSuites, ",") {
+		id, ok := ids[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown cipher suite %q in %v", strings.TrimSpace(name), config.SocketCipherSuites)
 		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
 	}
+	return nil
+}
+
+type certificateBinding int
+
+const (
+	certificateBindingNone certificateBinding = iota
+	certificateBindingCN
+	certificateBindingSAN
+	certificateBindingAny
+)
+
+// certificateIdentityPolicy binds client certificates to CompIDs, see config.SocketCertificateCompIDBinding.
+type certificateIdentityPolicy struct {
+	binding certificateBinding
+	// Names accepted in place of the CompID, optional.
+	identities []string
+}
+
+func parseCertificateIdentityPolicy(settings *SessionSettings) (policy certificateIdentityPolicy, err error) {
+	if settings.HasSetting(config.SocketCertificateCompIDBinding) {
+		var binding string
+		if binding, err = settings.Setting(config.SocketCertificateCompIDBinding); err != nil {
+			return
+		}
+
+		switch binding {
+		case "NONE":
+			policy.binding = certificateBindingNone
+		case "CN":
+			policy.binding = certificateBindingCN
+		case "SAN":
+			policy.binding = certificateBindingSAN
+		case "ANY":
+			policy.binding = certificateBindingAny
+		default:
+			err = IncorrectFormatForSetting{Setting: config.SocketCertificateCompIDBinding, Value: []byte(binding)}
+			return
+		}
+	}
+
+	if settings.HasSetting(config.SocketCertificateIdentity) {
+		var identities string
+		if identities, err = settings.Setting(config.SocketCertificateIdentity); err != nil {
+			return
+		}
+
+		for _, identity := range strings.Split(identities, ",") {
+			if identity = strings.TrimSpace(identity); identity != "" {
+				policy.identities = append(policy.identities, identity)
+			}
+		}
+
+		if len(policy.identities) == 0 {
+			err = IncorrectFormatForSetting{Setting: config.SocketCertificateIdentity, Value: []byte(identities)}
+		}
+	}
+
+	return
+}
+
+// check returns an error unless the client certificate in certs is issued to compID, or to one of the identities of the policy.
+func (p certificateIdentityPolicy) check(certs []*x509.Certificate, compID string) error {
+	if p.binding == certificateBindingNone {
+		return nil
+	}
+
+	if len(certs) == 0 {
+		return errors.New("no client certificate presented")
+	}
+
+	identities := p.identities
+	if len(identities) == 0 {
+		identities = []string{compID}
+	}
+
+	cert := certs[0]
+	for _, identity := range identities {
+		if p.binding != certificateBindingSAN && cert.Subject.CommonName == identity {
+			return nil
+		}
+
+		if p.binding != certificateBindingCN {
+			for _, name := range cert.DNSNames {
+				if name == identity {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("client certificate %q does not match %v", cert.Subject.String(), strings.Join(identities, ","))
 }
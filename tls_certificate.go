@@ -0,0 +1,202 @@
+// Copyright (c) quickfixengine.org  All rights reserved.
+//
+// This file may be distributed under the terms of the quickfixengine.org
+// license as defined by quickfixengine.org and appearing in the file
+// LICENSE included in the packaging of this file.
+//
+// This file is provided AS IS with NO WARRANTY OF ANY KIND, INCLUDING
+// THE WARRANTY OF DESIGN, MERCHANTABILITY AND FITNESS FOR A
+// PARTICULAR PURPOSE.
+//
+// See http://www.quickfixengine.org/LICENSE for licensing information.
+//
+// Contact ask@quickfixengine.org if any conditions of this licensing
+// are not clear to you.
+
+package quickfix
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// certificateLoader holds a certificate read from files, reloading it when the files change.
+type certificateLoader struct {
+	files []string
+	load  func() (tls.Certificate, error)
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+	// Modification time and size of files when cert was loaded.
+	versions []fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+func newCertificateLoader(files []string, load func() (tls.Certificate, error)) (*certificateLoader, error) {
+	l := &certificateLoader{files: files, load: load}
+	if err := l.reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// reload loads the certificate from the files, keeping the current certificate if it fails.
+func (l *certificateLoader) reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.reloadLocked(l.stat())
+}
+
+func (l *certificateLoader) reloadLocked(versions []fileVersion) error {
+	cert, err := l.load()
+	if err != nil {
+		return err
+	}
+
+	l.cert = &cert
+	l.versions = versions
+	return nil
+}
+
+// stat returns the versions of the files, zero for files that cannot be read.
+func (l *certificateLoader) stat() []fileVersion {
+	versions := make([]fileVersion, len(l.files))
+	for i, file := range l.files {
+		if info, err := os.Stat(file); err == nil {
+			versions[i] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return versions
+}
+
+// current returns the certificate, reloading it first if the files changed since it was loaded.
+// A certificate that fails to load, e.g. while its files are being replaced, is retried on the next call.
+func (l *certificateLoader) current() *tls.Certificate {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	versions := l.stat()
+	for i := range versions {
+		if versions[i] != l.versions[i] {
+			_ = l.reloadLocked(versions)
+			break
+		}
+	}
+	return l.cert
+}
+
+func (l *certificateLoader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return l.current(), nil
+}
+
+func (l *certificateLoader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return l.current(), nil
+}
+
+// loadX509KeyPair reads a PEM encoded certificate and private key from files, see x509KeyPair.
+func loadX509KeyPair(certificateFile, privateKeyFile, password string) (tls.Certificate, error) {
+	certificatePEM, err := os.ReadFile(certificateFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	privateKeyPEM, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return x509KeyPair(certificatePEM, privateKeyPEM, password)
+}
+
+// x509KeyPair parses a PEM encoded certificate and private key, decrypting the key with password if it is encrypted.
+func x509KeyPair(certificatePEM, privateKeyPEM []byte, password string) (tls.Certificate, error) {
+	privateKeyPEM, err := decryptPrivateKey(privateKeyPEM, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certificatePEM, privateKeyPEM)
+}
+
+// decryptPrivateKey returns the first private key of keyPEM in plain PEM, decrypting a PKCS#8 or a legacy
+// OpenSSL encrypted key with password. Keys that are not encrypted are returned unchanged.
+func decryptPrivateKey(keyPEM []byte, password string) ([]byte, error) {
+	for rest := keyPEM; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return keyPEM, nil
+		}
+
+		switch {
+		case block.Type == "ENCRYPTED PRIVATE KEY":
+			if password == "" {
+				return nil, errors.New("private key is encrypted, SocketPrivateKeyPassword is required")
+			}
+
+			key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(password))
+			if err != nil {
+				return nil, err
+			}
+
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+
+		//nolint:staticcheck // SA1019 legacy encrypted keys are still issued by some CAs
+		case x509.IsEncryptedPEMBlock(block):
+			if password == "" {
+				return nil, errors.New("private key is encrypted, SocketPrivateKeyPassword is required")
+			}
+
+			//nolint:staticcheck // SA1019 legacy encrypted keys are still issued by some CAs
+			der, err := x509.DecryptPEMBlock(block, []byte(password))
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			return keyPEM, nil
+		}
+	}
+}
+
+// loadPKCS12 reads the certificate, its chain and its private key from a PKCS#12 bundle.
+func loadPKCS12(file, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	// The certificate of the key comes first, followed by its chain.
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})
+	for _, cert := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+
+	return tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
@@ -16,10 +16,21 @@
 package quickfix
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/quickfixgo/quickfix/config"
@@ -90,7 +101,7 @@ func (s *TLSTestSuite) TestLoadTLSNoCA() {
 	s.Nil(err)
 	s.NotNil(tlsConfig)
 
-	s.Len(tlsConfig.Certificates, 1)
+	s.NotNil(s.certificate(tlsConfig))
 	s.Nil(tlsConfig.RootCAs)
 	s.Nil(tlsConfig.ClientCAs)
 	s.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
@@ -115,7 +126,7 @@ func (s *TLSTestSuite) TestLoadTLSWithCA() {
 	s.Nil(err)
 	s.NotNil(tlsConfig)
 
-	s.Len(tlsConfig.Certificates, 1)
+	s.NotNil(s.certificate(tlsConfig))
 	s.NotNil(tlsConfig.RootCAs)
 	s.NotNil(tlsConfig.ClientCAs)
 	s.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
@@ -201,7 +212,7 @@ func (s *TLSTestSuite) TestInsecureSkipVerifyAndCerts() {
 	s.NotNil(tlsConfig)
 
 	s.True(tlsConfig.InsecureSkipVerify)
-	s.Len(tlsConfig.Certificates, 1)
+	s.NotNil(s.certificate(tlsConfig))
 }
 
 func (s *TLSTestSuite) TestMinimumTLSVersion() {
@@ -278,7 +289,7 @@ func (s *TLSTestSuite) TestLoadTLSBytesNoCA() {
 	s.NoError(err)
 	s.NotNil(tlsConfig)
 
-	s.Len(tlsConfig.Certificates, 1)
+	s.NotNil(s.certificate(tlsConfig))
 	s.Nil(tlsConfig.RootCAs)
 	s.Nil(tlsConfig.ClientCAs)
 	s.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
@@ -303,7 +314,7 @@ func (s *TLSTestSuite) TestLoadTLSBytesWithCA() {
 	s.Nil(err)
 	s.NotNil(tlsConfig)
 
-	s.Len(tlsConfig.Certificates, 1)
+	s.NotNil(s.certificate(tlsConfig))
 	s.NotNil(tlsConfig.RootCAs)
 	s.NotNil(tlsConfig.ClientCAs)
 	s.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
@@ -331,3 +342,267 @@ func (s *TLSTestSuite) TestServerNameWithCertsFromBytes() {
 	s.NotNil(tlsConfig)
 	s.Equal("DummyServerNameWithCerts", tlsConfig.ServerName)
 }
+
+func (s *TLSTestSuite) TestParseCertificateIdentityPolicy() {
+	policy, err := parseCertificateIdentityPolicy(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal(certificateIdentityPolicy{}, policy)
+
+	s.settings.GlobalSettings().Set(config.SocketCertificateCompIDBinding, "SAN")
+	s.settings.GlobalSettings().Set(config.SocketCertificateIdentity, "client1.example.com, client1")
+	policy, err = parseCertificateIdentityPolicy(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal(certificateIdentityPolicy{binding: certificateBindingSAN, identities: []string{"client1.example.com", "client1"}}, policy)
+
+	s.settings.GlobalSettings().Set(config.SocketCertificateIdentity, " , ")
+	_, err = parseCertificateIdentityPolicy(s.settings.GlobalSettings())
+	s.NotNil(err)
+
+	s.settings.GlobalSettings().Set(config.SocketCertificateCompIDBinding, "EMAIL")
+	_, err = parseCertificateIdentityPolicy(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestCertificateIdentityPolicyCheck() {
+	certs := []*x509.Certificate{
+		{Subject: pkix.Name{CommonName: "CLIENT1"}, DNSNames: []string{"client1.example.com"}},
+		{Subject: pkix.Name{CommonName: "CLIENT2"}},
+	}
+
+	var tests = []struct {
+		policy certificateIdentityPolicy
+		compID string
+		ok     bool
+	}{
+		{certificateIdentityPolicy{}, "CLIENT2", true},
+		{certificateIdentityPolicy{binding: certificateBindingCN}, "CLIENT1", true},
+		{certificateIdentityPolicy{binding: certificateBindingCN}, "CLIENT2", false},
+		{certificateIdentityPolicy{binding: certificateBindingCN}, "client1.example.com", false},
+		{certificateIdentityPolicy{binding: certificateBindingSAN}, "client1.example.com", true},
+		{certificateIdentityPolicy{binding: certificateBindingSAN}, "CLIENT1", false},
+		{certificateIdentityPolicy{binding: certificateBindingAny}, "CLIENT1", true},
+		{certificateIdentityPolicy{binding: certificateBindingAny}, "client1.example.com", true},
+		{certificateIdentityPolicy{binding: certificateBindingCN, identities: []string{"OTHER", "CLIENT1"}}, "C1", true},
+		{certificateIdentityPolicy{binding: certificateBindingCN, identities: []string{"OTHER"}}, "CLIENT1", false},
+	}
+
+	for _, test := range tests {
+		err := test.policy.check(certs, test.compID)
+		s.Equal(test.ok, err == nil, "%+v %v", test.policy, test.compID)
+	}
+
+	s.NotNil(certificateIdentityPolicy{binding: certificateBindingAny}.check(nil, "CLIENT1"))
+}
+
+func (s *TLSTestSuite) TestLoadTLSEncryptedPrivateKey() {
+	for _, keyFile := range []string{"_test_data/localhost-encrypted.key", "_test_data/localhost-legacy-encrypted.key"} {
+		s.SetupTest()
+		s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, keyFile)
+		s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+
+		_, err := loadTLSConfig(s.settings.GlobalSettings())
+		s.NotNil(err, keyFile)
+
+		s.settings.GlobalSettings().Set(config.SocketPrivateKeyPassword, "wrong")
+		_, err = loadTLSConfig(s.settings.GlobalSettings())
+		s.NotNil(err, keyFile)
+
+		s.settings.GlobalSettings().Set(config.SocketPrivateKeyPassword, "password")
+		tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+		s.Nil(err, keyFile)
+		s.NotNil(s.certificate(tlsConfig))
+
+		keyBytes, err := os.ReadFile(keyFile)
+		s.Require().NoError(err)
+
+		s.SetupTest()
+		s.settings.GlobalSettings().Set(config.SocketPrivateKeyBytes, string(keyBytes))
+		s.settings.GlobalSettings().Set(config.SocketCertificateBytes, string(s.CertificateBytes))
+		s.settings.GlobalSettings().Set(config.SocketPrivateKeyPassword, "password")
+		tlsConfig, err = loadTLSConfig(s.settings.GlobalSettings())
+		s.Nil(err, keyFile)
+		s.NotNil(s.certificate(tlsConfig))
+	}
+}
+
+func (s *TLSTestSuite) TestLoadTLSPKCS12() {
+	// localhost.p12 uses the legacy 3DES encryption and SHA-1 MAC, localhost-aes.p12 the OpenSSL 3 defaults,
+	// AES-256 with PBKDF2 and a SHA-256 MAC.
+	for _, file := range []string{"_test_data/localhost.p12", "_test_data/localhost-aes.p12"} {
+		s.settings.GlobalSettings().Set(config.SocketPKCS12File, file)
+		s.settings.GlobalSettings().Set(config.SocketPKCS12Password, "wrong")
+		_, err := loadTLSConfig(s.settings.GlobalSettings())
+		s.NotNil(err, file)
+
+		s.settings.GlobalSettings().Set(config.SocketPKCS12Password, "password")
+		tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+		s.Require().Nil(err, file)
+		cert := s.certificate(tlsConfig)
+		s.Require().NotNil(cert, file)
+
+		// The bundle holds the certificate and its CA.
+		s.Len(cert.Certificate, 2, file)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		s.Require().Nil(err, file)
+		s.Equal("localhost", leaf.Subject.CommonName, file)
+		s.Equal(tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth, file)
+	}
+}
+
+func (s *TLSTestSuite) TestMaximumTLSVersion() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Zero(tlsConfig.MaxVersion)
+
+	s.settings.GlobalSettings().Set(config.SocketMinimumTLSVersion, "TLS13")
+	s.settings.GlobalSettings().Set(config.SocketMaximumTLSVersion, "TLS13")
+	tlsConfig, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal(uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	s.Equal(uint16(tls.VersionTLS13), tlsConfig.MaxVersion)
+
+	s.settings.GlobalSettings().Set(config.SocketMaximumTLSVersion, "SSL30")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestCipherSuites() {
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, s.PrivateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, s.CertificateFile)
+	s.settings.GlobalSettings().Set(config.SocketCipherSuites, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+
+	tlsConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Nil(err)
+	s.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, tlsConfig.CipherSuites)
+
+	s.settings.GlobalSettings().Set(config.SocketCipherSuites, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_MADE_UP")
+	_, err = loadTLSConfig(s.settings.GlobalSettings())
+	s.NotNil(err)
+}
+
+func (s *TLSTestSuite) TestLoadTLSReloadsCertificate() {
+	dir := s.T().TempDir()
+	certificateFile := filepath.Join(dir, "client.crt")
+	privateKeyFile := filepath.Join(dir, "client.key")
+	s.Require().NoError(os.WriteFile(certificateFile, s.CertificateBytes, 0600))
+	s.Require().NoError(os.WriteFile(privateKeyFile, s.PrivateKeyBytes, 0600))
+
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, privateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, certificateFile)
+	tlsConfig, certificates, err := loadTLSConfigWithCertificate(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+	s.Require().NotNil(certificates)
+
+	s.Empty(tlsConfig.Certificates)
+	loaded, err := tlsConfig.GetCertificate(nil)
+	s.Require().Nil(err)
+
+	// A half written renewal keeps the current certificate.
+	renewedCertificate, renewedKey := generateCertificate(s.T(), "renewed")
+	s.Require().NoError(os.WriteFile(certificateFile, renewedCertificate, 0600))
+	cert, err := tlsConfig.GetClientCertificate(nil)
+	s.Require().Nil(err)
+	s.Equal(loaded.Certificate, cert.Certificate)
+
+	s.Require().NoError(os.WriteFile(privateKeyFile, renewedKey, 0600))
+	for _, get := range []func() (*tls.Certificate, error){
+		func() (*tls.Certificate, error) { return tlsConfig.GetCertificate(nil) },
+		func() (*tls.Certificate, error) { return tlsConfig.GetClientCertificate(nil) },
+	} {
+		cert, err = get()
+		s.Require().Nil(err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		s.Require().Nil(err)
+		s.Equal("renewed", leaf.Subject.CommonName)
+	}
+
+	s.Require().NoError(os.WriteFile(privateKeyFile, []byte("garbage"), 0600))
+	s.NotNil(certificates.reload())
+	s.Require().NoError(os.WriteFile(privateKeyFile, renewedKey, 0600))
+	s.Nil(certificates.reload())
+}
+
+func (s *TLSTestSuite) TestLoadTLSReloadsCertificateWithoutServerName() {
+	dir := s.T().TempDir()
+	certificateFile := filepath.Join(dir, "localhost.crt")
+	privateKeyFile := filepath.Join(dir, "localhost.key")
+	s.Require().NoError(os.WriteFile(certificateFile, s.CertificateBytes, 0600))
+	s.Require().NoError(os.WriteFile(privateKeyFile, s.PrivateKeyBytes, 0600))
+
+	s.settings.GlobalSettings().Set(config.SocketPrivateKeyFile, privateKeyFile)
+	s.settings.GlobalSettings().Set(config.SocketCertificateFile, certificateFile)
+	s.settings.GlobalSettings().Set(config.SocketInsecureSkipVerify, "Y")
+	serverConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+	// The certificates are self-signed, the server only checks one is presented.
+	serverConfig.ClientAuth = tls.RequireAnyClientCert
+
+	clientConfig, err := loadTLSConfig(s.settings.GlobalSettings())
+	s.Require().Nil(err)
+	s.Empty(clientConfig.ServerName)
+
+	// handshake returns the common names of the certificates the server and the client presented.
+	handshake := func() (server, client string) {
+		serverConn, clientConn := net.Pipe()
+		defer serverConn.Close()
+		defer clientConn.Close()
+
+		tlsServer, tlsClient := tls.Server(serverConn, serverConfig), tls.Client(clientConn, clientConfig)
+		done := make(chan error, 1)
+		go func() { done <- tlsServer.Handshake() }()
+		s.Require().NoError(tlsClient.Handshake())
+		s.Require().NoError(<-done)
+
+		return tlsClient.ConnectionState().PeerCertificates[0].Subject.CommonName,
+			tlsServer.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	server, client := handshake()
+	s.Equal("localhost", server)
+	s.Equal("localhost", client)
+
+	renewedCertificate, renewedKey := generateCertificate(s.T(), "renewed")
+	s.Require().NoError(os.WriteFile(certificateFile, renewedCertificate, 0600))
+	s.Require().NoError(os.WriteFile(privateKeyFile, renewedKey, 0600))
+
+	server, client = handshake()
+	s.Equal("renewed", server)
+	s.Equal("renewed", client)
+}
+
+// certificate returns the certificate tlsConfig presents, or nil if it has none.
+func (s *TLSTestSuite) certificate(tlsConfig *tls.Config) *tls.Certificate {
+	if len(tlsConfig.Certificates) > 0 {
+		return &tlsConfig.Certificates[0]
+	}
+	if tlsConfig.GetCertificate == nil {
+		return nil
+	}
+
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{})
+	s.Require().NoError(err)
+	return cert
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate for commonName and its private key.
+func generateCertificate(t *testing.T, commonName string) (certificatePEM, privateKeyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}